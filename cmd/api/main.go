@@ -9,6 +9,8 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Embedded so recurring maintenance windows can name any timezone on hosts without zoneinfo
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,6 +22,7 @@ import (
 	"github.com/wukong0111/go-banks/internal/logger"
 	"github.com/wukong0111/go-banks/internal/middleware"
 	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/scheduler"
 	"github.com/wukong0111/go-banks/internal/secrets"
 	"github.com/wukong0111/go-banks/internal/services"
 )
@@ -83,6 +86,15 @@ func run() error {
 	bankService := services.NewBankService(bankRepo)
 	bankHandler := handlers.NewBankHandler(bankService)
 
+	// Initialize delta sync dependencies
+	changeRepo := repository.NewPostgresChangeRepository(dbPool)
+	changeService := services.NewChangeService(changeRepo)
+	changeHandler := handlers.NewChangeHandler(changeService)
+
+	// Initialize IBAN resolution dependencies
+	ibanResolverService := services.NewIBANResolverService(bankRepo)
+	ibanResolverHandler := handlers.NewIBANResolverHandler(ibanResolverService)
+
 	// Initialize bank creation dependencies
 	bankWriter := repository.NewPostgresBankWriter(dbPool)
 	bankCreatorService := services.NewBankCreatorService(bankWriter)
@@ -92,6 +104,50 @@ func run() error {
 	bankUpdaterService := services.NewBankUpdaterService(bankWriter, bankRepo)
 	bankUpdaterHandler := handlers.NewBankUpdaterHandler(bankUpdaterService)
 
+	// Initialize patch dependencies
+	bankPatcherService := services.NewBankPatcherService(bankWriter, bankRepo)
+	bankPatcherHandler := handlers.NewBankPatcherHandler(bankPatcherService)
+
+	// Initialize bulk import dependencies
+	bankImporterService := services.NewBankImporterService(bankWriter, bankRepo)
+	bankImporterHandler := handlers.NewBankImporterHandler(bankImporterService)
+
+	// Initialize bank deletion dependencies
+	bankDeleterService := services.NewBankDeleterService(bankWriter)
+	bankDeleterHandler := handlers.NewBankDeleterHandler(bankDeleterService)
+
+	// Initialize audit history dependencies
+	auditRepo := repository.NewPostgresAuditRepository(dbPool)
+	bankHistoryService := services.NewBankHistoryService(auditRepo, bankRepo)
+	bankHistoryHandler := handlers.NewBankHistoryHandler(bankHistoryService)
+
+	// Initialize revision dependencies
+	bankRevisionRepo := repository.NewPostgresBankRevisionRepository(dbPool)
+	bankReverterService := services.NewBankReverterService(bankWriter, bankRepo, bankRevisionRepo)
+	bankReverterHandler := handlers.NewBankReverterHandler(bankReverterService)
+
+	// Initialize scheduled change dependencies
+	scheduledChangeRepo := repository.NewPostgresScheduledChangeRepository(dbPool)
+	scheduledChangeService := services.NewScheduledChangeService(scheduledChangeRepo, bankRepo)
+	scheduledChangeHandler := handlers.NewScheduledChangeHandler(scheduledChangeService)
+
+	// Initialize environment config dependencies
+	environmentConfigService := services.NewEnvironmentConfigService(bankWriter, bankRepo)
+	environmentConfigHandler := handlers.NewEnvironmentConfigHandler(environmentConfigService)
+
+	// Initialize environment promotion dependencies
+	environmentPromotionService := services.NewEnvironmentPromotionService(bankWriter, bankRepo)
+	environmentPromotionHandler := handlers.NewEnvironmentPromotionHandler(environmentPromotionService)
+
+	// Initialize report dependencies
+	environmentDriftService := services.NewEnvironmentDriftService(bankRepo)
+	environmentDriftHandler := handlers.NewEnvironmentDriftHandler(environmentDriftService)
+
+	// Initialize maintenance window dependencies
+	maintenanceWindowWriter := repository.NewPostgresMaintenanceWindowWriter(dbPool)
+	maintenanceWindowService := services.NewMaintenanceWindowService(maintenanceWindowWriter, bankRepo)
+	maintenanceWindowHandler := handlers.NewMaintenanceWindowHandler(maintenanceWindowService)
+
 	// Initialize bank filters dependencies
 	bankFiltersService := services.NewBankFiltersService(bankRepo)
 	bankFiltersHandler := handlers.NewBankFiltersHandler(bankFiltersService)
@@ -103,6 +159,10 @@ func run() error {
 	bankGroupCreatorService := services.NewBankGroupCreatorService(bankGroupWriter)
 	bankGroupUpdaterService := services.NewBankGroupUpdaterService(bankGroupWriter, bankGroupRepo)
 	bankGroupHandler := handlers.NewBankGroupHandler(bankGroupService, bankGroupCreatorService, bankGroupUpdaterService)
+	bankGroupDeleterService := services.NewBankGroupDeleterService(bankGroupWriter)
+	bankGroupDeleterHandler := handlers.NewBankGroupDeleterHandler(bankGroupDeleterService)
+	bankGroupPatcherService := services.NewBankGroupPatcherService(bankGroupWriter, bankGroupRepo)
+	bankGroupPatcherHandler := handlers.NewBankGroupPatcherHandler(bankGroupPatcherService)
 
 	// Initialize JWT service and auth middleware
 	jwtExpiry, err := time.ParseDuration(cfg.JWT.Expiry)
@@ -116,6 +176,18 @@ func run() error {
 	}
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
 
+	// Parse scheduled change worker settings
+	schedulerInterval, err := time.ParseDuration(cfg.Scheduler.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid scheduler interval: %w", err)
+	}
+	if schedulerInterval <= 0 {
+		return fmt.Errorf("invalid scheduler interval %q: must be positive", cfg.Scheduler.Interval)
+	}
+	if cfg.Scheduler.BatchSize <= 0 {
+		return fmt.Errorf("invalid scheduler batch size %d: must be positive", cfg.Scheduler.BatchSize)
+	}
+
 	// Setup Gin router
 	r := gin.Default()
 
@@ -131,32 +203,136 @@ func run() error {
 	// Bank endpoints require banks:read permission
 	api.GET("/banks",
 		authMiddleware.RequireAuth("banks:read"),
+		middleware.ConditionalGET(cfg.Cache.Banks),
 		bankHandler.GetBanks)
+	api.GET("/banks/lookup",
+		authMiddleware.RequireAuth("banks:read"),
+		bankHandler.LookupBanks)
+	api.GET("/banks/changes",
+		authMiddleware.RequireAuth("banks:read"),
+		changeHandler.GetChanges)
+	api.GET("/banks/export",
+		authMiddleware.RequireAuth("banks:read"),
+		bankHandler.ExportBanks)
+	api.POST("/banks/batch-get",
+		authMiddleware.RequireAuth("banks:read"),
+		bankHandler.BatchGetBanks)
+	api.POST("/banks/resolve-ibans",
+		authMiddleware.RequireAuth("banks:read"),
+		ibanResolverHandler.ResolveIBANs)
 	api.GET("/banks/:bankId/details",
 		authMiddleware.RequireAuth("banks:read"),
+		middleware.ConditionalGET(cfg.Cache.BankDetails),
 		bankHandler.GetBankDetails)
+	api.GET("/banks/:bankId/history",
+		authMiddleware.RequireAuth("banks:read"),
+		bankHistoryHandler.GetBankHistory)
+	api.GET("/banks/:bankId/revisions/:rev",
+		authMiddleware.RequireAuth("banks:read"),
+		bankReverterHandler.GetBankRevision)
+	api.GET("/banks/:bankId/scheduled-changes",
+		authMiddleware.RequireAuth("banks:read"),
+		scheduledChangeHandler.GetScheduledChanges)
+	api.GET("/banks/:bankId/environments/:env",
+		authMiddleware.RequireAuth("banks:read"),
+		environmentConfigHandler.GetEnvironmentConfig)
+	api.GET("/banks/:bankId/maintenance-windows",
+		authMiddleware.RequireAuth("banks:read"),
+		maintenanceWindowHandler.GetMaintenanceWindows)
 	// Bank creation endpoint requires banks:write permission
 	api.POST("/banks",
 		authMiddleware.RequireAuth("banks:write"),
 		bankCreatorHandler.CreateBank)
-	// Bank update endpoint requires banks:write permission
+	// Bulk import endpoint requires banks:write permission
+	api.POST("/banks/import",
+		authMiddleware.RequireAuth("banks:write"),
+		bankImporterHandler.ImportBanks)
+	// Bank update endpoints require banks:write permission
 	api.PUT("/banks/:bankId",
 		authMiddleware.RequireAuth("banks:write"),
 		bankUpdaterHandler.UpdateBank)
+	api.PATCH("/banks/:bankId",
+		authMiddleware.RequireAuth("banks:write"),
+		bankPatcherHandler.PatchBank)
+	api.POST("/banks/:bankId/revisions/:rev/revert",
+		authMiddleware.RequireAuth("banks:write"),
+		bankReverterHandler.RevertBank)
+	// Single environment config writes require banks:write permission
+	api.PUT("/banks/:bankId/environments/:env",
+		authMiddleware.RequireAuth("banks:write"),
+		environmentConfigHandler.PutEnvironmentConfig)
+	api.PATCH("/banks/:bankId/environments/:env",
+		authMiddleware.RequireAuth("banks:write"),
+		environmentConfigHandler.PatchEnvironmentConfig)
+	api.DELETE("/banks/:bankId/environments/:env",
+		authMiddleware.RequireAuth("banks:write"),
+		environmentConfigHandler.DeleteEnvironmentConfig)
+	// Environment promotions require banks:write permission
+	api.POST("/banks/:bankId/environments/:env/promote",
+		authMiddleware.RequireAuth("banks:write"),
+		environmentPromotionHandler.PromoteEnvironmentConfig)
+	api.POST("/environments/:env/promote",
+		authMiddleware.RequireAuth("banks:write"),
+		environmentPromotionHandler.PromoteEnvironmentConfigs)
+	// Scheduled config changes require banks:write permission
+	api.POST("/banks/:bankId/scheduled-changes",
+		authMiddleware.RequireAuth("banks:write"),
+		scheduledChangeHandler.ScheduleChange)
+	api.POST("/banks/:bankId/scheduled-changes/:changeId/cancel",
+		authMiddleware.RequireAuth("banks:write"),
+		scheduledChangeHandler.CancelScheduledChange)
+	// Maintenance windows require banks:write permission
+	api.POST("/banks/:bankId/maintenance-windows",
+		authMiddleware.RequireAuth("banks:write"),
+		maintenanceWindowHandler.CreateMaintenanceWindow)
+	api.DELETE("/banks/:bankId/maintenance-windows/:windowId",
+		authMiddleware.RequireAuth("banks:write"),
+		maintenanceWindowHandler.DeleteMaintenanceWindow)
+	// Soft delete and restore require banks:write permission
+	api.DELETE("/banks/:bankId",
+		authMiddleware.RequireAuth("banks:write"),
+		bankDeleterHandler.DeleteBank)
+	api.POST("/banks/:bankId/restore",
+		authMiddleware.RequireAuth("banks:write"),
+		bankDeleterHandler.RestoreBank)
+	// Permanent removal requires banks:admin permission
+	api.POST("/banks/:bankId/purge",
+		authMiddleware.RequireAuth("banks:admin"),
+		bankDeleterHandler.PurgeBank)
+	// Reports require banks:read permission
+	api.GET("/reports/environment-drift",
+		authMiddleware.RequireAuth("banks:read"),
+		environmentDriftHandler.GetEnvironmentDrift)
 	// Bank filters endpoint requires banks:read permission
 	api.GET("/filters",
 		authMiddleware.RequireAuth("banks:read"),
+		middleware.ConditionalGET(cfg.Cache.Filters),
 		bankFiltersHandler.GetFilters)
 	// Bank groups endpoints
 	api.GET("/bank-groups",
 		authMiddleware.RequireAuth("banks:read"),
+		middleware.ConditionalGET(cfg.Cache.BankGroups),
 		bankGroupHandler.GetBankGroups)
+	api.GET("/bank-groups/:groupId",
+		authMiddleware.RequireAuth("banks:read"),
+		middleware.ConditionalGET(cfg.Cache.BankGroups),
+		bankGroupHandler.GetBankGroupDetails)
+	api.GET("/bank-groups/:groupId/banks",
+		authMiddleware.RequireAuth("banks:read"),
+		middleware.ConditionalGET(cfg.Cache.Banks),
+		bankHandler.GetBankGroupBanks)
 	api.POST("/bank-groups",
 		authMiddleware.RequireAuth("banks:write"),
 		bankGroupHandler.CreateBankGroup)
 	api.PUT("/bank-groups/:groupId",
 		authMiddleware.RequireAuth("banks:write"),
 		bankGroupHandler.UpdateBankGroup)
+	api.PATCH("/bank-groups/:groupId",
+		authMiddleware.RequireAuth("banks:write"),
+		bankGroupPatcherHandler.PatchBankGroup)
+	api.DELETE("/bank-groups/:groupId",
+		authMiddleware.RequireAuth("banks:write"),
+		bankGroupDeleterHandler.DeleteBankGroup)
 
 	// Create HTTP server with timeouts
 	srv := &http.Server{
@@ -175,6 +351,19 @@ func run() error {
 		}
 	}()
 
+	// Start the scheduled change worker, stopped on shutdown
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	workerDone := make(chan struct{})
+	if cfg.Scheduler.Enabled {
+		go func() {
+			defer close(workerDone)
+			scheduler.NewWorker(scheduledChangeService, schedulerInterval, cfg.Scheduler.BatchSize, appLogger).Run(workerCtx)
+		}()
+	} else {
+		close(workerDone)
+	}
+
 	// Create channel to listen for interrupt signals
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -194,6 +383,9 @@ func run() error {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
+	stopWorker()
+	<-workerDone
+
 	appLogger.Info("graceful shutdown completed successfully")
 	return nil
 }
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/config"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+func main() {
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		// Don't fail if .env doesn't exist, just warn
+		fmt.Fprintf(os.Stderr, "Warning: Could not load .env file: %v\n", err)
+	}
+
+	// Define command line flags
+	var (
+		fileFlag   = flag.String("file", "", "Path of the CSV or NDJSON file to import")
+		formatFlag = flag.String("format", "", "File format: csv or ndjson (defaults to the file extension)")
+		modeFlag   = flag.String("mode", services.ImportModeCreateOnly, "Import mode: create_only, upsert or replace")
+		dryRunFlag = flag.Bool("dry-run", false, "Validate and run the import without committing any change")
+		atomicFlag = flag.Bool("atomic", false, "Commit every bank or none of them")
+		jsonFlag   = flag.Bool("json", false, "Print the full report as JSON")
+		helpFlag   = flag.Bool("help", false, "Show help message")
+	)
+
+	flag.Parse()
+
+	// Show help if requested
+	if *helpFlag {
+		showHelp()
+		return
+	}
+
+	if *fileFlag == "" {
+		showHelp()
+		os.Exit(1)
+	}
+
+	format := *formatFlag
+	if format == "" {
+		format = formatFromExtension(*fileFlag)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	file, err := os.Open(*fileFlag)
+	if err != nil {
+		log.Fatalf("Failed to open import file: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// The imported writes are audited under the OS user running the command
+	ctx := audit.WithActor(context.Background(), audit.Actor{Subject: currentUsername()})
+
+	// Connect to database
+	dbPool, err := pgxpool.New(ctx, cfg.Database.ConnectionString())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	if err := dbPool.Ping(ctx); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	// Same services as POST /api/banks/import
+	bankRepo := repository.NewPostgresBankRepository(dbPool)
+	bankWriter := repository.NewPostgresBankWriter(dbPool)
+	importer := services.NewBankImporterService(bankWriter, bankRepo)
+
+	report, err := importer.ImportBanks(ctx, file, &services.ImportBanksOptions{
+		Format: format,
+		Mode:   *modeFlag,
+		DryRun: *dryRunFlag,
+		Atomic: *atomicFlag,
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	if *jsonFlag {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// formatFromExtension guesses the import format from the file name
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return services.ExportFormatNDJSON
+	default:
+		return services.ExportFormatCSV
+	}
+}
+
+func printReport(report *models.BankImportReport) {
+	for _, row := range report.Rows {
+		if row.Success {
+			continue
+		}
+		for _, fieldError := range row.Errors {
+			location := fmt.Sprintf("row %d", row.Row)
+			if row.BankID != "" {
+				location += " (" + row.BankID + ")"
+			}
+			if fieldError.Field != "" {
+				location += " " + fieldError.Field
+			}
+			fmt.Printf("❌ %s: %s\n", location, fieldError.Message)
+		}
+	}
+
+	if report.Failed > 0 {
+		fmt.Println()
+	}
+
+	switch {
+	case report.DryRun:
+		fmt.Printf("🔍 Dry run (%s): %d rows, %d valid, %d failed. No changes were committed.\n",
+			report.Mode, report.Total, report.Succeeded, report.Failed)
+	case report.Applied:
+		fmt.Printf("✅ Import (%s): %d rows, %d imported, %d failed\n",
+			report.Mode, report.Total, report.Succeeded, report.Failed)
+	default:
+		fmt.Printf("⚠️  Import (%s): %d rows, %d failed. No changes were committed.\n",
+			report.Mode, report.Total, report.Failed)
+	}
+}
+
+// currentUsername returns the name of the OS user, or an empty string when it cannot be determined
+func currentUsername() string {
+	current, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return current.Username
+}
+
+func showHelp() {
+	fmt.Println("Bulk Bank Import for Go Banks API")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Printf("  go run cmd/import/main.go -file <path> [options]\n")
+	fmt.Println()
+	fmt.Println("The file uses the columns of GET /api/banks/export: one row per bank and")
+	fmt.Println("environment, with bank fields repeated on every row of the same bank.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -file string")
+	fmt.Println("        Path of the CSV or NDJSON file to import")
+	fmt.Println("  -format string")
+	fmt.Println("        csv or ndjson (defaults to the file extension)")
+	fmt.Println("  -mode string")
+	fmt.Println("        create_only (default), upsert or replace")
+	fmt.Println("  -dry-run")
+	fmt.Println("        Validate and run the import without committing any change")
+	fmt.Println("  -atomic")
+	fmt.Println("        Commit every bank or none of them")
+	fmt.Println("  -json")
+	fmt.Println("        Print the full report as JSON")
+	fmt.Println("  -help")
+	fmt.Println("        Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  # Check a file without writing anything")
+	fmt.Println("  go run cmd/import/main.go -file banks_pt.csv -dry-run")
+	fmt.Println()
+	fmt.Println("  # Create or update banks, all or nothing")
+	fmt.Println("  go run cmd/import/main.go -file banks_pt.ndjson -mode upsert -atomic")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/user"
 	"strings"
 	"time"
 
@@ -32,6 +33,7 @@ func main() {
 	// Define command line flags
 	var (
 		apiKeyFlag      = flag.String("apikey", "", "API key for authentication (defaults to API_KEY env var)")
+		subjectFlag     = flag.String("subject", "", "Token subject, recorded as the actor of writes made with it (defaults to the current OS user)")
 		permissionsFlag = flag.String("permissions", "banks:read", "Comma-separated list of permissions (e.g., banks:read,banks:write)")
 		expiryFlag      = flag.String("expiry", "", "Token expiry duration (e.g., 24h, 1h, 30m) - defaults to JWT_EXPIRY env var")
 		helpFlag        = flag.Bool("help", false, "Show help message")
@@ -54,6 +56,17 @@ func main() {
 		log.Fatalf("Invalid API key. Use -apikey flag or set API_KEY environment variable")
 	}
 
+	// Resolve the subject
+	subject := strings.TrimSpace(*subjectFlag)
+	if subject == "" {
+		if current, err := user.Current(); err == nil {
+			subject = current.Username
+		}
+	}
+	if subject == "" {
+		log.Fatalf("A subject is required. Use -subject to identify who will use the token")
+	}
+
 	// Parse permissions
 	permissionsList := parsePermissions(*permissionsFlag)
 	if len(permissionsList) == 0 {
@@ -62,7 +75,7 @@ func main() {
 
 	// Validate permissions
 	if !validatePermissions(permissionsList) {
-		log.Fatalf("Invalid permissions. Allowed: banks:read, banks:write")
+		log.Fatalf("Invalid permissions. Allowed: banks:read, banks:write, banks:admin")
 	}
 
 	// Parse expiry duration
@@ -88,7 +101,7 @@ func main() {
 	}
 
 	// Generate token
-	token, err := jwtService.GenerateToken(permissionsList)
+	token, err := jwtService.GenerateToken(subject, permissionsList)
 	if err != nil {
 		log.Fatalf("Failed to generate token: %v", err)
 	}
@@ -101,6 +114,7 @@ func main() {
 	fmt.Println()
 	fmt.Printf("Token: %s\n", token)
 	fmt.Printf("Expires: %s\n", expiresAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Subject: %s\n", subject)
 	fmt.Printf("Permissions: %v\n", permissionsList)
 	fmt.Printf("Duration: %s\n", expiry)
 	fmt.Println()
@@ -130,6 +144,7 @@ func validatePermissions(permissions []string) bool {
 	allowedPermissions := map[string]bool{
 		"banks:read":  true,
 		"banks:write": true,
+		"banks:admin": true,
 	}
 
 	for _, perm := range permissions {
@@ -150,9 +165,11 @@ func showHelp() {
 	fmt.Println("Options:")
 	fmt.Println("  -apikey string")
 	fmt.Println("        API key for authentication (defaults to API_KEY env var)")
+	fmt.Println("  -subject string")
+	fmt.Println("        Token subject, recorded as the actor of its writes (defaults to the current OS user)")
 	fmt.Println("  -permissions string")
 	fmt.Println("        Comma-separated list of permissions (default: banks:read)")
-	fmt.Println("        Available permissions: banks:read, banks:write")
+	fmt.Println("        Available permissions: banks:read, banks:write, banks:admin")
 	fmt.Println("  -expiry string")
 	fmt.Println("        Token expiry duration (defaults to JWT_EXPIRY env var)")
 	fmt.Println("        Examples: 24h, 1h, 30m, 1h30m")
@@ -166,6 +183,9 @@ func showHelp() {
 	fmt.Println("  # Generate token with read and write permissions")
 	fmt.Println("  go run cmd/token/main.go -permissions banks:read,banks:write")
 	fmt.Println()
+	fmt.Println("  # Generate a write token for a named operator")
+	fmt.Println("  go run cmd/token/main.go -subject ops@example.com -permissions banks:read,banks:write")
+	fmt.Println()
 	fmt.Println("  # Generate token with custom expiry")
 	fmt.Println("  go run cmd/token/main.go -expiry 1h")
 	fmt.Println()
@@ -0,0 +1,29 @@
+// Package audit carries the identity behind a catalog write down to the writers that record it.
+package audit
+
+import "context"
+
+// SystemSubject identifies writes made without an authenticated caller, such as command-line tools
+const SystemSubject = "system"
+
+// Actor identifies who made a write and the request it belongs to
+type Actor struct {
+	Subject   string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx that carries the actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, or SystemSubject when there is none
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok || actor.Subject == "" {
+		actor.Subject = SystemSubject
+	}
+	return actor
+}
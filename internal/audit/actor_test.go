@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorFromContext(t *testing.T) {
+	assert.Equal(t, Actor{Subject: SystemSubject}, ActorFromContext(context.Background()))
+
+	ctx := WithActor(context.Background(), Actor{Subject: "ops@example.com", RequestID: "req-1"})
+	assert.Equal(t, Actor{Subject: "ops@example.com", RequestID: "req-1"}, ActorFromContext(ctx))
+
+	ctx = WithActor(context.Background(), Actor{RequestID: "req-2"})
+	assert.Equal(t, Actor{Subject: SystemSubject, RequestID: "req-2"}, ActorFromContext(ctx))
+}
@@ -40,13 +40,18 @@ func NewJWTService(provider secrets.SecretProvider, expiry time.Duration, log lo
 	}, nil
 }
 
-// GenerateToken generates a JWT token with the given permissions
-func (j *JWTService) GenerateToken(permissions []string) (string, error) {
+// GenerateToken generates a JWT token for the given subject and permissions. The subject is
+// recorded as the actor of every write made with the token.
+func (j *JWTService) GenerateToken(subject string, permissions []string) (string, error) {
+	if subject == "" {
+		return "", errors.New("token subject is required")
+	}
+
 	now := time.Now()
 
 	claims := &Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   "api-client",
+			Subject:   subject,
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
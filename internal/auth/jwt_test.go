@@ -33,7 +33,7 @@ func TestJWTService_GenerateToken(t *testing.T) {
 
 	permissions := []string{"banks:read", "banks:write"}
 
-	token, err := service.GenerateToken(permissions)
+	token, err := service.GenerateToken("test-client", permissions)
 	require.NoError(t, err)
 	assert.NotEmpty(t, token)
 
@@ -42,7 +42,7 @@ func TestJWTService_GenerateToken(t *testing.T) {
 	require.NoError(t, err)
 
 	// Check claims
-	assert.Equal(t, "api-client", claims.Subject)
+	assert.Equal(t, "test-client", claims.Subject)
 	assert.Equal(t, "go-banks-api", claims.Issuer)
 	assert.Len(t, claims.Permissions, 2)
 	assert.Contains(t, claims.Permissions, "banks:read")
@@ -68,7 +68,7 @@ func TestJWTService_ValidateToken_ExpiredToken(t *testing.T) {
 	require.NoError(t, err)
 
 	permissions := []string{"banks:read"}
-	token, err := service.GenerateToken(permissions)
+	token, err := service.GenerateToken("test-client", permissions)
 	require.NoError(t, err)
 
 	// Wait a moment to ensure token is expired
@@ -90,7 +90,7 @@ func TestJWTService_ValidateToken_WrongSecret(t *testing.T) {
 	require.NoError(t, err)
 
 	permissions := []string{"banks:read"}
-	token, err := service1.GenerateToken(permissions)
+	token, err := service1.GenerateToken("test-client", permissions)
 	require.NoError(t, err)
 
 	// Try to validate with different secret
@@ -158,7 +158,7 @@ func TestJWTService_TokenStructure(t *testing.T) {
 	require.NoError(t, err)
 	permissions := []string{"banks:read"}
 
-	tokenString, err := service.GenerateToken(permissions)
+	tokenString, err := service.GenerateToken("test-client", permissions)
 	require.NoError(t, err)
 
 	// Parse token without validation to check structure
@@ -176,3 +176,15 @@ func TestJWTService_TokenStructure(t *testing.T) {
 	assert.NotNil(t, claims.IssuedAt)
 	assert.NotNil(t, claims.NotBefore)
 }
+
+func TestJWTService_GenerateToken_RequiresSubject(t *testing.T) {
+	testLogger := logger.NewDiscardLogger()
+	mockProvider := &testSecretProvider{secret: "test-secret"}
+	service, err := NewJWTService(mockProvider, time.Hour, testLogger)
+	require.NoError(t, err)
+
+	token, err := service.GenerateToken("", []string{"banks:read"})
+
+	require.Error(t, err)
+	assert.Empty(t, token)
+}
@@ -9,11 +9,13 @@ import (
 )
 
 type Config struct {
-	Port     int             `json:"port"`
-	Database *DatabaseConfig `json:"database"`
-	JWT      *JWTConfig      `json:"jwt"`
-	Logger   *LoggerConfig   `json:"logger"`
-	APIKey   string          `json:"api_key"`
+	Port      int              `json:"port"`
+	Database  *DatabaseConfig  `json:"database"`
+	JWT       *JWTConfig       `json:"jwt"`
+	Logger    *LoggerConfig    `json:"logger"`
+	Cache     *CacheConfig     `json:"cache"`
+	Scheduler *SchedulerConfig `json:"scheduler"`
+	APIKey    string           `json:"api_key"`
 }
 
 type DatabaseConfig struct {
@@ -39,6 +41,21 @@ type LoggerConfig struct {
 	MaxFileSize int64    `json:"max_file_size"`
 }
 
+// CacheConfig holds the Cache-Control policy sent by each cacheable route
+type CacheConfig struct {
+	Banks       string `json:"banks"`
+	BankDetails string `json:"bank_details"`
+	BankGroups  string `json:"bank_groups"`
+	Filters     string `json:"filters"`
+}
+
+// SchedulerConfig controls the worker that applies scheduled environment config changes
+type SchedulerConfig struct {
+	Enabled   bool   `json:"enabled"`
+	Interval  string `json:"interval"`
+	BatchSize int    `json:"batch_size"`
+}
+
 func Load() (*Config, error) {
 	config := &Config{
 		Port:   getEnvAsInt("PORT", 8080),
@@ -63,6 +80,17 @@ func Load() (*Config, error) {
 			AddSource:   getEnvAsBool("LOG_ADD_SOURCE", false),
 			MaxFileSize: getEnvAsInt64("LOG_MAX_FILE_SIZE", 100*1024*1024), // 100MB
 		},
+		Cache: &CacheConfig{
+			Banks:       getEnv("CACHE_CONTROL_BANKS", "private, no-cache"),
+			BankDetails: getEnv("CACHE_CONTROL_BANK_DETAILS", "private, no-cache"),
+			BankGroups:  getEnv("CACHE_CONTROL_BANK_GROUPS", "private, max-age=300"),
+			Filters:     getEnv("CACHE_CONTROL_FILTERS", "private, max-age=60"),
+		},
+		Scheduler: &SchedulerConfig{
+			Enabled:   getEnvAsBool("SCHEDULER_ENABLED", true),
+			Interval:  getEnv("SCHEDULER_INTERVAL", "30s"),
+			BatchSize: getEnvAsInt("SCHEDULER_BATCH_SIZE", 100),
+		},
 	}
 
 	slog.Info("configuration loaded successfully",
@@ -0,0 +1,214 @@
+// Package cron parses standard five-field cron expressions and finds the times they match.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds the search for matching times, so that expressions that never match, such as
+// "0 0 31 2 *", do not loop forever
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed cron expression: minute, hour, day of month, month and day of week.
+// Each field is a set of allowed values stored as a bit mask.
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// When both day fields are restricted a day matches if either does, as in the classic cron
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
+}
+
+type fieldBounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds     = fieldBounds{"minute", 0, 59}
+	hourBounds       = fieldBounds{"hour", 0, 23}
+	dayOfMonthBounds = fieldBounds{"day of month", 1, 31}
+	monthBounds      = fieldBounds{"month", 1, 12}
+	// Both 0 and 7 stand for Sunday
+	dayOfWeekBounds = fieldBounds{"day of week", 0, 7}
+)
+
+// Parse parses an expression of five space-separated fields. Each field accepts "*", a value,
+// a range "a-b", a step "*/n" or "a-b/n", and comma-separated lists of those.
+func Parse(expression string) (*Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+
+	var schedule Schedule
+	var err error
+	if schedule.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.dayOfMonthAny = fields[2] == "*"
+	schedule.dayOfWeekAny = fields[4] == "*"
+
+	return &schedule, nil
+}
+
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var mask uint64
+	for part := range strings.SplitSeq(field, ",") {
+		partMask, err := parsePart(part, bounds)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s field %q: %w", bounds.name, field, err)
+		}
+		mask |= partMask
+	}
+	return mask, nil
+}
+
+func parsePart(part string, bounds fieldBounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step < 1 {
+			return 0, fmt.Errorf("invalid step %q", stepPart)
+		}
+	}
+
+	low, high := bounds.min, bounds.max
+	switch {
+	case rangePart == "*":
+	case strings.Contains(rangePart, "-"):
+		lowPart, highPart, _ := strings.Cut(rangePart, "-")
+		var err error
+		if low, err = parseValue(lowPart, bounds); err != nil {
+			return 0, err
+		}
+		if high, err = parseValue(highPart, bounds); err != nil {
+			return 0, err
+		}
+		if low > high {
+			return 0, fmt.Errorf("range %q is reversed", rangePart)
+		}
+	default:
+		value, err := parseValue(rangePart, bounds)
+		if err != nil {
+			return 0, err
+		}
+		low = value
+		// "5/15" means every 15 from 5 onwards
+		if !hasStep {
+			high = value
+		}
+	}
+
+	var mask uint64
+	for value := low; value <= high; value += step {
+		mask |= 1 << value
+	}
+	return mask, nil
+}
+
+func parseValue(s string, bounds fieldBounds) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if value < bounds.min || value > bounds.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", value, bounds.min, bounds.max)
+	}
+	return value, nil
+}
+
+// ErrNoMatch is returned when an expression does not match any time within the search limit
+var ErrNoMatch = errors.New("cron expression does not match any time")
+
+// Matches reports whether the schedule matches the minute of t, in the location of t
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.matchesDay(t) && s.hour&(1<<t.Hour()) != 0 && s.minute&(1<<t.Minute()) != 0
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	if s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	dayOfMonth := s.dayOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := s.dayOfWeek&(1<<int(t.Weekday())) != 0
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// Prev returns the latest matching minute at or before t, in the location of t
+func (s *Schedule) Prev(t time.Time) (time.Time, error) {
+	limit := t.Add(-searchLimit)
+	t = t.Truncate(time.Minute)
+
+	for !t.Before(limit) {
+		year, month, day := t.Date()
+		loc := t.Location()
+
+		switch {
+		case !s.matchesDay(t):
+			// Last minute of the previous day
+			t = time.Date(year, month, day, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(year, month, day, t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, nil
+		}
+	}
+
+	return time.Time{}, ErrNoMatch
+}
+
+// Next returns the earliest matching minute after t, in the location of t
+func (s *Schedule) Next(t time.Time) (time.Time, error) {
+	limit := t.Add(searchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for !t.After(limit) {
+		year, month, day := t.Date()
+		loc := t.Location()
+
+		switch {
+		case !s.matchesDay(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(year, month, day, t.Hour(), 0, 0, 0, loc).Add(time.Hour)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+
+	return time.Time{}, ErrNoMatch
+}
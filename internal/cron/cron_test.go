@@ -0,0 +1,98 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"too few fields", "0 22 * *", "must have 5 fields"},
+		{"out of range", "60 * * * *", "out of range"},
+		{"not a number", "0 22 * * SAT", "invalid value"},
+		{"reversed range", "0 6-2 * * *", "is reversed"},
+		{"zero step", "*/0 * * * *", "invalid step"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestSchedule_Matches(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		time       time.Time
+		expected   bool
+	}{
+		{"every minute", "* * * * *", time.Date(2026, 3, 7, 13, 27, 0, 0, time.UTC), true},
+		{"saturday night", "0 22 * * 6", time.Date(2026, 3, 7, 22, 0, 0, 0, time.UTC), true},
+		{"sunday as 7", "30 2 * * 7", time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC), true},
+		{"wrong weekday", "0 22 * * 6", time.Date(2026, 3, 6, 22, 0, 0, 0, time.UTC), false},
+		{"list and step", "0,30 */6 * * *", time.Date(2026, 3, 7, 18, 30, 0, 0, time.UTC), true},
+		{"step misses", "0 */6 * * *", time.Date(2026, 3, 7, 19, 0, 0, 0, time.UTC), false},
+		{"either day field when both are set", "0 0 1 * 1", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), true},
+		{"range", "0 9-17 * * 1-5", time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, schedule.Matches(tt.time))
+		})
+	}
+}
+
+func TestSchedule_PrevAndNext(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	schedule, err := Parse("0 22 * * 6")
+	require.NoError(t, err)
+
+	// Monday 10 March 2026
+	now := time.Date(2026, 3, 10, 9, 15, 0, 0, madrid)
+
+	prev, err := schedule.Prev(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 7, 22, 0, 0, 0, madrid), prev)
+
+	next, err := schedule.Next(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 14, 22, 0, 0, 0, madrid), next)
+
+	// A matching minute is its own previous match but not its own next one
+	prev, err = schedule.Prev(next)
+	require.NoError(t, err)
+	assert.Equal(t, next, prev)
+
+	next, err = schedule.Next(prev)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 21, 22, 0, 0, 0, madrid), next)
+}
+
+func TestSchedule_NeverMatches(t *testing.T) {
+	schedule, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+
+	_, err = schedule.Prev(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrNoMatch)
+
+	_, err = schedule.Next(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrNoMatch)
+}
@@ -55,6 +55,7 @@ func (h *BankCreatorHandler) CreateBank(c *gin.Context) {
 		return
 	}
 
+	setVersionETag(c, bank.Version)
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Bank created successfully",
 		"data":    bank,
@@ -46,6 +46,7 @@ func TestBankCreatorHandler_CreateBank_Success(t *testing.T) {
 		ASPSP:                  "test_aspsp",
 		Country:                "ES",
 		AuthTypeChoiceRequired: true,
+		Version:                1,
 	}
 
 	mockService.On("CreateBank", mock.Anything, mock.MatchedBy(func(req *services.CreateBankRequest) bool {
@@ -80,6 +81,7 @@ func TestBankCreatorHandler_CreateBank_Success(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
 
 	var response map[string]any
 	err = json.Unmarshal(w.Body.Bytes(), &response)
@@ -91,6 +93,7 @@ func TestBankCreatorHandler_CreateBank_Success(t *testing.T) {
 	data := response["data"].(map[string]any)
 	assert.Equal(t, "test_bank_001", data["bank_id"])
 	assert.Equal(t, "Test Bank", data["name"])
+	assert.InDelta(t, 1, data["version"], 0)
 
 	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type BankDeleterHandler struct {
+	deleterService services.BankDeleter
+}
+
+func NewBankDeleterHandler(deleterService services.BankDeleter) *BankDeleterHandler {
+	return &BankDeleterHandler{
+		deleterService: deleterService,
+	}
+}
+
+// DeleteBank soft-deletes a bank
+func (h *BankDeleterHandler) DeleteBank(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	deletion, err := h.deleterService.DeleteBank(c.Request.Context(), bankID)
+	if err != nil {
+		h.handleError(c, err, bankID, "delete")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank deleted", "bank_id", deletion.BankID)
+	}
+
+	response := models.APIResponse[*models.BankDeletion]{
+		Success: true,
+		Data:    deletion,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreBank undoes the soft delete of a bank
+func (h *BankDeleterHandler) RestoreBank(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	bank, err := h.deleterService.RestoreBank(c.Request.Context(), bankID)
+	if err != nil {
+		h.handleError(c, err, bankID, "restore")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank restored", "bank_id", bank.BankID)
+	}
+
+	response := models.APIResponse[*models.Bank]{
+		Success: true,
+		Data:    bank,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// PurgeBank permanently removes a bank and its environment configs
+func (h *BankDeleterHandler) PurgeBank(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	purge, err := h.deleterService.PurgeBank(c.Request.Context(), bankID)
+	if err != nil {
+		h.handleError(c, err, bankID, "purge")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Warn("bank purged",
+			"bank_id", purge.BankID,
+			"environment_configs_deleted", purge.EnvironmentConfigsDeleted,
+			"remote_addr", c.ClientIP(),
+		)
+	}
+
+	response := models.APIResponse[*models.BankPurge]{
+		Success: true,
+		Data:    purge,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BankDeleterHandler) handleError(c *gin.Context, err error, bankID, action string) {
+	errorMessage := err.Error()
+
+	switch {
+	case strings.Contains(errorMessage, "invalid request"):
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Bank ID is required"),
+		})
+
+	case strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Bank not found"),
+		})
+
+	case strings.Contains(errorMessage, "is not deleted"):
+		c.JSON(http.StatusConflict, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Bank is not deleted"),
+		})
+
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to "+action+" bank",
+				"error", err,
+				"bank_id", bankID,
+			)
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to " + action + " bank"),
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockBankDeleter implements the BankDeleter interface for testing
+type MockBankDeleter struct {
+	mock.Mock
+}
+
+func (m *MockBankDeleter) DeleteBank(ctx context.Context, bankID string) (*models.BankDeletion, error) {
+	args := m.Called(ctx, bankID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankDeletion), args.Error(1)
+}
+
+func (m *MockBankDeleter) RestoreBank(ctx context.Context, bankID string) (*models.Bank, error) {
+	args := m.Called(ctx, bankID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Bank), args.Error(1)
+}
+
+func (m *MockBankDeleter) PurgeBank(ctx context.Context, bankID string) (*models.BankPurge, error) {
+	args := m.Called(ctx, bankID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankPurge), args.Error(1)
+}
+
+func newBankDeleterRouter(handler *BankDeleterHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.DELETE("/banks/:bankId", handler.DeleteBank)
+	router.POST("/banks/:bankId/restore", handler.RestoreBank)
+	router.POST("/banks/:bankId/purge", handler.PurgeBank)
+	return router
+}
+
+func TestBankDeleterHandler_DeleteBank(t *testing.T) {
+	mockService := new(MockBankDeleter)
+	router := newBankDeleterRouter(NewBankDeleterHandler(mockService))
+
+	deletedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("DeleteBank", mock.Anything, "BES2100").
+		Return(&models.BankDeletion{BankID: "BES2100", DeletedAt: deletedAt}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/banks/BES2100", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.BankDeletion]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, "BES2100", response.Data.BankID)
+	assert.True(t, deletedAt.Equal(response.Data.DeletedAt))
+	mockService.AssertExpectations(t)
+}
+
+func TestBankDeleterHandler_RestoreBank(t *testing.T) {
+	mockService := new(MockBankDeleter)
+	router := newBankDeleterRouter(NewBankDeleterHandler(mockService))
+
+	mockService.On("RestoreBank", mock.Anything, "BES2100").
+		Return(&models.Bank{BankID: "BES2100", Name: "CaixaBank"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/banks/BES2100/restore", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.Bank]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, "CaixaBank", response.Data.Name)
+}
+
+func TestBankDeleterHandler_PurgeBank(t *testing.T) {
+	mockService := new(MockBankDeleter)
+	router := newBankDeleterRouter(NewBankDeleterHandler(mockService))
+
+	mockService.On("PurgeBank", mock.Anything, "BES2100").
+		Return(&models.BankPurge{BankID: "BES2100", EnvironmentConfigsDeleted: 2}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/banks/BES2100/purge", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.BankPurge]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(2), response.Data.EnvironmentConfigsDeleted)
+}
+
+func TestBankDeleterHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		serviceMethod  string
+		serviceError   error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "delete unknown bank",
+			method:         http.MethodDelete,
+			path:           "/banks/UNKNOWN",
+			serviceMethod:  "DeleteBank",
+			serviceError:   errors.New("failed to delete bank: bank with ID 'UNKNOWN' not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Bank not found",
+		},
+		{
+			name:           "restore bank that is not deleted",
+			method:         http.MethodPost,
+			path:           "/banks/UNKNOWN/restore",
+			serviceMethod:  "RestoreBank",
+			serviceError:   errors.New("failed to restore bank: bank with ID 'UNKNOWN' is not deleted"),
+			expectedStatus: http.StatusConflict,
+			expectedError:  "Bank is not deleted",
+		},
+		{
+			name:           "purge database failure",
+			method:         http.MethodPost,
+			path:           "/banks/UNKNOWN/purge",
+			serviceMethod:  "PurgeBank",
+			serviceError:   errors.New("failed to purge bank: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to purge bank",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankDeleter)
+			router := newBankDeleterRouter(NewBankDeleterHandler(mockService))
+			mockService.On(tt.serviceMethod, mock.Anything, "UNKNOWN").Return(nil, tt.serviceError)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Equal(t, tt.expectedError, *response.Error)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type BankGroupDeleterHandler struct {
+	deleterService services.BankGroupDeleter
+}
+
+func NewBankGroupDeleterHandler(deleterService services.BankGroupDeleter) *BankGroupDeleterHandler {
+	return &BankGroupDeleterHandler{
+		deleterService: deleterService,
+	}
+}
+
+// DeleteBankGroup deletes a bank group applying the policy query parameter to its member banks.
+// A group rejected because it still has banks is reported with 409 and the list of banks.
+func (h *BankGroupDeleterHandler) DeleteBankGroup(c *gin.Context) {
+	groupID := c.Param("groupId")
+	request := &services.DeleteBankGroupRequest{
+		Policy:     c.Query("policy"),
+		ReassignTo: c.Query("reassign_to"),
+	}
+
+	deletion, err := h.deleterService.DeleteBankGroup(c.Request.Context(), groupID, request)
+	if err != nil {
+		errorMessage := err.Error()
+
+		switch {
+		case strings.Contains(errorMessage, "invalid"):
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid bank group deletion request",
+					"error", errorMessage,
+					"group_id", groupID,
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(errorMessage),
+			})
+
+		case strings.Contains(errorMessage, "not found"):
+			c.JSON(http.StatusNotFound, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Bank group not found"),
+			})
+
+		default:
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("failed to delete bank group",
+					"error", err,
+					"group_id", groupID,
+					"policy", request.Policy,
+				)
+			}
+			c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Failed to delete bank group"),
+			})
+		}
+		return
+	}
+
+	if !deletion.Deleted {
+		response := models.APIResponse[*models.BankGroupDeletion]{
+			Success: false,
+			Data:    deletion,
+			Error:   stringPtr(fmt.Sprintf("Bank group still has %d member banks", len(deletion.AffectedBanks))),
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank group deleted",
+			"group_id", deletion.GroupID,
+			"policy", deletion.Policy,
+			"affected_banks", len(deletion.AffectedBanks),
+		)
+	}
+
+	response := models.APIResponse[*models.BankGroupDeletion]{
+		Success: true,
+		Data:    deletion,
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockBankGroupDeleter implements the BankGroupDeleter interface for testing
+type MockBankGroupDeleter struct {
+	mock.Mock
+}
+
+func (m *MockBankGroupDeleter) DeleteBankGroup(ctx context.Context, groupID string, request *services.DeleteBankGroupRequest) (*models.BankGroupDeletion, error) {
+	args := m.Called(ctx, groupID, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroupDeletion), args.Error(1)
+}
+
+func performBankGroupDeletion(t *testing.T, mockService *MockBankGroupDeleter, url string) (*httptest.ResponseRecorder, models.APIResponse[*models.BankGroupDeletion]) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.DELETE("/bank-groups/:groupId", NewBankGroupDeleterHandler(mockService).DeleteBankGroup)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, url, nil)
+	router.ServeHTTP(w, req)
+
+	var response models.APIResponse[*models.BankGroupDeletion]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func TestBankGroupDeleterHandler_DeleteBankGroup(t *testing.T) {
+	mockService := new(MockBankGroupDeleter)
+	groupID := uuid.New()
+	targetID := uuid.New()
+
+	deletion := &models.BankGroupDeletion{
+		GroupID:       groupID,
+		Policy:        models.BankGroupDeletePolicyReassign,
+		ReassignedTo:  &targetID,
+		Deleted:       true,
+		AffectedBanks: []string{"BES0049", "BES2100"},
+	}
+	mockService.On("DeleteBankGroup", mock.Anything, groupID.String(), &services.DeleteBankGroupRequest{
+		Policy:     "reassign",
+		ReassignTo: targetID.String(),
+	}).Return(deletion, nil)
+
+	w, response := performBankGroupDeletion(t, mockService, "/bank-groups/"+groupID.String()+"?policy=reassign&reassign_to="+targetID.String())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Success)
+	assert.Equal(t, deletion, response.Data)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankGroupDeleterHandler_DeleteBankGroup_Rejected(t *testing.T) {
+	mockService := new(MockBankGroupDeleter)
+	groupID := uuid.New()
+
+	mockService.On("DeleteBankGroup", mock.Anything, groupID.String(), mock.Anything).Return(&models.BankGroupDeletion{
+		GroupID:       groupID,
+		Policy:        models.BankGroupDeletePolicyReject,
+		AffectedBanks: []string{"BES2100"},
+	}, nil)
+
+	w, response := performBankGroupDeletion(t, mockService, "/bank-groups/"+groupID.String()+"?policy=reject")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "Bank group still has 1 member banks", *response.Error)
+	assert.Equal(t, []string{"BES2100"}, response.Data.AffectedBanks)
+}
+
+func TestBankGroupDeleterHandler_DeleteBankGroup_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "missing policy",
+			serviceError:   errors.New("invalid policy: a policy is required (allowed: reject, detach, reassign)"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid policy: a policy is required (allowed: reject, detach, reassign)",
+		},
+		{
+			name:           "unknown reassign target",
+			serviceError:   errors.New("failed to delete bank group: invalid reassign_to: bank group with ID 'x' not found"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "failed to delete bank group: invalid reassign_to: bank group with ID 'x' not found",
+		},
+		{
+			name:           "unknown group",
+			serviceError:   errors.New("failed to delete bank group: bank group with ID 'x' not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Bank group not found",
+		},
+		{
+			name:           "database failure",
+			serviceError:   errors.New("failed to delete bank group: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to delete bank group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankGroupDeleter)
+			mockService.On("DeleteBankGroup", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceError)
+
+			w, response := performBankGroupDeletion(t, mockService, "/bank-groups/"+uuid.New().String())
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Equal(t, tt.expectedError, *response.Error)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -8,6 +9,7 @@ import (
 
 	"github.com/wukong0111/go-banks/internal/logger"
 	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
 	"github.com/wukong0111/go-banks/internal/services"
 )
 
@@ -25,10 +27,65 @@ func NewBankGroupHandler(bankGroupService services.BankGroupService, creatorServ
 	}
 }
 
+// GetBankGroups lists the bank groups page by page.
+// No Last-Modified is set: deleting a group does not advance the newest updated_at of the page.
 func (h *BankGroupHandler) GetBankGroups(c *gin.Context) {
+	sort, err := parseSortParam(c.Query("sort"))
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid sort parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	filters := &repository.BankGroupFilters{
+		Name: c.Query("name"),
+		Sort: sort,
+	}
+
+	// Pagination is only applied when page or limit is given
+	page, err := parseIntParam(c, "page")
+	if err == nil {
+		filters.Page = page
+		filters.Limit, err = parseIntParam(c, "limit")
+	}
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid pagination parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
 	// Get bank groups from service
-	bankGroups, err := h.bankGroupService.GetBankGroups(c.Request.Context())
+	bankGroups, pagination, err := h.bankGroupService.GetBankGroups(c.Request.Context(), filters)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid sort") {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(err.Error()),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
 		if log, ok := logger.GetLogger(c); ok {
 			log.Error("failed to retrieve bank groups",
 				"error", err,
@@ -44,8 +101,54 @@ func (h *BankGroupHandler) GetBankGroups(c *gin.Context) {
 
 	// Return successful response
 	response := models.APIResponse[[]models.BankGroup]{
+		Success:    true,
+		Data:       bankGroups,
+		Pagination: pagination,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBankGroupDetails returns a bank group with the number of its banks per country and environment.
+// No Last-Modified is set: the counts change without the group being updated.
+func (h *BankGroupHandler) GetBankGroupDetails(c *gin.Context) {
+	groupID := c.Param("groupId")
+
+	details, err := h.bankGroupService.GetBankGroupDetails(c.Request.Context(), groupID)
+	if err != nil {
+		var statusCode int
+		var errorMessage string
+
+		switch {
+		case strings.Contains(err.Error(), "invalid"):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+			errorMessage = "Bank group not found"
+		default:
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("failed to retrieve bank group details",
+					"error", err,
+					"group_id", groupID,
+				)
+			}
+			statusCode = http.StatusInternalServerError
+			errorMessage = "Failed to retrieve bank group details"
+		}
+
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(errorMessage),
+		}
+		c.JSON(statusCode, response)
+		return
+	}
+
+	setVersionETag(c, details.Version)
+	response := models.APIResponse[*models.BankGroupDetails]{
 		Success: true,
-		Data:    bankGroups,
+		Data:    details,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -103,6 +206,7 @@ func (h *BankGroupHandler) CreateBankGroup(c *gin.Context) {
 		return
 	}
 
+	setVersionETag(c, bankGroup.Version)
 	response := models.APIResponse[*models.BankGroup]{
 		Success: true,
 		Data:    bankGroup,
@@ -148,7 +252,7 @@ func (h *BankGroupHandler) UpdateBankGroup(c *gin.Context) {
 	}
 
 	// Call service to update bank group
-	bankGroup, err := h.updaterService.UpdateBankGroup(c.Request.Context(), groupID, &request)
+	bankGroup, err := h.updaterService.UpdateBankGroup(c.Request.Context(), groupID, &request, parseIfMatch(c))
 	if err != nil {
 		if log, ok := logger.GetLogger(c); ok {
 			log.Error("failed to update bank group",
@@ -162,6 +266,9 @@ func (h *BankGroupHandler) UpdateBankGroup(c *gin.Context) {
 		var errorMessage string
 
 		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			statusCode = versionConflictStatus(c)
+			errorMessage = "Bank group has been modified"
 		case strings.Contains(err.Error(), "not found"):
 			statusCode = http.StatusNotFound
 			errorMessage = "Bank group not found"
@@ -183,6 +290,7 @@ func (h *BankGroupHandler) UpdateBankGroup(c *gin.Context) {
 		return
 	}
 
+	setVersionETag(c, bankGroup.Version)
 	response := models.APIResponse[*models.BankGroup]{
 		Success: true,
 		Data:    bankGroup,
@@ -38,6 +38,7 @@ func TestBankGroupHandler_CreateBankGroup_Success(t *testing.T) {
 	expectedGroup := &models.BankGroup{
 		GroupID: validUUID,
 		Name:    "Test Group",
+		Version: 1,
 	}
 
 	mockCreatorService.On("CreateBankGroup", mock.Anything, mock.MatchedBy(func(req *services.CreateBankGroupRequest) bool {
@@ -59,6 +60,7 @@ func TestBankGroupHandler_CreateBankGroup_Success(t *testing.T) {
 
 	// Assert response
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
 
 	var response models.APIResponse[*models.BankGroup]
 	err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -67,6 +69,7 @@ func TestBankGroupHandler_CreateBankGroup_Success(t *testing.T) {
 	assert.NotNil(t, response.Data)
 	assert.Equal(t, "Test Group", response.Data.Name)
 	assert.Equal(t, validUUID, response.Data.GroupID)
+	assert.Equal(t, int64(1), response.Data.Version)
 
 	mockCreatorService.AssertExpectations(t)
 }
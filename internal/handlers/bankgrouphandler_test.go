@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
 	"github.com/wukong0111/go-banks/internal/services"
 )
 
@@ -23,9 +24,21 @@ type MockBankGroupService struct {
 	mock.Mock
 }
 
-func (m *MockBankGroupService) GetBankGroups(ctx context.Context) ([]models.BankGroup, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]models.BankGroup), args.Error(1)
+func (m *MockBankGroupService) GetBankGroups(ctx context.Context, filters *repository.BankGroupFilters) ([]models.BankGroup, *models.Pagination, error) {
+	args := m.Called(ctx, filters)
+	var pagination *models.Pagination
+	if args.Get(1) != nil {
+		pagination = args.Get(1).(*models.Pagination)
+	}
+	return args.Get(0).([]models.BankGroup), pagination, args.Error(2)
+}
+
+func (m *MockBankGroupService) GetBankGroupDetails(ctx context.Context, groupID string) (*models.BankGroupDetails, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroupDetails), args.Error(1)
 }
 
 type MockBankGroupCreator struct {
@@ -43,7 +56,7 @@ func (m *MockBankGroupCreator) CreateBankGroup(ctx context.Context, request *ser
 // Simple dummy updater mock for these tests
 type dummyUpdaterService struct{}
 
-func (d *dummyUpdaterService) UpdateBankGroup(_ context.Context, _ string, _ *services.UpdateBankGroupRequest) (*models.BankGroup, error) {
+func (d *dummyUpdaterService) UpdateBankGroup(_ context.Context, _ string, _ *services.UpdateBankGroupRequest, _ services.VersionPrecondition) (*models.BankGroup, error) {
 	return nil, nil
 }
 
@@ -82,7 +95,7 @@ func TestBankGroupHandler_GetBankGroups_Success(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockService.On("GetBankGroups", mock.Anything).Return(expectedGroups, nil)
+	mockService.On("GetBankGroups", mock.Anything, mock.Anything).Return(expectedGroups, nil, nil)
 
 	// Create test request
 	w := httptest.NewRecorder()
@@ -114,7 +127,7 @@ func TestBankGroupHandler_GetBankGroups_EmptyResult(t *testing.T) {
 	handler := NewBankGroupHandler(mockService, mockCreatorService, mockUpdaterService)
 
 	// Setup mock expectations
-	mockService.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{}, nil)
+	mockService.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{}, nil, nil)
 
 	// Create test request
 	w := httptest.NewRecorder()
@@ -148,7 +161,7 @@ func TestBankGroupHandler_GetBankGroups_ServiceError(t *testing.T) {
 	expectedError := errors.New("database connection failed")
 
 	// Setup mock expectations
-	mockService.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{}, expectedError)
+	mockService.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{}, nil, expectedError)
 
 	// Create test request
 	w := httptest.NewRecorder()
@@ -170,3 +183,159 @@ func TestBankGroupHandler_GetBankGroups_ServiceError(t *testing.T) {
 	assert.Equal(t, "Failed to retrieve bank groups", *response.Error)
 	mockService.AssertExpectations(t)
 }
+
+func TestBankGroupHandler_GetBankGroups_InvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankGroupService)
+	mockCreatorService := new(MockBankGroupCreator)
+	mockUpdaterService := &dummyUpdaterService{}
+	handler := NewBankGroupHandler(mockService, mockCreatorService, mockUpdaterService)
+
+	mockService.On("GetBankGroups", mock.Anything, mock.MatchedBy(func(filters *repository.BankGroupFilters) bool {
+		return len(filters.Sort) == 1 && filters.Sort[0].Field == "country"
+	})).Return([]models.BankGroup{}, nil, errors.New("invalid sort field: country"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/bank-groups?sort=country", http.NoBody)
+
+	handler.GetBankGroups(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response models.APIResponse[any]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Error)
+	assert.Contains(t, *response.Error, "invalid sort field")
+	mockService.AssertExpectations(t)
+}
+
+func TestBankGroupHandler_GetBankGroups_Paginated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankGroupService)
+	handler := NewBankGroupHandler(mockService, new(MockBankGroupCreator), &dummyUpdaterService{})
+
+	groups := []models.BankGroup{{GroupID: uuid.New(), Name: "Santander Group"}}
+	pagination := &models.Pagination{Page: 2, Limit: 1, Total: 3, TotalPages: 3}
+
+	mockService.On("GetBankGroups", mock.Anything, mock.MatchedBy(func(filters *repository.BankGroupFilters) bool {
+		return filters.Name == "santander" && filters.Page == 2 && filters.Limit == 1
+	})).Return(groups, pagination, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/bank-groups?name=santander&page=2&limit=1", http.NoBody)
+
+	handler.GetBankGroups(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.BankGroup]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, pagination, response.Pagination)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankGroupHandler_GetBankGroups_InvalidPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{"page=first", "limit=ten"} {
+		t.Run(query, func(t *testing.T) {
+			mockService := new(MockBankGroupService)
+			handler := NewBankGroupHandler(mockService, new(MockBankGroupCreator), &dummyUpdaterService{})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/bank-groups?"+query, http.NoBody)
+
+			handler.GetBankGroups(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, "must be a number")
+			mockService.AssertNotCalled(t, "GetBankGroups", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankGroupHandler_GetBankGroupDetails_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankGroupService)
+	handler := NewBankGroupHandler(mockService, new(MockBankGroupCreator), &dummyUpdaterService{})
+
+	router := gin.New()
+	router.GET("/bank-groups/:groupId", handler.GetBankGroupDetails)
+
+	groupID := uuid.New()
+	details := &models.BankGroupDetails{
+		BankGroup: models.BankGroup{GroupID: groupID, Name: "BBVA Group"},
+		MemberCounts: models.BankGroupMemberCounts{
+			Total:         2,
+			ByCountry:     map[string]int{"ES": 2},
+			ByEnvironment: map[string]int{"production": 2},
+		},
+	}
+	mockService.On("GetBankGroupDetails", mock.Anything, groupID.String()).Return(details, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/bank-groups/"+groupID.String(), http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.BankGroupDetails]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, groupID, response.Data.GroupID)
+	assert.Equal(t, details.MemberCounts, response.Data.MemberCounts)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankGroupHandler_GetBankGroupDetails_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"invalid group ID", errors.New("invalid group_id: invalid UUID length: 3"), http.StatusBadRequest, "invalid group_id"},
+		{"unknown group", errors.New("bank group with ID 'x' not found"), http.StatusNotFound, "Bank group not found"},
+		{"repository failure", errors.New("failed to count banks per country: timeout"), http.StatusInternalServerError, "Failed to retrieve bank group details"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankGroupService)
+			handler := NewBankGroupHandler(mockService, new(MockBankGroupCreator), &dummyUpdaterService{})
+
+			router := gin.New()
+			router.GET("/bank-groups/:groupId", handler.GetBankGroupDetails)
+
+			mockService.On("GetBankGroupDetails", mock.Anything, "abc").Return(nil, tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodGet, "/bank-groups/abc", http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
 	"github.com/wukong0111/go-banks/internal/services"
 )
 
@@ -23,7 +24,7 @@ type MockBankGroupUpdaterService struct {
 	mock.Mock
 }
 
-func (m *MockBankGroupUpdaterService) UpdateBankGroup(ctx context.Context, groupID string, request *services.UpdateBankGroupRequest) (*models.BankGroup, error) {
+func (m *MockBankGroupUpdaterService) UpdateBankGroup(ctx context.Context, groupID string, request *services.UpdateBankGroupRequest, _ services.VersionPrecondition) (*models.BankGroup, error) {
 	args := m.Called(ctx, groupID, request)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -46,7 +47,11 @@ func setupUpdateTestRouter(updaterService services.BankGroupUpdater) *gin.Engine
 
 type MockTestBankGroupService struct{}
 
-func (m *MockTestBankGroupService) GetBankGroups(_ context.Context) ([]models.BankGroup, error) {
+func (m *MockTestBankGroupService) GetBankGroups(_ context.Context, _ *repository.BankGroupFilters) ([]models.BankGroup, *models.Pagination, error) {
+	return nil, nil, nil
+}
+
+func (m *MockTestBankGroupService) GetBankGroupDetails(_ context.Context, _ string) (*models.BankGroupDetails, error) {
 	return nil, nil
 }
 
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type BankGroupPatcherHandler struct {
+	patcherService services.BankGroupPatcher
+}
+
+func NewBankGroupPatcherHandler(patcherService services.BankGroupPatcher) *BankGroupPatcherHandler {
+	return &BankGroupPatcherHandler{
+		patcherService: patcherService,
+	}
+}
+
+// PatchBankGroup applies a JSON Merge Patch or JSON Patch document, selected by Content-Type, to a bank group
+func (h *BankGroupPatcherHandler) PatchBankGroup(c *gin.Context) {
+	groupID := c.Param("groupId")
+
+	mediaType, patch, ok := readPatchRequest(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.patcherService.PatchBankGroup(c.Request.Context(), groupID, mediaType, patch, parseIfMatch(c))
+	if err != nil {
+		handlePatchError(c, err, "bank group", groupID)
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank group patched",
+			"group_id", groupID,
+			"content_type", mediaType,
+		)
+	}
+
+	setVersionETag(c, group.Version)
+	response := models.APIResponse[*models.BankGroup]{
+		Success: true,
+		Data:    group,
+	}
+	c.JSON(http.StatusOK, response)
+}
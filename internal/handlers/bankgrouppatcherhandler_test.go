@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockBankGroupPatcher implements the BankGroupPatcher interface for testing
+type MockBankGroupPatcher struct {
+	mock.Mock
+}
+
+func (m *MockBankGroupPatcher) PatchBankGroup(ctx context.Context, groupID, mediaType string, patch []byte, precondition services.VersionPrecondition) (*models.BankGroup, error) {
+	args := m.Called(ctx, groupID, mediaType, patch, precondition)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroup), args.Error(1)
+}
+
+func newBankGroupPatcherRouter(handler *BankGroupPatcherHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/bank-groups/:groupId", handler.PatchBankGroup)
+	return router
+}
+
+func TestBankGroupPatcherHandler_PatchBankGroup(t *testing.T) {
+	mockService := new(MockBankGroupPatcher)
+	router := newBankGroupPatcherRouter(NewBankGroupPatcherHandler(mockService))
+
+	groupID := uuid.New()
+	patch := `{"logo_url":null}`
+	mockService.On("PatchBankGroup", mock.Anything, groupID.String(), jsonpatch.MergePatchMediaType, []byte(patch), services.VersionPrecondition(nil)).
+		Return(&models.BankGroup{GroupID: groupID, Name: "Santander Group"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/bank-groups/"+groupID.String(), strings.NewReader(patch))
+	req.Header.Set("Content-Type", jsonpatch.MergePatchMediaType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.BankGroup]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, groupID, response.Data.GroupID)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankGroupPatcherHandler_PatchBankGroup_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"invalid group ID", errors.New("invalid group_id: invalid UUID length: 3"), http.StatusBadRequest, "invalid group_id"},
+		{"group missing", errors.New("bank group with ID 'x' not found"), http.StatusNotFound, "Bank group not found"},
+		{"writer failure", errors.New("failed to update bank group: timeout"), http.StatusInternalServerError, "Failed to patch bank group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankGroupPatcher)
+			router := newBankGroupPatcherRouter(NewBankGroupPatcherHandler(mockService))
+
+			mockService.On("PatchBankGroup", mock.Anything, "abc", jsonpatch.JSONPatchMediaType, mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodPatch, "/bank-groups/abc", strings.NewReader(`[]`))
+			req.Header.Set("Content-Type", jsonpatch.JSONPatchMediaType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
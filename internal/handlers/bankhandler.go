@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/middleware"
 	"github.com/wukong0111/go-banks/internal/models"
 	"github.com/wukong0111/go-banks/internal/repository"
 	"github.com/wukong0111/go-banks/internal/services"
@@ -23,18 +26,94 @@ func NewBankHandler(bankService services.BankService) *BankHandler {
 	}
 }
 
+// GetBanks lists the banks matching the filters, page by page or, with ?cursor=, by cursor.
+// No Last-Modified is set: deletions and banks leaving the filters do not advance the newest
+// updated_at of the page, so clients revalidate listings with the ETag.
 func (h *BankHandler) GetBanks(c *gin.Context) {
 	// Parse query parameters - no defaults or validation, just HTTP parsing
-	filters := &repository.BankFilters{
-		Environment: c.Query("env"),
-		Name:        c.Query("name"),
-		API:         c.Query("api"),
-		Country:     c.Query("country"),
+	filters, err := parseBankFilters(c)
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid filter parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	h.listBanks(c, filters)
+}
+
+// GetBankGroupBanks lists the banks of a bank group, with the same filters, sorting and
+// pagination as GetBanks
+func (h *BankHandler) GetBankGroupBanks(c *gin.Context) {
+	groupID := c.Param("groupId")
+
+	filters, err := parseBankFilters(c)
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid filter parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
 	}
 
+	if _, err := h.bankService.GetBankGroup(c.Request.Context(), groupID); err != nil {
+		var statusCode int
+		var errorMessage string
+
+		switch {
+		case strings.Contains(err.Error(), "invalid"):
+			statusCode = http.StatusBadRequest
+			errorMessage = err.Error()
+		case strings.Contains(err.Error(), "not found"):
+			statusCode = http.StatusNotFound
+			errorMessage = "Bank group not found"
+		default:
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("failed to retrieve bank group",
+					"error", err,
+					"group_id", groupID,
+				)
+			}
+			statusCode = http.StatusInternalServerError
+			errorMessage = "Failed to retrieve bank group"
+		}
+
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(errorMessage),
+		}
+		c.JSON(statusCode, response)
+		return
+	}
+
+	filters.BankGroupID = groupID
+	h.listBanks(c, filters)
+}
+
+// listBanks parses the pagination, sorting and representation parameters and writes the
+// page of banks matching filters
+func (h *BankHandler) listBanks(c *gin.Context, filters *repository.BankFilters) {
+	var err error
+
 	// Parse and validate pagination parameters
 	var page, limit int
-	var err error
 
 	// Validate page parameter if provided
 	if pageStr, exists := c.GetQuery("page"); exists {
@@ -83,9 +162,59 @@ func (h *BankHandler) GetBanks(c *gin.Context) {
 	filters.Page = page
 	filters.Limit = limit
 
+	sort, err := parseSortParam(c.Query("sort"))
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid sort parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	filters.Sort = sort
+
+	representation := parseBankRepresentation(c)
+	if err := representation.Validate(); err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid bank representation parameters",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Keyset pagination is selected by the presence of the cursor parameter (empty for the first page)
+	if cursor, exists := c.GetQuery("cursor"); exists {
+		h.getBanksByCursor(c, filters, cursor, representation)
+		return
+	}
+
 	// Get banks from service
 	banks, pagination, err := h.bankService.GetBanks(c.Request.Context(), filters)
 	if err != nil {
+		if isInvalidBankFiltersError(err) {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(err.Error()),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
 		if log, ok := logger.GetLogger(c); ok {
 			log.Error("failed to retrieve banks",
 				"error", err,
@@ -100,6 +229,19 @@ func (h *BankHandler) GetBanks(c *gin.Context) {
 		return
 	}
 
+	if !representation.IsDefault() {
+		rendered, ok := h.renderBanks(c, banks, filters.Environment, representation)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, models.APIResponse[[]map[string]any]{
+			Success:    true,
+			Data:       rendered,
+			Pagination: pagination,
+		})
+		return
+	}
+
 	// Return successful response
 	response := models.APIResponse[[]models.Bank]{
 		Success:    true,
@@ -110,6 +252,387 @@ func (h *BankHandler) GetBanks(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// getBanksByCursor serves GET /api/banks in keyset pagination mode
+func (h *BankHandler) getBanksByCursor(c *gin.Context, filters *repository.BankFilters, cursor string, representation *services.BankRepresentation) {
+	if _, exists := c.GetQuery("page"); exists {
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid page parameter: cannot be combined with cursor"),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	filters.Cursor = cursor
+
+	if includeTotalStr, exists := c.GetQuery("include_total"); exists {
+		includeTotal, err := strconv.ParseBool(includeTotalStr)
+		if err != nil {
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid include_total parameter",
+					"error", err.Error(),
+					"include_total_value", includeTotalStr,
+					"remote_addr", c.ClientIP(),
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Invalid include_total parameter: must be a boolean"),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		filters.IncludeTotal = includeTotal
+	}
+
+	banks, pagination, err := h.bankService.GetBanksByCursor(c.Request.Context(), filters)
+	if err != nil {
+		if isInvalidBankFiltersError(err) {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(err.Error()),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if strings.Contains(err.Error(), "invalid cursor") {
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid cursor parameter",
+					"error", err.Error(),
+					"remote_addr", c.ClientIP(),
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Invalid cursor parameter"),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to retrieve banks",
+				"error", err,
+				"filters", filters,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to retrieve banks"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !representation.IsDefault() {
+		rendered, ok := h.renderBanks(c, banks, filters.Environment, representation)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, models.APIResponse[[]map[string]any]{
+			Success:          true,
+			Data:             rendered,
+			CursorPagination: pagination,
+		})
+		return
+	}
+
+	response := models.APIResponse[[]models.Bank]{
+		Success:          true,
+		Data:             banks,
+		CursorPagination: pagination,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// exportFlushInterval is the number of banks written between flushes of an export stream
+const exportFlushInterval = 100
+
+// ExportBanks streams every bank matching the GetBanks filters as CSV or NDJSON, without pagination
+func (h *BankHandler) ExportBanks(c *gin.Context) {
+	filters, err := parseBankFilters(c)
+	if err == nil {
+		filters.Sort, err = parseSortParam(c.Query("sort"))
+	}
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid export parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	format := c.DefaultQuery("format", services.ExportFormatCSV)
+	writer, err := services.NewBankExportWriter(format, c.Writer)
+	if err != nil {
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Headers are only sent once the service has accepted the filters, so that
+	// validation and early database errors can still be answered with JSON
+	started := false
+	start := func() error {
+		started = true
+		// A full export may outlive the server write timeout, which is sized for regular responses
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		c.Header("Content-Type", services.ExportContentType(format))
+		c.Header("Content-Disposition", `attachment; filename="banks.`+format+`"`)
+		c.Status(http.StatusOK)
+		return writer.WriteHeader()
+	}
+
+	exported := 0
+	err = h.bankService.ExportBanks(c.Request.Context(), filters, func(bank *models.BankWithEnvironments) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.WriteBank(bank); err != nil {
+			return err
+		}
+		exported++
+		if exported%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if err != nil {
+		if started {
+			// The status line is already on the wire; the truncated body is all we can signal
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("bank export interrupted",
+					"error", err,
+					"format", format,
+					"exported_banks", exported,
+				)
+			}
+			c.Abort()
+			return
+		}
+
+		if isInvalidBankFiltersError(err) {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(err.Error()),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to export banks",
+				"error", err,
+				"filters", filters,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to export banks"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+}
+
+func (h *BankHandler) BatchGetBanks(c *gin.Context) {
+	var request services.BatchGetBanksRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid JSON request format",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"path", c.Request.URL.Path,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid request format"),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	batch, err := h.bankService.BatchGetBanks(c.Request.Context(), &request)
+	if err != nil {
+		errorMessage := err.Error()
+		if strings.Contains(errorMessage, "invalid request") || strings.Contains(errorMessage, "invalid environment") {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(errorMessage),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to batch get banks",
+				"error", err,
+				"bank_id_count", len(request.BankIDs),
+				"environment", request.Environment,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to retrieve banks"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := models.APIResponse[*models.BankBatch]{
+		Success: true,
+		Data:    batch,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// setBankDetailsLastModified reports the most recent modification of a bank and its environment configs
+func setBankDetailsLastModified(c *gin.Context, details models.BankDetails) {
+	middleware.SetLastModified(c, details.GetBank().UpdatedAt)
+
+	switch d := details.(type) {
+	case *models.BankWithEnvironment:
+		if d.EnvironmentConfig != nil {
+			middleware.SetLastModified(c, d.EnvironmentConfig.UpdatedAt)
+		}
+		setEffectiveStatusLastModified(c, d.EffectiveStatus)
+	case *models.BankWithEnvironments:
+		for _, config := range d.EnvironmentConfigs {
+			middleware.SetLastModified(c, config.UpdatedAt)
+		}
+		for _, status := range d.EffectiveStatuses {
+			setEffectiveStatusLastModified(c, status)
+		}
+	}
+}
+
+// setEffectiveStatusLastModified reports the last time a maintenance window started or ended, which
+// changes the effective status without changing any stored row
+func setEffectiveStatusLastModified(c *gin.Context, status *models.EffectiveStatus) {
+	if status != nil && status.ChangedAt != nil {
+		middleware.SetLastModified(c, *status.ChangedAt)
+	}
+}
+
+// parseBankFilters reads the filter query parameters shared by the bank listings.
+// Multi-value filters accept repeated and comma-separated values.
+func parseBankFilters(c *gin.Context) (*repository.BankFilters, error) {
+	filters := &repository.BankFilters{
+		Environment: c.Query("env"),
+		Name:        c.Query("name"),
+		APIs:        parseMultiValueParam(c, "api"),
+		Countries:   parseMultiValueParam(c, "country"),
+		BankGroupID: c.Query("bank_group_id"),
+		Query:       c.Query("q"),
+	}
+
+	var err error
+	if filters.AuthTypeChoiceRequired, err = parseOptionalBoolParam(c, "auth_type_choice_required"); err != nil {
+		return nil, err
+	}
+	if filters.HasBIC, err = parseOptionalBoolParam(c, "has_bic"); err != nil {
+		return nil, err
+	}
+	if filters.UpdatedSince, err = parseOptionalTimeParam(c, "updated_since"); err != nil {
+		return nil, err
+	}
+
+	state := &filters.EnvironmentState
+	state.EffectiveStatus = c.Query("effective_status")
+	stateParams := []struct {
+		key   string
+		value **bool
+	}{
+		{key: "enabled", value: &state.Enabled},
+		{key: "blocked", value: &state.Blocked},
+		{key: "risky", value: &state.Risky},
+		{key: "instant", value: &state.SupportsInstantPayments},
+		{key: "supports_instant_payments", value: &state.SupportsInstantPayments},
+		{key: "instant_payments_activated", value: &state.InstantPaymentsActivated},
+		{key: "enabled_periodic_payment", value: &state.EnabledPeriodicPayment},
+		{key: "app_auth_setup_required", value: &state.AppAuthSetupRequired},
+	}
+	for _, param := range stateParams {
+		value, err := parseOptionalBoolParam(c, param.key)
+		if err != nil {
+			return nil, err
+		}
+		// instant is a short alias of supports_instant_payments; the long name wins when both are sent
+		if value != nil {
+			*param.value = value
+		}
+	}
+
+	return filters, nil
+}
+
+// isInvalidBankFiltersError reports whether a listing error was caused by client-provided filters
+func isInvalidBankFiltersError(err error) bool {
+	errorMessage := err.Error()
+	return strings.Contains(errorMessage, "invalid sort") || strings.Contains(errorMessage, "invalid q") ||
+		strings.Contains(errorMessage, "invalid bank_group_id") || strings.Contains(errorMessage, "invalid effective_status")
+}
+
+// parseBankRepresentation reads the fields and include query parameters
+func parseBankRepresentation(c *gin.Context) *services.BankRepresentation {
+	return &services.BankRepresentation{
+		Fields:  parseListParam(c.Query("fields")),
+		Include: parseListParam(c.Query("include")),
+	}
+}
+
+// renderBanks shapes a page of banks, writing an error response and returning false on failure
+func (h *BankHandler) renderBanks(c *gin.Context, banks []models.Bank, environment string, representation *services.BankRepresentation) ([]map[string]any, bool) {
+	rendered, err := h.bankService.RenderBanks(c.Request.Context(), banks, environment, representation)
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to render banks",
+				"error", err,
+				"fields", representation.Fields,
+				"include", representation.Include,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to retrieve banks"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return nil, false
+	}
+	return rendered, true
+}
+
 func (h *BankHandler) GetBankDetails(c *gin.Context) {
 	// Extract bank ID from path parameter
 	bankID := c.Param("bankId")
@@ -133,6 +656,16 @@ func (h *BankHandler) GetBankDetails(c *gin.Context) {
 	// Extract optional environment parameter
 	environment := c.Query("env")
 
+	representation := parseBankRepresentation(c)
+	if err := representation.Validate(); err != nil {
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
 	// Get bank details from service
 	bankDetails, err := h.bankService.GetBankDetails(c.Request.Context(), bankID, environment)
 	if err != nil {
@@ -179,6 +712,32 @@ func (h *BankHandler) GetBankDetails(c *gin.Context) {
 		return
 	}
 
+	setBankDetailsLastModified(c, bankDetails)
+	setVersionETag(c, bankDetails.GetBank().Version)
+
+	if !representation.IsDefault() {
+		rendered, err := h.bankService.RenderBankDetails(c.Request.Context(), bankDetails, representation)
+		if err != nil {
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("failed to render bank details",
+					"error", err,
+					"bank_id", bankID,
+				)
+			}
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Failed to retrieve bank details"),
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		c.JSON(http.StatusOK, models.APIResponse[map[string]any]{
+			Success: true,
+			Data:    rendered,
+		})
+		return
+	}
+
 	// Return successful response
 	response := models.APIResponse[any]{
 		Success: true,
@@ -188,6 +747,64 @@ func (h *BankHandler) GetBankDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *BankHandler) LookupBanks(c *gin.Context) {
+	lookup := &services.BankLookupRequest{
+		BIC:     c.Query("bic"),
+		Code:    c.Query("code"),
+		Country: c.Query("country"),
+	}
+
+	banks, err := h.bankService.LookupBanks(c.Request.Context(), lookup)
+	if err != nil {
+		errorMessage := err.Error()
+		switch {
+		case strings.Contains(errorMessage, "invalid"):
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid bank lookup parameters",
+					"error", errorMessage,
+					"remote_addr", c.ClientIP(),
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(errorMessage),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		case strings.Contains(errorMessage, "bank not found"):
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Bank not found"),
+			}
+			c.JSON(http.StatusNotFound, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to lookup banks",
+				"error", err,
+				"bic", lookup.BIC,
+				"code", lookup.Code,
+				"country", lookup.Country,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to lookup banks"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := models.APIResponse[[]models.BankWithEnvironments]{
+		Success: true,
+		Data:    banks,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
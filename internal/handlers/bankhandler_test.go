@@ -3,16 +3,25 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/middleware"
 	"github.com/wukong0111/go-banks/internal/models"
 	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
 )
 
 // MockBankService is a mock implementation of the BankService for testing.
@@ -25,6 +34,14 @@ func (m *MockBankService) GetBanks(ctx context.Context, filters *repository.Bank
 	return args.Get(0).([]models.Bank), args.Get(1).(*models.Pagination), args.Error(2)
 }
 
+func (m *MockBankService) GetBanksByCursor(ctx context.Context, filters *repository.BankFilters) ([]models.Bank, *models.CursorPagination, error) {
+	args := m.Called(ctx, filters)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]models.Bank), args.Get(1).(*models.CursorPagination), args.Error(2)
+}
+
 func (m *MockBankService) GetBankDetails(ctx context.Context, bankID, environment string) (models.BankDetails, error) {
 	args := m.Called(ctx, bankID, environment)
 	// Handle the case where the first argument is nil
@@ -34,6 +51,58 @@ func (m *MockBankService) GetBankDetails(ctx context.Context, bankID, environmen
 	return args.Get(0).(models.BankDetails), args.Error(1)
 }
 
+func (m *MockBankService) GetBankGroup(ctx context.Context, groupID string) (*models.BankGroup, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroup), args.Error(1)
+}
+
+func (m *MockBankService) RenderBanks(ctx context.Context, banks []models.Bank, environment string, representation *services.BankRepresentation) ([]map[string]any, error) {
+	args := m.Called(ctx, banks, environment, representation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]map[string]any), args.Error(1)
+}
+
+func (m *MockBankService) RenderBankDetails(ctx context.Context, details models.BankDetails, representation *services.BankRepresentation) (map[string]any, error) {
+	args := m.Called(ctx, details, representation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]any), args.Error(1)
+}
+
+func (m *MockBankService) ExportBanks(ctx context.Context, filters *repository.BankFilters, fn func(bank *models.BankWithEnvironments) error) error {
+	args := m.Called(ctx, filters, fn)
+	if banks, ok := args.Get(0).([]models.BankWithEnvironments); ok {
+		for i := range banks {
+			if err := fn(&banks[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockBankService) BatchGetBanks(ctx context.Context, request *services.BatchGetBanksRequest) (*models.BankBatch, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankBatch), args.Error(1)
+}
+
+func (m *MockBankService) LookupBanks(ctx context.Context, lookup *services.BankLookupRequest) ([]models.BankWithEnvironments, error) {
+	args := m.Called(ctx, lookup)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BankWithEnvironments), args.Error(1)
+}
+
 func TestBankHandler_GetBanks(t *testing.T) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
@@ -87,3 +156,876 @@ func TestBankHandler_GetBanks(t *testing.T) {
 	// Assert that the mock service was called
 	mockService.AssertExpectations(t)
 }
+
+func TestBankHandler_GetBanks_CursorMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	nextCursor := "eyJuIjoiQmFuayBCIiwiaWQiOiIyIn0"
+	total := 5
+	expectedBanks := []models.Bank{
+		{BankID: "1", Name: "Bank A"},
+		{BankID: "2", Name: "Bank B"},
+	}
+	expectedPagination := &models.CursorPagination{
+		Limit:      2,
+		NextCursor: &nextCursor,
+		HasMore:    true,
+		Total:      &total,
+	}
+
+	mockService.On("GetBanksByCursor", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return filters.Cursor == "" && filters.IncludeTotal && filters.Limit == 2
+	})).Return(expectedBanks, expectedPagination, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?cursor=&limit=2&include_total=true", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.Bank]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	assert.True(t, response.Success)
+	assert.Equal(t, expectedBanks, response.Data)
+	assert.Nil(t, response.Pagination)
+	assert.Equal(t, expectedPagination, response.CursorPagination)
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+}
+
+func TestBankHandler_GetBanks_CursorMode_InvalidCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	mockService.On("GetBanksByCursor", mock.Anything, mock.Anything).Return(nil, nil, errors.New("invalid cursor"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?cursor=not-a-cursor", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_CursorMode_WithQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	mockService.On("GetBanksByCursor", mock.Anything, mock.Anything).
+		Return(nil, nil, errors.New("invalid q: full-text search is not supported with cursor pagination"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?cursor=&q=santander", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_CursorMode_WithPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?cursor=&page=2", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetBanksByCursor", mock.Anything, mock.Anything)
+}
+
+func TestBankHandler_GetBanks_Sort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	mockService.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return len(filters.Sort) == 2 &&
+			filters.Sort[0] == repository.SortField{Field: "country"} &&
+			filters.Sort[1] == repository.SortField{Field: "updated_at", Descending: true}
+	})).Return([]models.Bank{}, &models.Pagination{Page: 1, Limit: 20}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?sort=country,-updated_at", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_InvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+	}{
+		{name: "unknown field", query: "sort=password", serviceErr: errors.New("invalid sort field: password")},
+		{name: "empty field", query: "sort=name,,country"},
+		{name: "duplicated field", query: "sort=name,-name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.GET("/banks", handler.GetBanks)
+
+			if tt.serviceErr != nil {
+				mockService.On("GetBanks", mock.Anything, mock.Anything).Return([]models.Bank(nil), (*models.Pagination)(nil), tt.serviceErr)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankHandler_GetBanks_FieldsAndInclude(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	banks := []models.Bank{{BankID: "BES2100", Name: "CaixaBank"}}
+	rendered := []map[string]any{{"bank_id": "BES2100", "name": "CaixaBank", "environment_configs": map[string]any{}}}
+
+	mockService.On("GetBanks", mock.Anything, mock.Anything).Return(banks, &models.Pagination{Page: 1, Limit: 20, Total: 1, TotalPages: 1}, nil)
+	mockService.On("RenderBanks", mock.Anything, banks, "sandbox", &services.BankRepresentation{
+		Fields:  []string{"bank_id", "name"},
+		Include: []string{"environment_configs"},
+	}).Return(rendered, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?env=sandbox&fields=bank_id,name&include=environment_configs", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]map[string]any]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Len(t, response.Data, 1)
+	assert.NotContains(t, response.Data[0], "country")
+	assert.Contains(t, response.Data[0], "environment_configs")
+	assert.NotNil(t, response.Pagination)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_InvalidRepresentation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{"fields=secret", "include=owner"} {
+		t.Run(query, func(t *testing.T) {
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.GET("/banks", handler.GetBanks)
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankHandler_GetBankDetails_IncludeBankGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks/:bankId/details", handler.GetBankDetails)
+
+	details := &models.BankWithEnvironments{Bank: models.Bank{BankID: "BES2100"}}
+	mockService.On("GetBankDetails", mock.Anything, "BES2100", "").Return(details, nil)
+	mockService.On("RenderBankDetails", mock.Anything, details, &services.BankRepresentation{
+		Include: []string{"bank_group"},
+	}).Return(map[string]any{"bank_id": "BES2100", "bank_group": nil}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES2100/details?include=bank_group", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"bank_group":null`)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_ExtendedFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	updatedSince := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	mockService.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return slices.Equal(filters.Countries, []string{"ES", "PT", "FR"}) &&
+			slices.Equal(filters.APIs, []string{"berlin_group", "stet"}) &&
+			filters.BankGroupID == "550e8400-e29b-41d4-a716-446655440000" &&
+			filters.AuthTypeChoiceRequired != nil && *filters.AuthTypeChoiceRequired &&
+			filters.HasBIC != nil && !*filters.HasBIC &&
+			filters.UpdatedSince != nil && filters.UpdatedSince.Equal(updatedSince)
+	})).Return([]models.Bank{}, &models.Pagination{Page: 1, Limit: 20}, nil)
+
+	query := "country=ES,PT&country=FR&api=berlin_group,stet&bank_group_id=550e8400-e29b-41d4-a716-446655440000" +
+		"&auth_type_choice_required=true&has_bic=false&updated_since=2025-01-15T10:00:00Z"
+	req, _ := http.NewRequest(http.MethodGet, "/banks?"+query, http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_InvalidExtendedFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{"has_bic=maybe", "auth_type_choice_required=2", "updated_since=yesterday"} {
+		t.Run(query, func(t *testing.T) {
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.GET("/banks", handler.GetBanks)
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks?"+query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankHandler_GetBanks_EnvironmentStateFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	mockService.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		state := filters.EnvironmentState
+		return filters.Environment == "production" &&
+			state.Enabled != nil && *state.Enabled &&
+			state.Blocked != nil && !*state.Blocked &&
+			state.SupportsInstantPayments != nil && *state.SupportsInstantPayments &&
+			state.Risky == nil &&
+			state.AppAuthSetupRequired == nil
+	})).Return([]models.Bank{}, &models.Pagination{Page: 1, Limit: 20}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?env=production&enabled=true&blocked=false&instant=true", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_InvalidEnvironmentStateFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?env=production&risky=often", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid risky parameter")
+	mockService.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+}
+
+func TestBankHandler_GetBanks_InvalidBankGroupID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", handler.GetBanks)
+
+	mockService.On("GetBanks", mock.Anything, mock.Anything).Return([]models.Bank(nil), (*models.Pagination)(nil), errors.New("invalid bank_group_id: abc must be a valid UUID"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks?bank_group_id=abc", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBankDetails_ETagAsIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	mockPatcher := new(MockBankPatcher)
+
+	router := gin.New()
+	router.GET("/banks/:bankId/details", middleware.ConditionalGET("private, no-cache"), NewBankHandler(mockService).GetBankDetails)
+	router.PATCH("/banks/:bankId", NewBankPatcherHandler(mockPatcher).PatchBank)
+
+	details := &models.BankWithEnvironments{
+		Bank:               models.Bank{BankID: "BES0049", Name: "Santander", Version: 4},
+		EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{},
+	}
+	mockService.On("GetBankDetails", mock.Anything, "BES0049", "").Return(details, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/details", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `"4.`), etag)
+
+	// The tag read from the GET is a precondition on the version it was read at
+	patched := &models.Bank{BankID: "BES0049", Name: "Santander", Version: 5}
+	mockPatcher.On("PatchBank", mock.Anything, "BES0049", jsonpatch.MergePatchMediaType, mock.Anything, services.VersionPrecondition{4}).
+		Return(patched, nil)
+
+	req, _ = http.NewRequest(http.MethodPatch, "/banks/BES0049", strings.NewReader(`{"bic":null}`))
+	req.Header.Set("Content-Type", jsonpatch.MergePatchMediaType)
+	req.Header.Set("If-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+	mockPatcher.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBanks_NotModifiedAfterDeletion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks", middleware.ConditionalGET("private, no-cache"), handler.GetBanks)
+
+	updatedAt := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	santander := models.Bank{BankID: "BES0049", Name: "Santander", UpdatedAt: updatedAt}
+	sabadell := models.Bank{BankID: "BES0081", Name: "Sabadell", UpdatedAt: updatedAt}
+
+	mockService.On("GetBanks", mock.Anything, mock.Anything).
+		Return([]models.Bank{santander, sabadell}, &models.Pagination{Page: 1, Limit: 20, Total: 2, TotalPages: 1}, nil).Once()
+	// Sabadell is then deleted, which leaves the newest updated_at of the page unchanged
+	mockService.On("GetBanks", mock.Anything, mock.Anything).
+		Return([]models.Bank{santander}, &models.Pagination{Page: 1, Limit: 20, Total: 1, TotalPages: 1}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	revalidations := map[string]string{
+		"If-None-Match":     etag,
+		"If-Modified-Since": updatedAt.Add(time.Hour).Format(http.TimeFormat),
+	}
+	for header, value := range revalidations {
+		req, _ = http.NewRequest(http.MethodGet, "/banks", http.NoBody)
+		req.Header.Set(header, value)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, header)
+		assert.NotContains(t, w.Body.String(), "BES0081", header)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBankDetails_LastModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks/:bankId/details", middleware.ConditionalGET("private, no-cache"), handler.GetBankDetails)
+
+	bankUpdatedAt := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	configUpdatedAt := time.Date(2025, 1, 12, 9, 15, 0, 0, time.UTC)
+	details := &models.BankWithEnvironments{
+		Bank: models.Bank{BankID: "BES2100", UpdatedAt: bankUpdatedAt},
+		EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"production": {BankID: "BES2100", UpdatedAt: configUpdatedAt},
+		},
+	}
+	mockService.On("GetBankDetails", mock.Anything, "BES2100", "").Return(details, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES2100/details", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Sun, 12 Jan 2025 09:15:00 GMT", w.Header().Get("Last-Modified"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Revalidating with the ETag skips the body
+	req, _ = http.NewRequest(http.MethodGet, "/banks/BES2100/details", http.NoBody)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestBankHandler_LookupBanks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks/lookup", handler.LookupBanks)
+
+	expected := []models.BankWithEnvironments{
+		{
+			Bank: models.Bank{BankID: "BES2100", Name: "CaixaBank"},
+			EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+				"production": {BankID: "BES2100", Environment: models.EnvironmentProduction, Enabled: true},
+			},
+		},
+	}
+
+	mockService.On("LookupBanks", mock.Anything, &services.BankLookupRequest{Code: "2100", Country: "ES"}).Return(expected, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/lookup?code=2100&country=ES", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.BankWithEnvironments]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, expected, response.Data)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_LookupBanks_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "validation error",
+			serviceError:   errors.New("invalid BIC format: CAIX"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not found",
+			serviceError:   errors.New("bank not found"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "repository failure",
+			serviceError:   errors.New("failed to lookup banks: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.GET("/banks/lookup", handler.LookupBanks)
+
+			mockService.On("LookupBanks", mock.Anything, mock.Anything).Return(nil, tt.serviceError)
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks/lookup?bic=CAIX", http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankHandler_BatchGetBanks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.POST("/banks/batch-get", handler.BatchGetBanks)
+
+	expected := &models.BankBatch{
+		Banks: map[string]*models.BankWithEnvironments{
+			"BES2100": {
+				Bank:               models.Bank{BankID: "BES2100", Name: "CaixaBank"},
+				EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{},
+			},
+		},
+		NotFound: []string{"UNKNOWN"},
+	}
+
+	mockService.On("BatchGetBanks", mock.Anything, &services.BatchGetBanksRequest{
+		BankIDs:     []string{"BES2100", "UNKNOWN"},
+		Environment: "sandbox",
+	}).Return(expected, nil)
+
+	body := `{"bank_ids":["BES2100","UNKNOWN"],"environment":"sandbox"}`
+	req, _ := http.NewRequest(http.MethodPost, "/banks/batch-get", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[*models.BankBatch]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, expected, response.Data)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_BatchGetBanks_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "malformed body",
+			body:           `{"bank_ids":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bank_ids",
+			body:           `{"environment":"sandbox"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "validation error",
+			body:           `{"bank_ids":["BES2100"],"environment":"staging"}`,
+			serviceError:   errors.New("invalid environment: staging"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository failure",
+			body:           `{"bank_ids":["BES2100"]}`,
+			serviceError:   errors.New("failed to get banks: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.POST("/banks/batch-get", handler.BatchGetBanks)
+
+			if tt.serviceError != nil {
+				mockService.On("BatchGetBanks", mock.Anything, mock.Anything).Return(nil, tt.serviceError)
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "/banks/batch-get", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankHandler_ExportBanks_CSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks/export", handler.ExportBanks)
+
+	banks := []models.BankWithEnvironments{
+		{
+			Bank: models.Bank{BankID: "BES2100", Name: "CaixaBank", Country: "ES"},
+			EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+				"production": {BankID: "BES2100", Environment: models.EnvironmentProduction, Enabled: true},
+			},
+		},
+		{Bank: models.Bank{BankID: "BES0049", Name: "Santander", Country: "ES"}},
+	}
+
+	mockService.On("ExportBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return filters.Environment == "production" && len(filters.Countries) == 1 && filters.Countries[0] == "ES"
+	}), mock.Anything).Return(banks, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/export?env=production&country=ES", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="banks.csv"`, w.Header().Get("Content-Disposition"))
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "bank_id,name,"))
+	assert.True(t, strings.HasPrefix(lines[1], "BES2100,CaixaBank,"))
+	assert.Contains(t, lines[1], ",production,true,")
+	assert.True(t, strings.HasPrefix(lines[2], "BES0049,Santander,"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_ExportBanks_EmptyNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks/export", handler.ExportBanks)
+
+	mockService.On("ExportBanks", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/export?format=ndjson", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_ExportBanks_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "unknown format",
+			query:          "format=xlsx",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed filter",
+			query:          "has_bic=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid filter value",
+			query:          "bank_group_id=abc",
+			serviceError:   errors.New("invalid bank_group_id: abc must be a valid UUID"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository failure before streaming",
+			serviceError:   errors.New("failed to query banks: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.GET("/banks/export", handler.ExportBanks)
+
+			if tt.serviceError != nil {
+				mockService.On("ExportBanks", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceError)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks/export?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankHandler_GetBankGroupBanks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/bank-groups/:groupId/banks", handler.GetBankGroupBanks)
+
+	groupID := uuid.New().String()
+	expectedBanks := []models.Bank{{BankID: "1", Name: "Bank A"}}
+	expectedPagination := &models.Pagination{Total: 1, Page: 2, Limit: 5, TotalPages: 1}
+
+	mockService.On("GetBankGroup", mock.Anything, groupID).Return(&models.BankGroup{Name: "Group"}, nil)
+	mockService.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		// The path parameter wins over the bank_group_id query parameter
+		return filters.BankGroupID == groupID && filters.Page == 2 && filters.Limit == 5 &&
+			slices.Equal(filters.Countries, []string{"ES"})
+	})).Return(expectedBanks, expectedPagination, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/bank-groups/"+groupID+"/banks?country=ES&page=2&limit=5&bank_group_id=other", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.Bank]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, expectedBanks, response.Data)
+	assert.Equal(t, expectedPagination, response.Pagination)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBankGroupBanks_CursorMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankService)
+	handler := NewBankHandler(mockService)
+
+	router := gin.New()
+	router.GET("/bank-groups/:groupId/banks", handler.GetBankGroupBanks)
+
+	groupID := uuid.New().String()
+	cursorPagination := &models.CursorPagination{Limit: 20}
+
+	mockService.On("GetBankGroup", mock.Anything, groupID).Return(&models.BankGroup{Name: "Group"}, nil)
+	mockService.On("GetBanksByCursor", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return filters.BankGroupID == groupID
+	})).Return([]models.Bank{}, cursorPagination, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/bank-groups/"+groupID+"/banks?cursor=", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHandler_GetBankGroupBanks_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	groupID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"invalid group ID", errors.New("invalid group_id: invalid UUID length: 3"), http.StatusBadRequest, "invalid group_id"},
+		{"unknown group", errors.New("bank group not found"), http.StatusNotFound, "Bank group not found"},
+		{"repository failure", errors.New("failed to get bank group: timeout"), http.StatusInternalServerError, "Failed to retrieve bank group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankService)
+			handler := NewBankHandler(mockService)
+
+			router := gin.New()
+			router.GET("/bank-groups/:groupId/banks", handler.GetBankGroupBanks)
+
+			mockService.On("GetBankGroup", mock.Anything, groupID).Return(nil, tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodGet, "/bank-groups/"+groupID+"/banks", http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+			mockService.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type BankHistoryHandler struct {
+	historyService services.BankHistoryService
+}
+
+func NewBankHistoryHandler(historyService services.BankHistoryService) *BankHistoryHandler {
+	return &BankHistoryHandler{
+		historyService: historyService,
+	}
+}
+
+// GetBankHistory lists the audited writes of a bank, newest first, with the fields each one changed
+func (h *BankHistoryHandler) GetBankHistory(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	page, err := parseIntParam(c, "page")
+	var limit int
+	if err == nil {
+		limit, err = parseIntParam(c, "limit")
+	}
+	if err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid pagination parameter",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"query_params", c.Request.URL.RawQuery,
+			)
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(err.Error()),
+		})
+		return
+	}
+
+	history, pagination, err := h.historyService.GetBankHistory(c.Request.Context(), bankID, page, limit)
+	if err != nil {
+		errorMessage := err.Error()
+
+		switch {
+		case strings.Contains(errorMessage, "invalid request"):
+			c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Bank ID is required"),
+			})
+
+		case strings.Contains(errorMessage, "not found"):
+			c.JSON(http.StatusNotFound, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Bank not found"),
+			})
+
+		default:
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("failed to retrieve bank history",
+					"error", err,
+					"bank_id", bankID,
+				)
+			}
+			c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Failed to retrieve bank history"),
+			})
+		}
+		return
+	}
+
+	response := models.APIResponse[[]models.BankHistoryEntry]{
+		Success:    true,
+		Data:       history,
+		Pagination: pagination,
+	}
+	c.JSON(http.StatusOK, response)
+}
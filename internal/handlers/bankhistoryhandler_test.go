@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockBankHistoryService implements the BankHistoryService interface for testing
+type MockBankHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockBankHistoryService) GetBankHistory(ctx context.Context, bankID string, page, limit int) ([]models.BankHistoryEntry, *models.Pagination, error) {
+	args := m.Called(ctx, bankID, page, limit)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]models.BankHistoryEntry), args.Get(1).(*models.Pagination), args.Error(2)
+}
+
+func newBankHistoryRouter(handler *BankHistoryHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/banks/:bankId/history", handler.GetBankHistory)
+	return router
+}
+
+func TestBankHistoryHandler_GetBankHistory(t *testing.T) {
+	mockService := new(MockBankHistoryService)
+	router := newBankHistoryRouter(NewBankHistoryHandler(mockService))
+
+	history := []models.BankHistoryEntry{
+		{
+			AuditID:   7,
+			Operation: models.AuditOperationUpdate,
+			Actor:     "ops@example.com",
+			Changes: []models.FieldChange{
+				{Field: "environment_configs.production.blocked", Before: false, After: true},
+			},
+		},
+	}
+	pagination := &models.Pagination{Page: 2, Limit: 10, Total: 11, TotalPages: 2}
+	mockService.On("GetBankHistory", mock.Anything, "BES0049", 2, 10).Return(history, pagination, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/history?page=2&limit=10", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.BankHistoryEntry]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "ops@example.com", response.Data[0].Actor)
+	require.Len(t, response.Data[0].Changes, 1)
+	assert.Equal(t, "environment_configs.production.blocked", response.Data[0].Changes[0].Field)
+	assert.Equal(t, pagination, response.Pagination)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankHistoryHandler_GetBankHistory_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{"invalid page", "?page=abc", nil, http.StatusBadRequest},
+		{"unknown bank", "", errors.New("bank not found"), http.StatusNotFound},
+		{"repository failure", "", errors.New("failed to query audit entries: connection reset"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankHistoryService)
+			router := newBankHistoryRouter(NewBankHistoryHandler(mockService))
+			if tt.serviceErr != nil {
+				mockService.On("GetBankHistory", mock.Anything, "BES0049", 0, 0).Return(nil, nil, tt.serviceErr)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/history"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// maxImportBodySize bounds the size of an uploaded import file
+const maxImportBodySize = 32 << 20
+
+type BankImporterHandler struct {
+	importerService services.BankImporter
+}
+
+func NewBankImporterHandler(importerService services.BankImporter) *BankImporterHandler {
+	return &BankImporterHandler{
+		importerService: importerService,
+	}
+}
+
+// ImportBanks loads the CSV or NDJSON file sent as request body. The report is returned with
+// 200 when every row succeeded and with 422 when any row failed.
+func (h *BankImporterHandler) ImportBanks(c *gin.Context) {
+	dryRun, err := parseOptionalBoolParam(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{Success: false, Error: stringPtr(err.Error())})
+		return
+	}
+	atomic, err := parseOptionalBoolParam(c, "atomic")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{Success: false, Error: stringPtr(err.Error())})
+		return
+	}
+
+	// The format defaults to the body media type, then to CSV
+	format := c.Query("format")
+	if format == "" {
+		format = services.ExportFormatCSV
+		if strings.Contains(c.ContentType(), "ndjson") {
+			format = services.ExportFormatNDJSON
+		}
+	}
+
+	options := &services.ImportBanksOptions{
+		Format: format,
+		Mode:   c.Query("mode"),
+		DryRun: dryRun != nil && *dryRun,
+		Atomic: atomic != nil && *atomic,
+	}
+
+	// Large files may take longer to upload than the server read timeout allows
+	if err := http.NewResponseController(c.Writer).SetReadDeadline(time.Now().Add(5 * time.Minute)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("failed to extend import read deadline", "error", err)
+		}
+	}
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodySize)
+
+	report, err := h.importerService.ImportBanks(c.Request.Context(), body, options)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(fmt.Sprintf("Import file too large: the limit is %d bytes", maxImportBodySize)),
+			}
+			c.JSON(http.StatusRequestEntityTooLarge, response)
+			return
+		}
+
+		if strings.Contains(err.Error(), "invalid") {
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid import request",
+					"error", err.Error(),
+					"remote_addr", c.ClientIP(),
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(err.Error()),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to import banks",
+				"error", err,
+				"format", options.Format,
+				"mode", options.Mode,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to import banks"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank import processed",
+			"mode", report.Mode,
+			"dry_run", report.DryRun,
+			"atomic", report.Atomic,
+			"applied", report.Applied,
+			"total", report.Total,
+			"failed", report.Failed,
+		)
+	}
+
+	if report.Failed > 0 {
+		response := models.APIResponse[*models.BankImportReport]{
+			Success: false,
+			Data:    report,
+			Error:   stringPtr(fmt.Sprintf("%d of %d rows failed", report.Failed, report.Total)),
+		}
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	response := models.APIResponse[*models.BankImportReport]{
+		Success: true,
+		Data:    report,
+	}
+	c.JSON(http.StatusOK, response)
+}
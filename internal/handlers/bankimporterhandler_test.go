@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockBankImporter implements the BankImporter interface for testing
+type MockBankImporter struct {
+	mock.Mock
+}
+
+func (m *MockBankImporter) ImportBanks(ctx context.Context, r io.Reader, options *services.ImportBanksOptions) (*models.BankImportReport, error) {
+	args := m.Called(ctx, r, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankImportReport), args.Error(1)
+}
+
+func performImportRequest(t *testing.T, handler *BankImporterHandler, url, contentType, body string) (*httptest.ResponseRecorder, models.APIResponse[*models.BankImportReport]) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.POST("/banks/import", handler.ImportBanks)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	r.ServeHTTP(w, req)
+
+	var response models.APIResponse[*models.BankImportReport]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func TestBankImporterHandler_ImportBanks_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankImporter)
+	handler := NewBankImporterHandler(mockService)
+
+	report := &models.BankImportReport{
+		Mode:      services.ImportModeUpsert,
+		DryRun:    true,
+		Total:     1,
+		Succeeded: 1,
+		Rows:      []models.BankImportRowResult{{Row: 2, BankID: "BPT0033", Action: models.ImportActionUpdate, Success: true}},
+	}
+	mockService.On("ImportBanks", mock.Anything, mock.Anything, &services.ImportBanksOptions{
+		Format: services.ExportFormatCSV,
+		Mode:   services.ImportModeUpsert,
+		DryRun: true,
+	}).Return(report, nil)
+
+	w, response := performImportRequest(t, handler, "/banks/import?mode=upsert&dry_run=true", "text/csv", "bank_id,name\nBPT0033,Millennium\n")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, response.Success)
+	assert.Equal(t, report, response.Data)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankImporterHandler_ImportBanks_FormatFromContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankImporter)
+	handler := NewBankImporterHandler(mockService)
+
+	mockService.On("ImportBanks", mock.Anything, mock.Anything, mock.MatchedBy(func(options *services.ImportBanksOptions) bool {
+		return options.Format == services.ExportFormatNDJSON && options.Atomic
+	})).Return(&models.BankImportReport{Total: 1, Succeeded: 1}, nil)
+
+	w, _ := performImportRequest(t, handler, "/banks/import?atomic=true", "application/x-ndjson", `{"bank_id":"BPT0033"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBankImporterHandler_ImportBanks_RowFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockBankImporter)
+	handler := NewBankImporterHandler(mockService)
+
+	report := &models.BankImportReport{
+		Mode:      services.ImportModeCreateOnly,
+		Applied:   true,
+		Total:     2,
+		Succeeded: 1,
+		Failed:    1,
+		Rows: []models.BankImportRowResult{
+			{Row: 2, BankID: "BPT0033", Action: models.ImportActionCreate, Success: true},
+			{Row: 3, BankID: "BPT0035", Errors: []models.FieldError{{Field: "name", Message: "is required"}}},
+		},
+	}
+	mockService.On("ImportBanks", mock.Anything, mock.Anything, mock.Anything).Return(report, nil)
+
+	w, response := performImportRequest(t, handler, "/banks/import", "text/csv", "bank_id,name\nBPT0033,Millennium\nBPT0035,\n")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "1 of 2 rows failed", *response.Error)
+	assert.Equal(t, report, response.Data)
+}
+
+func TestBankImporterHandler_ImportBanks_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		url            string
+		serviceError   error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "invalid dry_run",
+			url:            "/banks/import?dry_run=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "dry_run",
+		},
+		{
+			name:           "invalid file",
+			url:            "/banks/import",
+			serviceError:   errors.New(`invalid file: unknown column "colour"`),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `invalid file: unknown column "colour"`,
+		},
+		{
+			name:           "repository failure",
+			url:            "/banks/import",
+			serviceError:   errors.New("failed to import banks: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to import banks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankImporter)
+			handler := NewBankImporterHandler(mockService)
+			if tt.serviceError != nil {
+				mockService.On("ImportBanks", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceError)
+			}
+
+			w, response := performImportRequest(t, handler, tt.url, "text/csv", "bank_id\nBPT0033\n")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+			if tt.serviceError == nil {
+				mockService.AssertNotCalled(t, "ImportBanks", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// maxPatchBodySize bounds the size of a patch document
+const maxPatchBodySize = 1 << 20
+
+// acceptPatch lists the patch media types accepted by the PATCH endpoints
+const acceptPatch = jsonpatch.MergePatchMediaType + ", " + jsonpatch.JSONPatchMediaType
+
+type BankPatcherHandler struct {
+	patcherService services.BankPatcher
+}
+
+func NewBankPatcherHandler(patcherService services.BankPatcher) *BankPatcherHandler {
+	return &BankPatcherHandler{
+		patcherService: patcherService,
+	}
+}
+
+// PatchBank applies a JSON Merge Patch or JSON Patch document, selected by Content-Type, to a bank
+func (h *BankPatcherHandler) PatchBank(c *gin.Context) {
+	bankID := strings.TrimSpace(c.Param("bankId"))
+
+	mediaType, patch, ok := readPatchRequest(c)
+	if !ok {
+		return
+	}
+
+	bank, err := h.patcherService.PatchBank(c.Request.Context(), bankID, mediaType, patch, parseIfMatch(c))
+	if err != nil {
+		handlePatchError(c, err, "bank", bankID)
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank patched",
+			"bank_id", bankID,
+			"content_type", mediaType,
+		)
+	}
+
+	setVersionETag(c, bank.Version)
+	response := models.APIResponse[*models.Bank]{
+		Success: true,
+		Data:    bank,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// readPatchRequest checks the patch media type and reads the patch document. On failure the
+// error response has already been written.
+func readPatchRequest(c *gin.Context) (string, []byte, bool) {
+	c.Header("Accept-Patch", acceptPatch)
+
+	mediaType := c.ContentType()
+	if mediaType != jsonpatch.MergePatchMediaType && mediaType != jsonpatch.JSONPatchMediaType {
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Unsupported Content-Type: use " + acceptPatch),
+		}
+		c.JSON(http.StatusUnsupportedMediaType, response)
+		return "", nil, false
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchBodySize))
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		errorMessage := "Failed to read patch document"
+
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			statusCode = http.StatusRequestEntityTooLarge
+			errorMessage = "Patch document too large"
+		}
+
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(errorMessage),
+		}
+		c.JSON(statusCode, response)
+		return "", nil, false
+	}
+
+	return mediaType, patch, true
+}
+
+// handlePatchError maps the errors of a patch to HTTP status codes: a failed test operation
+// is a conflict, a patch that does not fit the document is unprocessable and a stale version
+// fails the If-Match precondition
+func handlePatchError(c *gin.Context, err error, entity, entityID string) {
+	var statusCode int
+	var errorMessage string
+
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		statusCode = versionConflictStatus(c)
+		errorMessage = err.Error()
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		statusCode = http.StatusConflict
+		errorMessage = err.Error()
+	case errors.Is(err, jsonpatch.ErrCannotApply):
+		statusCode = http.StatusUnprocessableEntity
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "invalid"):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case strings.Contains(err.Error(), "not found"):
+		statusCode = http.StatusNotFound
+		errorMessage = strings.ToUpper(entity[:1]) + entity[1:] + " not found"
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to patch "+entity,
+				"error", err,
+				"id", entityID,
+			)
+		}
+		statusCode = http.StatusInternalServerError
+		errorMessage = "Failed to patch " + entity
+	}
+
+	if statusCode != http.StatusInternalServerError {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("rejected "+entity+" patch",
+				"error", err.Error(),
+				"id", entityID,
+				"status", statusCode,
+			)
+		}
+	}
+
+	response := models.APIResponse[any]{
+		Success: false,
+		Error:   stringPtr(errorMessage),
+	}
+	c.JSON(statusCode, response)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockBankPatcher implements the BankPatcher interface for testing
+type MockBankPatcher struct {
+	mock.Mock
+}
+
+func (m *MockBankPatcher) PatchBank(ctx context.Context, bankID, mediaType string, patch []byte, precondition services.VersionPrecondition) (*models.Bank, error) {
+	args := m.Called(ctx, bankID, mediaType, patch, precondition)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Bank), args.Error(1)
+}
+
+func newBankPatcherRouter(handler *BankPatcherHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/banks/:bankId", handler.PatchBank)
+	return router
+}
+
+func TestBankPatcherHandler_PatchBank(t *testing.T) {
+	for _, mediaType := range []string{jsonpatch.MergePatchMediaType, jsonpatch.JSONPatchMediaType} {
+		t.Run(mediaType, func(t *testing.T) {
+			mockService := new(MockBankPatcher)
+			router := newBankPatcherRouter(NewBankPatcherHandler(mockService))
+
+			patch := `{"bic":null}`
+			bank := &models.Bank{BankID: "BES0049", Name: "Santander", Version: 4}
+			mockService.On("PatchBank", mock.Anything, "BES0049", mediaType, []byte(patch), services.VersionPrecondition(nil)).Return(bank, nil)
+
+			req, _ := http.NewRequest(http.MethodPatch, "/banks/BES0049", strings.NewReader(patch))
+			req.Header.Set("Content-Type", mediaType+"; charset=utf-8")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, acceptPatch, w.Header().Get("Accept-Patch"))
+			assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+			var response models.APIResponse[models.Bank]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.Success)
+			assert.Equal(t, "BES0049", response.Data.BankID)
+			assert.Nil(t, response.Data.BIC)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankPatcherHandler_PatchBank_VersionConflict(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		precondition   services.VersionPrecondition
+		expectedStatus int
+	}{
+		{"stale If-Match", `"2"`, services.VersionPrecondition{2}, http.StatusPreconditionFailed},
+		{"concurrent unconditional write", "", nil, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankPatcher)
+			router := newBankPatcherRouter(NewBankPatcherHandler(mockService))
+
+			conflict := fmt.Errorf("%w: bank is at version 3", repository.ErrVersionConflict)
+			mockService.On("PatchBank", mock.Anything, "BES0049", jsonpatch.MergePatchMediaType, mock.Anything, tt.precondition).
+				Return(nil, conflict)
+
+			req, _ := http.NewRequest(http.MethodPatch, "/banks/BES0049", strings.NewReader(`{"name":"x"}`))
+			req.Header.Set("Content-Type", jsonpatch.MergePatchMediaType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Empty(t, w.Header().Get("ETag"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankPatcherHandler_PatchBank_UnsupportedMediaType(t *testing.T) {
+	mockService := new(MockBankPatcher)
+	router := newBankPatcherRouter(NewBankPatcherHandler(mockService))
+
+	req, _ := http.NewRequest(http.MethodPatch, "/banks/BES0049", strings.NewReader(`{"name":"x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Equal(t, acceptPatch, w.Header().Get("Accept-Patch"))
+	mockService.AssertNotCalled(t, "PatchBank", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBankPatcherHandler_PatchBank_TooLarge(t *testing.T) {
+	mockService := new(MockBankPatcher)
+	router := newBankPatcherRouter(NewBankPatcherHandler(mockService))
+
+	body := `{"documentation":"` + strings.Repeat("x", maxPatchBodySize) + `"}`
+	req, _ := http.NewRequest(http.MethodPatch, "/banks/BES0049", strings.NewReader(body))
+	req.Header.Set("Content-Type", jsonpatch.MergePatchMediaType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockService.AssertNotCalled(t, "PatchBank", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBankPatcherHandler_PatchBank_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"test operation failed", fmt.Errorf("%w: value at \"/name\" does not match (operation 0)", jsonpatch.ErrTestFailed), http.StatusConflict, "patch test failed"},
+		{"path missing", fmt.Errorf("%w: path /foo not found (operation 0)", jsonpatch.ErrCannotApply), http.StatusUnprocessableEntity, "patch cannot be applied"},
+		{"invalid result", errors.New("invalid patch result: name is required"), http.StatusBadRequest, "invalid patch result: name is required"},
+		{"malformed patch", fmt.Errorf("%w: unknown operation \"merge\" (operation 0)", jsonpatch.ErrInvalidPatch), http.StatusBadRequest, "invalid patch"},
+		{"bank missing", errors.New("bank not found"), http.StatusNotFound, "Bank not found"},
+		{"writer failure", errors.New("failed to update bank: connection reset"), http.StatusInternalServerError, "Failed to patch bank"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankPatcher)
+			router := newBankPatcherRouter(NewBankPatcherHandler(mockService))
+
+			mockService.On("PatchBank", mock.Anything, "BES0049", jsonpatch.JSONPatchMediaType, mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodPatch, "/banks/BES0049", strings.NewReader(`[]`))
+			req.Header.Set("Content-Type", jsonpatch.JSONPatchMediaType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type BankReverterHandler struct {
+	reverterService services.BankReverter
+}
+
+func NewBankReverterHandler(reverterService services.BankReverter) *BankReverterHandler {
+	return &BankReverterHandler{
+		reverterService: reverterService,
+	}
+}
+
+// GetBankRevision returns a bank as it was at a revision
+func (h *BankReverterHandler) GetBankRevision(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	revision, ok := parseRevisionParam(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.reverterService.GetBankRevision(c.Request.Context(), bankID, revision)
+	if err != nil {
+		h.handleError(c, err, bankID, "retrieve revision of")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[*models.BankRevision]{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// RevertBank restores a bank and its environment configs to a revision
+func (h *BankReverterHandler) RevertBank(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	revision, ok := parseRevisionParam(c)
+	if !ok {
+		return
+	}
+
+	bank, err := h.reverterService.RevertBank(c.Request.Context(), bankID, revision, parseIfMatch(c))
+	if err != nil {
+		h.handleError(c, err, bankID, "revert")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("bank reverted",
+			"bank_id", bank.BankID,
+			"revision", revision,
+			"version", bank.Version,
+		)
+	}
+
+	setVersionETag(c, bank.Version)
+	c.JSON(http.StatusOK, models.APIResponse[*models.BankWithEnvironments]{
+		Success: true,
+		Data:    bank,
+	})
+}
+
+// parseRevisionParam reads the revision path parameter, answering 400 when it is not a number
+func parseRevisionParam(c *gin.Context) (int64, bool) {
+	revision, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid revision: must be a number"),
+		})
+		return 0, false
+	}
+	return revision, true
+}
+
+func (h *BankReverterHandler) handleError(c *gin.Context, err error, bankID, action string) {
+	errorMessage := err.Error()
+
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.JSON(versionConflictStatus(c), models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(errorMessage),
+		})
+
+	case strings.Contains(errorMessage, "invalid request"):
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(strings.TrimPrefix(errorMessage, "invalid request: ")),
+		})
+
+	case strings.Contains(errorMessage, "revision") && strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Revision not found"),
+		})
+
+	case strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Bank not found"),
+		})
+
+	case strings.Contains(errorMessage, "cannot revert"):
+		c.JSON(http.StatusConflict, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(errorMessage),
+		})
+
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to "+action+" bank",
+				"error", err,
+				"bank_id", bankID,
+			)
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to " + action + " bank"),
+		})
+	}
+}
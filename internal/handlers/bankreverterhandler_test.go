@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockBankReverter implements the BankReverter interface for testing
+type MockBankReverter struct {
+	mock.Mock
+}
+
+func (m *MockBankReverter) GetBankRevision(ctx context.Context, bankID string, revision int64) (*models.BankRevision, error) {
+	args := m.Called(ctx, bankID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankRevision), args.Error(1)
+}
+
+func (m *MockBankReverter) RevertBank(ctx context.Context, bankID string, revision int64, precondition services.VersionPrecondition) (*models.BankWithEnvironments, error) {
+	args := m.Called(ctx, bankID, revision, precondition)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankWithEnvironments), args.Error(1)
+}
+
+func newBankReverterRouter(handler *BankReverterHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/banks/:bankId/revisions/:rev", handler.GetBankRevision)
+	router.POST("/banks/:bankId/revisions/:rev/revert", handler.RevertBank)
+	return router
+}
+
+func TestBankReverterHandler_RevertBank(t *testing.T) {
+	mockService := new(MockBankReverter)
+	router := newBankReverterRouter(NewBankReverterHandler(mockService))
+
+	bank := &models.BankWithEnvironments{
+		Bank: models.Bank{BankID: "BES0049", Name: "Santander", Version: 8},
+		EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"production": {BankID: "BES0049", Environment: models.EnvironmentProduction, Enabled: true},
+		},
+	}
+	mockService.On("RevertBank", mock.Anything, "BES0049", int64(3), services.VersionPrecondition{7}).Return(bank, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/banks/BES0049/revisions/3/revert", http.NoBody)
+	req.Header.Set("If-Match", `"7"`)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"8"`, w.Header().Get("ETag"))
+
+	var response models.APIResponse[models.BankWithEnvironments]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, "Santander", response.Data.Name)
+	assert.Contains(t, response.Data.EnvironmentConfigs, "production")
+	mockService.AssertExpectations(t)
+}
+
+func TestBankReverterHandler_GetBankRevision(t *testing.T) {
+	mockService := new(MockBankReverter)
+	router := newBankReverterRouter(NewBankReverterHandler(mockService))
+
+	revision := &models.BankRevision{
+		BankID:   "BES0049",
+		Revision: 3,
+		Bank:     models.BankWithEnvironments{Bank: models.Bank{BankID: "BES0049", Name: "Santander", Version: 3}},
+	}
+	mockService.On("GetBankRevision", mock.Anything, "BES0049", int64(3)).Return(revision, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/revisions/3", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.BankRevision]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(3), response.Data.Revision)
+	assert.Equal(t, "Santander", response.Data.Bank.Name)
+}
+
+func TestBankReverterHandler_RevertBank_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		revision       string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"revision not a number", "latest", nil, http.StatusBadRequest, "Invalid revision"},
+		{"non-positive revision", "0", errors.New("invalid request: revision must be a positive number"), http.StatusBadRequest, "revision must be a positive number"},
+		{"unknown revision", "9", errors.New("revision 9 of bank 'BES0049' not found"), http.StatusNotFound, "Revision not found"},
+		{"deleted bank", "3", errors.New("bank not found: no rows in result set"), http.StatusNotFound, "Bank not found"},
+		{"concurrent write", "3", fmt.Errorf("failed to revert bank: %w", repository.ErrVersionConflict), http.StatusConflict, "version conflict"},
+		{"bank group gone", "3", errors.New("cannot revert: the bank group of revision 3 no longer exists"), http.StatusConflict, "cannot revert"},
+		{"writer failure", "3", errors.New("failed to revert bank: connection reset"), http.StatusInternalServerError, "Failed to revert bank"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockBankReverter)
+			router := newBankReverterRouter(NewBankReverterHandler(mockService))
+			if tt.serviceErr != nil {
+				mockService.On("RevertBank", mock.Anything, "BES0049", mock.Anything, services.VersionPrecondition(nil)).Return(nil, tt.serviceErr)
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "/banks/BES0049/revisions/"+tt.revision+"/revert", http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
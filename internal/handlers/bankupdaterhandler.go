@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/repository"
 	"github.com/wukong0111/go-banks/internal/services"
 )
 
@@ -65,7 +67,7 @@ func (h *BankUpdaterHandler) UpdateBank(c *gin.Context) {
 	}
 
 	// Call service to update bank
-	response, err := h.updaterService.UpdateBank(c.Request.Context(), bankID, &request)
+	response, err := h.updaterService.UpdateBank(c.Request.Context(), bankID, &request, parseIfMatch(c))
 	if err != nil {
 		if log, ok := logger.GetLogger(c); ok {
 			log.Error("failed to update bank",
@@ -75,6 +77,17 @@ func (h *BankUpdaterHandler) UpdateBank(c *gin.Context) {
 			)
 		}
 
+		// Check if the bank changed since the version the client or the service read
+		if errors.Is(err, repository.ErrVersionConflict) {
+			c.JSON(versionConflictStatus(c), gin.H{
+				"success":   false,
+				"error":     "Bank has been modified",
+				"details":   err.Error(),
+				"timestamp": "2024-01-01T00:00:00Z", // placeholder for now
+			})
+			return
+		}
+
 		// Check if it's a not found error
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -107,6 +120,7 @@ func (h *BankUpdaterHandler) UpdateBank(c *gin.Context) {
 	}
 
 	// Success response
+	setVersionETag(c, response.Bank.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type ChangeHandler struct {
+	changeService services.ChangeService
+}
+
+func NewChangeHandler(changeService services.ChangeService) *ChangeHandler {
+	return &ChangeHandler{
+		changeService: changeService,
+	}
+}
+
+func (h *ChangeHandler) GetChanges(c *gin.Context) {
+	var limit int
+	if limitStr, exists := c.GetQuery("limit"); exists {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Invalid limit parameter: must be a number"),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	changes, err := h.changeService.GetChanges(c.Request.Context(), c.Query("since"), limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid since token") {
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid since parameter",
+					"error", err.Error(),
+					"remote_addr", c.ClientIP(),
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr("Invalid since parameter"),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to retrieve catalog changes",
+				"error", err,
+				"since", c.Query("since"),
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to retrieve changes"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := models.APIResponse[*models.CatalogChanges]{
+		Success: true,
+		Data:    changes,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
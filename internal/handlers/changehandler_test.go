@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockChangeService is a mock implementation of the ChangeService for testing.
+type MockChangeService struct {
+	mock.Mock
+}
+
+func (m *MockChangeService) GetChanges(ctx context.Context, since string, limit int) (*models.CatalogChanges, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CatalogChanges), args.Error(1)
+}
+
+func TestChangeHandler_GetChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockChangeService)
+	handler := NewChangeHandler(mockService)
+
+	router := gin.New()
+	router.GET("/banks/changes", handler.GetChanges)
+
+	bankID := "BES2100"
+	changes := &models.CatalogChanges{
+		Banks:              []models.Bank{{BankID: "BES0049", Name: "Santander"}},
+		EnvironmentConfigs: []models.BankEnvironmentConfig{},
+		BankGroups:         []models.BankGroup{},
+		Deleted: []models.Tombstone{
+			{EntityType: models.EntityTypeBank, EntityID: bankID, BankID: &bankID},
+		},
+		NextToken: "eyJzIjo0Mn0",
+		HasMore:   true,
+	}
+	mockService.On("GetChanges", mock.Anything, "eyJzIjoxMH0", 100).Return(changes, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/changes?since=eyJzIjoxMH0&limit=100", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.CatalogChanges]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Len(t, response.Data.Banks, 1)
+	require.Len(t, response.Data.Deleted, 1)
+	assert.Equal(t, "bank", response.Data.Deleted[0].EntityType)
+	assert.Equal(t, "eyJzIjo0Mn0", response.Data.NextToken)
+	assert.True(t, response.Data.HasMore)
+	mockService.AssertExpectations(t)
+}
+
+func TestChangeHandler_GetChanges_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		query        string
+		serviceErr   error
+		expectedCode int
+	}{
+		{name: "invalid limit", query: "limit=abc", expectedCode: http.StatusBadRequest},
+		{name: "invalid token", query: "since=%25%25", serviceErr: errors.New("invalid since token"), expectedCode: http.StatusBadRequest},
+		{name: "repository failure", query: "", serviceErr: errors.New("connection refused"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockChangeService)
+			handler := NewChangeHandler(mockService)
+
+			router := gin.New()
+			router.GET("/banks/changes", handler.GetChanges)
+
+			if tt.serviceErr != nil {
+				mockService.On("GetChanges", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/banks/changes?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type EnvironmentConfigHandler struct {
+	environmentConfigService services.EnvironmentConfigService
+}
+
+func NewEnvironmentConfigHandler(environmentConfigService services.EnvironmentConfigService) *EnvironmentConfigHandler {
+	return &EnvironmentConfigHandler{
+		environmentConfigService: environmentConfigService,
+	}
+}
+
+// GetEnvironmentConfig returns the config of one environment of a bank with its effective status
+func (h *EnvironmentConfigHandler) GetEnvironmentConfig(c *gin.Context) {
+	bankID := c.Param("bankId")
+	environment := c.Param("env")
+
+	config, err := h.environmentConfigService.GetEnvironmentConfig(c.Request.Context(), bankID, environment)
+	if err != nil {
+		h.handleError(c, err, bankID, environment, "retrieve")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[*models.BankEnvironmentConfigWithStatus]{
+		Success: true,
+		Data:    config,
+	})
+}
+
+// PutEnvironmentConfig creates or replaces the config of one environment of a bank, leaving the
+// configs of its other environments untouched
+func (h *EnvironmentConfigHandler) PutEnvironmentConfig(c *gin.Context) {
+	bankID := c.Param("bankId")
+	environment := c.Param("env")
+
+	var request services.EnvironmentConfig
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid JSON request format",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"path", c.Request.URL.Path,
+			)
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid request format"),
+		})
+		return
+	}
+
+	config, created, err := h.environmentConfigService.PutEnvironmentConfig(c.Request.Context(), bankID, environment, &request, parseIfMatch(c))
+	if err != nil {
+		h.handleError(c, err, bankID, environment, "save")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("environment config saved",
+			"bank_id", config.BankID,
+			"environment", environment,
+			"created", created,
+		)
+	}
+
+	statusCode := http.StatusOK
+	if created {
+		statusCode = http.StatusCreated
+	}
+	setVersionETag(c, config.Version)
+	c.JSON(statusCode, models.APIResponse[*models.BankEnvironmentConfigWithStatus]{
+		Success: true,
+		Data:    config,
+	})
+}
+
+// PatchEnvironmentConfig applies a JSON Merge Patch or JSON Patch document, selected by Content-Type,
+// to the config of one environment of a bank
+func (h *EnvironmentConfigHandler) PatchEnvironmentConfig(c *gin.Context) {
+	bankID := c.Param("bankId")
+	environment := c.Param("env")
+
+	mediaType, patch, ok := readPatchRequest(c)
+	if !ok {
+		return
+	}
+
+	config, err := h.environmentConfigService.PatchEnvironmentConfig(c.Request.Context(), bankID, environment, mediaType, patch, parseIfMatch(c))
+	if err != nil {
+		h.handleError(c, err, bankID, environment, "patch")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("environment config patched",
+			"bank_id", config.BankID,
+			"environment", environment,
+			"content_type", mediaType,
+		)
+	}
+
+	setVersionETag(c, config.Version)
+	c.JSON(http.StatusOK, models.APIResponse[*models.BankEnvironmentConfigWithStatus]{
+		Success: true,
+		Data:    config,
+	})
+}
+
+// DeleteEnvironmentConfig removes the config of one environment of a bank
+func (h *EnvironmentConfigHandler) DeleteEnvironmentConfig(c *gin.Context) {
+	bankID := c.Param("bankId")
+	environment := c.Param("env")
+
+	if err := h.environmentConfigService.DeleteEnvironmentConfig(c.Request.Context(), bankID, environment, parseIfMatch(c)); err != nil {
+		h.handleError(c, err, bankID, environment, "delete")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("environment config deleted",
+			"bank_id", bankID,
+			"environment", environment,
+		)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *EnvironmentConfigHandler) handleError(c *gin.Context, err error, bankID, environment, action string) {
+	errorMessage := err.Error()
+
+	var statusCode int
+	var message string
+
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		statusCode = versionConflictStatus(c)
+		message = errorMessage
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		statusCode = http.StatusConflict
+		message = errorMessage
+	case errors.Is(err, jsonpatch.ErrCannotApply):
+		statusCode = http.StatusUnprocessableEntity
+		message = errorMessage
+	case strings.Contains(errorMessage, "invalid"):
+		statusCode = http.StatusBadRequest
+		message = strings.TrimPrefix(errorMessage, "invalid request: ")
+	case strings.Contains(errorMessage, "environment config") && strings.Contains(errorMessage, "not found"):
+		statusCode = http.StatusNotFound
+		message = "Environment config not found"
+	case strings.Contains(errorMessage, "not found"):
+		statusCode = http.StatusNotFound
+		message = "Bank not found"
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to "+action+" environment config",
+				"error", err,
+				"bank_id", bankID,
+				"environment", environment,
+			)
+		}
+		statusCode = http.StatusInternalServerError
+		message = "Failed to " + action + " environment config"
+	}
+
+	c.JSON(statusCode, models.APIResponse[any]{
+		Success: false,
+		Error:   stringPtr(message),
+	})
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockEnvironmentConfigService implements the EnvironmentConfigService interface for testing
+type MockEnvironmentConfigService struct {
+	mock.Mock
+}
+
+func (m *MockEnvironmentConfigService) GetEnvironmentConfig(ctx context.Context, bankID, environment string) (*models.BankEnvironmentConfigWithStatus, error) {
+	args := m.Called(ctx, bankID, environment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankEnvironmentConfigWithStatus), args.Error(1)
+}
+
+func (m *MockEnvironmentConfigService) PutEnvironmentConfig(ctx context.Context, bankID, environment string, request *services.EnvironmentConfig, precondition services.VersionPrecondition) (*models.BankEnvironmentConfigWithStatus, bool, error) {
+	args := m.Called(ctx, bankID, environment, request, precondition)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*models.BankEnvironmentConfigWithStatus), args.Bool(1), args.Error(2)
+}
+
+func (m *MockEnvironmentConfigService) PatchEnvironmentConfig(ctx context.Context, bankID, environment, mediaType string, patch []byte, precondition services.VersionPrecondition) (*models.BankEnvironmentConfigWithStatus, error) {
+	args := m.Called(ctx, bankID, environment, mediaType, patch, precondition)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankEnvironmentConfigWithStatus), args.Error(1)
+}
+
+func (m *MockEnvironmentConfigService) DeleteEnvironmentConfig(ctx context.Context, bankID, environment string, precondition services.VersionPrecondition) error {
+	args := m.Called(ctx, bankID, environment, precondition)
+	return args.Error(0)
+}
+
+func newEnvironmentConfigRouter(handler *EnvironmentConfigHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/banks/:bankId/environments/:env", handler.GetEnvironmentConfig)
+	router.PUT("/banks/:bankId/environments/:env", handler.PutEnvironmentConfig)
+	router.PATCH("/banks/:bankId/environments/:env", handler.PatchEnvironmentConfig)
+	router.DELETE("/banks/:bankId/environments/:env", handler.DeleteEnvironmentConfig)
+	return router
+}
+
+func sandboxConfigWithStatus(version int64) *models.BankEnvironmentConfigWithStatus {
+	return &models.BankEnvironmentConfigWithStatus{
+		BankEnvironmentConfig: models.BankEnvironmentConfig{
+			BankID:      "BES0049",
+			Environment: models.EnvironmentSandbox,
+			Enabled:     true,
+			Version:     version,
+		},
+		EffectiveStatus: &models.EffectiveStatus{Status: models.EffectiveStatusAvailable},
+	}
+}
+
+func TestEnvironmentConfigHandler_GetEnvironmentConfig(t *testing.T) {
+	mockService := new(MockEnvironmentConfigService)
+	router := newEnvironmentConfigRouter(NewEnvironmentConfigHandler(mockService))
+
+	mockService.On("GetEnvironmentConfig", mock.Anything, "BES0049", "sandbox").Return(sandboxConfigWithStatus(4), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/environments/sandbox", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	data := response["data"].(map[string]any)
+	assert.Equal(t, "sandbox", data["environment"])
+	assert.InDelta(t, 4, data["version"], 0)
+	assert.Equal(t, "available", data["effective_status"].(map[string]any)["status"])
+}
+
+func TestEnvironmentConfigHandler_PutEnvironmentConfig(t *testing.T) {
+	for _, created := range []bool{true, false} {
+		t.Run(fmt.Sprintf("created=%t", created), func(t *testing.T) {
+			mockService := new(MockEnvironmentConfigService)
+			router := newEnvironmentConfigRouter(NewEnvironmentConfigHandler(mockService))
+
+			mockService.On("PutEnvironmentConfig", mock.Anything, "BES0049", "sandbox",
+				mock.MatchedBy(func(request *services.EnvironmentConfig) bool {
+					return request.Blocked != nil && *request.Blocked
+				}), services.VersionPrecondition{4}).Return(sandboxConfigWithStatus(5), created, nil)
+
+			req, _ := http.NewRequest(http.MethodPut, "/banks/BES0049/environments/sandbox", bytes.NewBufferString(`{"blocked":true}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"4"`)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			expectedStatus := http.StatusOK
+			if created {
+				expectedStatus = http.StatusCreated
+			}
+			assert.Equal(t, expectedStatus, w.Code)
+			assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnvironmentConfigHandler_PatchEnvironmentConfig(t *testing.T) {
+	mockService := new(MockEnvironmentConfigService)
+	router := newEnvironmentConfigRouter(NewEnvironmentConfigHandler(mockService))
+
+	patch := []byte(`{"risky":true}`)
+	mockService.On("PatchEnvironmentConfig", mock.Anything, "BES0049", "sandbox", jsonpatch.MergePatchMediaType, patch,
+		services.VersionPrecondition(nil)).Return(sandboxConfigWithStatus(5), nil)
+
+	req, _ := http.NewRequest(http.MethodPatch, "/banks/BES0049/environments/sandbox", bytes.NewReader(patch))
+	req.Header.Set("Content-Type", jsonpatch.MergePatchMediaType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestEnvironmentConfigHandler_DeleteEnvironmentConfig(t *testing.T) {
+	mockService := new(MockEnvironmentConfigService)
+	router := newEnvironmentConfigRouter(NewEnvironmentConfigHandler(mockService))
+
+	mockService.On("DeleteEnvironmentConfig", mock.Anything, "BES0049", "sandbox", services.VersionPrecondition(nil)).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/banks/BES0049/environments/sandbox", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestEnvironmentConfigHandler_Errors(t *testing.T) {
+	conflict := fmt.Errorf("%w: environment config is at version 5", repository.ErrVersionConflict)
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"invalid environment", "", errors.New("invalid request: environment must be one of sandbox, production, uat, test"), http.StatusBadRequest, "environment must be one of"},
+		{"unknown bank", "", errors.New("bank not found"), http.StatusNotFound, "Bank not found"},
+		{"missing config", "", errors.New("environment config 'uat' of bank 'BES0049' not found"), http.StatusNotFound, "Environment config not found"},
+		{"stale If-Match", `"4"`, conflict, http.StatusPreconditionFailed, "version conflict"},
+		{"concurrent write", "", conflict, http.StatusConflict, "version conflict"},
+		{"repository failure", "", errors.New("failed to delete environment config: connection reset"), http.StatusInternalServerError, "Failed to delete environment config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockEnvironmentConfigService)
+			router := newEnvironmentConfigRouter(NewEnvironmentConfigHandler(mockService))
+			mockService.On("DeleteEnvironmentConfig", mock.Anything, "BES0049", "sandbox", mock.Anything).Return(tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodDelete, "/banks/BES0049/environments/sandbox", http.NoBody)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type EnvironmentDriftHandler struct {
+	driftService services.EnvironmentDriftService
+}
+
+func NewEnvironmentDriftHandler(driftService services.EnvironmentDriftService) *EnvironmentDriftHandler {
+	return &EnvironmentDriftHandler{
+		driftService: driftService,
+	}
+}
+
+// GetEnvironmentDrift lists the banks whose config of ?target= differs from their config of ?base=,
+// as JSON or, with ?format=csv, as a CSV download
+func (h *EnvironmentDriftHandler) GetEnvironmentDrift(c *gin.Context) {
+	base := c.Query("base")
+	target := c.Query("target")
+
+	format := c.DefaultQuery("format", services.DriftReportFormatJSON)
+	if format != services.DriftReportFormatJSON && format != services.DriftReportFormatCSV {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error: stringPtr(fmt.Sprintf("invalid format: %s (allowed: %s, %s)",
+				format, services.DriftReportFormatJSON, services.DriftReportFormatCSV)),
+		})
+		return
+	}
+
+	filters, err := parseBankFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{Success: false, Error: stringPtr(err.Error())})
+		return
+	}
+
+	report, err := h.driftService.GetEnvironmentDrift(c.Request.Context(), filters, base, target)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			if log, ok := logger.GetLogger(c); ok {
+				log.Warn("invalid environment drift request",
+					"error", err.Error(),
+					"remote_addr", c.ClientIP(),
+					"query_params", c.Request.URL.RawQuery,
+				)
+			}
+			c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(strings.TrimPrefix(err.Error(), "invalid request: ")),
+			})
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to compare environments",
+				"error", err,
+				"base", base,
+				"target", target,
+			)
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to retrieve environment drift"),
+		})
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("environment drift computed",
+			"base", base,
+			"target", target,
+			"compared", report.Compared,
+			"drifted", report.Drifted,
+		)
+	}
+
+	if format == services.DriftReportFormatCSV {
+		c.Header("Content-Type", services.ExportContentType(services.ExportFormatCSV))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="environment-drift-%s-%s.csv"`, base, target))
+		c.Status(http.StatusOK)
+		if err := services.WriteEnvironmentDriftCSV(c.Writer, report); err != nil {
+			// The status line is already on the wire; the truncated body is all we can signal
+			if log, ok := logger.GetLogger(c); ok {
+				log.Error("environment drift download interrupted", "error", err)
+			}
+			c.Abort()
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[*models.EnvironmentDriftReport]{
+		Success: true,
+		Data:    report,
+	})
+}
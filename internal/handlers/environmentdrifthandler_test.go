@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// MockEnvironmentDriftService implements the EnvironmentDriftService interface for testing
+type MockEnvironmentDriftService struct {
+	mock.Mock
+}
+
+func (m *MockEnvironmentDriftService) GetEnvironmentDrift(ctx context.Context, filters *repository.BankFilters, base, target string) (*models.EnvironmentDriftReport, error) {
+	args := m.Called(ctx, filters, base, target)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnvironmentDriftReport), args.Error(1)
+}
+
+func newEnvironmentDriftRouter(handler *EnvironmentDriftHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/reports/environment-drift", handler.GetEnvironmentDrift)
+	return router
+}
+
+func environmentDriftReport() *models.EnvironmentDriftReport {
+	return &models.EnvironmentDriftReport{
+		Base:     models.EnvironmentUAT,
+		Target:   models.EnvironmentProduction,
+		Fields:   []string{"enabled", "instant_payments_limit"},
+		Compared: 2,
+		Drifted:  1,
+		Banks: []models.EnvironmentDrift{{
+			BankID:  "BES0049",
+			Name:    "Santander",
+			Country: "ES",
+			Status:  models.DriftStatusDifferent,
+			Fields:  []models.EnvironmentDriftField{{Field: "instant_payments_limit", Base: float64(30000), Target: nil}},
+		}},
+	}
+}
+
+func TestEnvironmentDriftHandler_GetEnvironmentDrift_JSON(t *testing.T) {
+	mockService := new(MockEnvironmentDriftService)
+	router := newEnvironmentDriftRouter(NewEnvironmentDriftHandler(mockService))
+
+	mockService.On("GetEnvironmentDrift", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return len(filters.Countries) == 1 && filters.Countries[0] == "ES"
+	}), "uat", "production").Return(environmentDriftReport(), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reports/environment-drift?base=uat&target=production&country=ES", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[models.EnvironmentDriftReport]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 1, response.Data.Drifted)
+	require.Len(t, response.Data.Banks, 1)
+	assert.Equal(t, "instant_payments_limit", response.Data.Banks[0].Fields[0].Field)
+	mockService.AssertExpectations(t)
+}
+
+func TestEnvironmentDriftHandler_GetEnvironmentDrift_CSV(t *testing.T) {
+	mockService := new(MockEnvironmentDriftService)
+	router := newEnvironmentDriftRouter(NewEnvironmentDriftHandler(mockService))
+
+	mockService.On("GetEnvironmentDrift", mock.Anything, mock.Anything, "uat", "production").Return(environmentDriftReport(), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/reports/environment-drift?base=uat&target=production&format=csv", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="environment-drift-uat-production.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "bank_id,name,country,status,field,base,target\n"+
+		"BES0049,Santander,ES,different,instant_payments_limit,30000,\n", w.Body.String())
+}
+
+func TestEnvironmentDriftHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"unknown format", "base=uat&target=production&format=xml", nil, http.StatusBadRequest, "invalid format: xml"},
+		{"malformed filter", "base=uat&target=production&has_bic=maybe", nil, http.StatusBadRequest, "has_bic"},
+		{"invalid environments", "base=uat&target=uat", errors.New("invalid request: target must differ from base"), http.StatusBadRequest, "target must differ from base"},
+		{"repository failure", "base=uat&target=production", errors.New("failed to compare environments: connection reset"), http.StatusInternalServerError, "Failed to retrieve environment drift"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockEnvironmentDriftService)
+			router := newEnvironmentDriftRouter(NewEnvironmentDriftHandler(mockService))
+			mockService.On("GetEnvironmentDrift", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodGet, "/reports/environment-drift?"+tt.query, http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// promoteEnvironmentBody is the optional body of a promotion
+type promoteEnvironmentBody struct {
+	Fields []string `json:"fields"`
+}
+
+type EnvironmentPromotionHandler struct {
+	promotionService services.EnvironmentPromotionService
+}
+
+func NewEnvironmentPromotionHandler(promotionService services.EnvironmentPromotionService) *EnvironmentPromotionHandler {
+	return &EnvironmentPromotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+// PromoteEnvironmentConfig copies the config of the environment in the path to the environment in ?to=
+// for one bank. With ?dry_run=true it only returns the changes the promotion would make.
+func (h *EnvironmentPromotionHandler) PromoteEnvironmentConfig(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	request, ok := readPromoteEnvironmentRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.promotionService.PromoteEnvironmentConfig(c.Request.Context(), bankID, request, parseIfMatch(c))
+	if err != nil {
+		h.handleError(c, err, bankID, request)
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("environment config promoted",
+			"bank_id", bankID,
+			"from", request.From,
+			"to", request.To,
+			"dry_run", report.DryRun,
+			"applied", report.Applied,
+		)
+	}
+
+	if !report.DryRun {
+		setVersionETag(c, report.Banks[0].Version)
+	}
+	c.JSON(http.StatusOK, models.APIResponse[*models.EnvironmentPromotionReport]{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// PromoteEnvironmentConfigs copies the config of the environment in the path to the environment in ?to=
+// for every bank matching the listing filters. The promotion is applied to every bank or to none: the
+// report is returned with 200 when every bank succeeded and with 422 when any bank failed.
+func (h *EnvironmentPromotionHandler) PromoteEnvironmentConfigs(c *gin.Context) {
+	filters, err := parseBankFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{Success: false, Error: stringPtr(err.Error())})
+		return
+	}
+
+	request, ok := readPromoteEnvironmentRequest(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.promotionService.PromoteEnvironmentConfigs(c.Request.Context(), filters, request)
+	if err != nil {
+		h.handleError(c, err, "", request)
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("environment configs promoted",
+			"from", request.From,
+			"to", request.To,
+			"dry_run", report.DryRun,
+			"applied", report.Applied,
+			"total", report.Total,
+			"changed", report.Changed,
+			"failed", report.Failed,
+		)
+	}
+
+	if report.Failed > 0 {
+		c.JSON(http.StatusUnprocessableEntity, models.APIResponse[*models.EnvironmentPromotionReport]{
+			Success: false,
+			Data:    report,
+			Error:   stringPtr(fmt.Sprintf("%d of %d banks failed", report.Failed, report.Total)),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[*models.EnvironmentPromotionReport]{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// readPromoteEnvironmentRequest reads the environments from the path and the query and the fields from
+// the optional body, writing an error response and returning false when the request is malformed
+func readPromoteEnvironmentRequest(c *gin.Context) (*services.PromoteEnvironmentRequest, bool) {
+	dryRun, err := parseOptionalBoolParam(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{Success: false, Error: stringPtr(err.Error())})
+		return nil, false
+	}
+
+	to := c.Query("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{Success: false, Error: stringPtr("to query parameter is required")})
+		return nil, false
+	}
+
+	var body promoteEnvironmentBody
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid JSON request format",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"path", c.Request.URL.Path,
+			)
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid request format"),
+		})
+		return nil, false
+	}
+
+	return &services.PromoteEnvironmentRequest{
+		From:   c.Param("env"),
+		To:     to,
+		Fields: body.Fields,
+		DryRun: dryRun != nil && *dryRun,
+	}, true
+}
+
+func (h *EnvironmentPromotionHandler) handleError(c *gin.Context, err error, bankID string, request *services.PromoteEnvironmentRequest) {
+	errorMessage := err.Error()
+
+	var statusCode int
+	var message string
+
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		statusCode = versionConflictStatus(c)
+		message = errorMessage
+	case strings.Contains(errorMessage, "invalid"):
+		statusCode = http.StatusBadRequest
+		message = strings.TrimPrefix(errorMessage, "invalid request: ")
+	case strings.Contains(errorMessage, "environment config") && strings.Contains(errorMessage, "not found"):
+		statusCode = http.StatusNotFound
+		message = "Environment config not found"
+	case strings.Contains(errorMessage, "not found"):
+		statusCode = http.StatusNotFound
+		message = "Bank not found"
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to promote environment config",
+				"error", err,
+				"bank_id", bankID,
+				"from", request.From,
+				"to", request.To,
+			)
+		}
+		statusCode = http.StatusInternalServerError
+		message = "Failed to promote environment config"
+	}
+
+	c.JSON(statusCode, models.APIResponse[any]{
+		Success: false,
+		Error:   stringPtr(message),
+	})
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockEnvironmentPromotionService implements the EnvironmentPromotionService interface for testing
+type MockEnvironmentPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockEnvironmentPromotionService) PromoteEnvironmentConfig(ctx context.Context, bankID string, request *services.PromoteEnvironmentRequest, precondition services.VersionPrecondition) (*models.EnvironmentPromotionReport, error) {
+	args := m.Called(ctx, bankID, request, precondition)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnvironmentPromotionReport), args.Error(1)
+}
+
+func (m *MockEnvironmentPromotionService) PromoteEnvironmentConfigs(ctx context.Context, filters *repository.BankFilters, request *services.PromoteEnvironmentRequest) (*models.EnvironmentPromotionReport, error) {
+	args := m.Called(ctx, filters, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EnvironmentPromotionReport), args.Error(1)
+}
+
+func newEnvironmentPromotionRouter(handler *EnvironmentPromotionHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/banks/:bankId/environments/:env/promote", handler.PromoteEnvironmentConfig)
+	router.POST("/environments/:env/promote", handler.PromoteEnvironmentConfigs)
+	return router
+}
+
+func TestEnvironmentPromotionHandler_PromoteEnvironmentConfig(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dry_run=%t", dryRun), func(t *testing.T) {
+			mockService := new(MockEnvironmentPromotionService)
+			router := newEnvironmentPromotionRouter(NewEnvironmentPromotionHandler(mockService))
+
+			report := &models.EnvironmentPromotionReport{
+				From:    models.EnvironmentUAT,
+				To:      models.EnvironmentProduction,
+				Fields:  []string{"instant_payments_limit"},
+				DryRun:  dryRun,
+				Applied: !dryRun,
+				Total:   1,
+				Changed: 1,
+				Banks: []models.EnvironmentPromotionResult{{
+					BankID:  "BES0049",
+					Changes: []models.FieldChange{{Field: "instant_payments_limit", Before: float64(5000), After: float64(15000)}},
+					Version: 7,
+					Success: true,
+				}},
+			}
+			mockService.On("PromoteEnvironmentConfig", mock.Anything, "BES0049", &services.PromoteEnvironmentRequest{
+				From:   "uat",
+				To:     "production",
+				Fields: []string{"instant_payments_limit"},
+				DryRun: dryRun,
+			}, services.VersionPrecondition{6}).Return(report, nil)
+
+			path := fmt.Sprintf("/banks/BES0049/environments/uat/promote?to=production&dry_run=%t", dryRun)
+			req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"fields":["instant_payments_limit"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"6"`)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if dryRun {
+				assert.Empty(t, w.Header().Get("ETag"))
+			} else {
+				assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+			}
+
+			var response models.APIResponse[models.EnvironmentPromotionReport]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.Success)
+			require.Len(t, response.Data.Banks, 1)
+			assert.Equal(t, "instant_payments_limit", response.Data.Banks[0].Changes[0].Field)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnvironmentPromotionHandler_PromoteEnvironmentConfigs(t *testing.T) {
+	mockService := new(MockEnvironmentPromotionService)
+	router := newEnvironmentPromotionRouter(NewEnvironmentPromotionHandler(mockService))
+
+	report := &models.EnvironmentPromotionReport{
+		From:   models.EnvironmentUAT,
+		To:     models.EnvironmentProduction,
+		Total:  2,
+		Failed: 1,
+		Banks: []models.EnvironmentPromotionResult{
+			{BankID: "BES0049", Changes: []models.FieldChange{}, Success: true},
+			{BankID: "BES0081", Success: false, Error: stringPtr("bank with ID 'BES0081' not found")},
+		},
+	}
+	mockService.On("PromoteEnvironmentConfigs", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return len(filters.Countries) == 1 && filters.Countries[0] == "ES"
+	}), &services.PromoteEnvironmentRequest{From: "uat", To: "production"}).Return(report, nil)
+
+	// The body is optional
+	req, _ := http.NewRequest(http.MethodPost, "/environments/uat/promote?to=production&country=ES", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response models.APIResponse[models.EnvironmentPromotionReport]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, "1 of 2 banks failed", *response.Error)
+	assert.Equal(t, 1, response.Data.Failed)
+	mockService.AssertExpectations(t)
+}
+
+func TestEnvironmentPromotionHandler_Errors(t *testing.T) {
+	conflict := fmt.Errorf("%w: environment config is at version 7", repository.ErrVersionConflict)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		ifMatch        string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"missing target", "/banks/BES0049/environments/uat/promote", "", "", nil, http.StatusBadRequest, "to query parameter is required"},
+		{"malformed dry_run", "/banks/BES0049/environments/uat/promote?to=production&dry_run=maybe", "", "", nil, http.StatusBadRequest, "dry_run"},
+		{"malformed body", "/banks/BES0049/environments/uat/promote?to=production", `{"fields":`, "", nil, http.StatusBadRequest, "Invalid request format"},
+		{"invalid field", "/banks/BES0049/environments/uat/promote?to=production", "", "",
+			errors.New("invalid request: field version cannot be promoted"), http.StatusBadRequest, "field version cannot be promoted"},
+		{"missing source config", "/banks/BES0049/environments/uat/promote?to=production", "", "",
+			errors.New("environment config 'uat' of bank 'BES0049' not found"), http.StatusNotFound, "Environment config not found"},
+		{"unknown bank", "/banks/BES0049/environments/uat/promote?to=production", "", "",
+			errors.New("bank with ID 'BES0049' not found"), http.StatusNotFound, "Bank not found"},
+		{"stale If-Match", "/banks/BES0049/environments/uat/promote?to=production", "", `"6"`, conflict, http.StatusPreconditionFailed, "version conflict"},
+		{"repository failure", "/banks/BES0049/environments/uat/promote?to=production", "", "",
+			errors.New("failed to promote environment configs: connection reset"), http.StatusInternalServerError, "Failed to promote environment config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockEnvironmentPromotionService)
+			router := newEnvironmentPromotionRouter(NewEnvironmentPromotionHandler(mockService))
+			mockService.On("PromoteEnvironmentConfig", mock.Anything, "BES0049", mock.Anything, mock.Anything).Return(nil, tt.serviceErr)
+
+			req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
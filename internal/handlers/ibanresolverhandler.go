@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type IBANResolverHandler struct {
+	resolverService services.IBANResolver
+}
+
+func NewIBANResolverHandler(resolverService services.IBANResolver) *IBANResolverHandler {
+	return &IBANResolverHandler{
+		resolverService: resolverService,
+	}
+}
+
+func (h *IBANResolverHandler) ResolveIBANs(c *gin.Context) {
+	var request services.ResolveIBANsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid JSON request format",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"path", c.Request.URL.Path,
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid request format"),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	resolutions, err := h.resolverService.ResolveIBANs(c.Request.Context(), &request)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid request") {
+			response := models.APIResponse[any]{
+				Success: false,
+				Error:   stringPtr(err.Error()),
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to resolve IBANs",
+				"error", err,
+				"iban_count", len(request.IBANs),
+			)
+		}
+		response := models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to resolve IBANs"),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := models.APIResponse[[]models.IBANResolution]{
+		Success: true,
+		Data:    resolutions,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type MockIBANResolver struct {
+	mock.Mock
+}
+
+func (m *MockIBANResolver) ResolveIBANs(ctx context.Context, request *services.ResolveIBANsRequest) ([]models.IBANResolution, error) {
+	args := m.Called(ctx, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IBANResolution), args.Error(1)
+}
+
+func setupIBANResolverRouter(resolver services.IBANResolver) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewIBANResolverHandler(resolver)
+	router.POST("/banks/resolve-ibans", handler.ResolveIBANs)
+	return router
+}
+
+func TestIBANResolverHandler_ResolveIBANs_Success(t *testing.T) {
+	mockResolver := new(MockIBANResolver)
+	router := setupIBANResolverRouter(mockResolver)
+
+	invalid := "invalid checksum"
+	expected := []models.IBANResolution{
+		{IBAN: "ES9121000418450200051332", Valid: true, Country: "ES", BankCode: "2100", Banks: []models.Bank{{BankID: "BES2100"}}},
+		{IBAN: "ES9221000418450200051332", Valid: false, Banks: []models.Bank{}, Error: &invalid},
+	}
+
+	mockResolver.On("ResolveIBANs", mock.Anything, &services.ResolveIBANsRequest{
+		IBANs: []string{"ES9121000418450200051332", "ES9221000418450200051332"},
+	}).Return(expected, nil)
+
+	body := `{"ibans": ["ES9121000418450200051332", "ES9221000418450200051332"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/banks/resolve-ibans", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.IBANResolution]
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, expected, response.Data)
+
+	mockResolver.AssertExpectations(t)
+}
+
+func TestIBANResolverHandler_ResolveIBANs_InvalidJSON(t *testing.T) {
+	mockResolver := new(MockIBANResolver)
+	router := setupIBANResolverRouter(mockResolver)
+
+	req, _ := http.NewRequest(http.MethodPost, "/banks/resolve-ibans", bytes.NewBufferString(`{"ibans":`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockResolver.AssertNotCalled(t, "ResolveIBANs", mock.Anything, mock.Anything)
+}
+
+func TestIBANResolverHandler_ResolveIBANs_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceError   error
+		expectedStatus int
+	}{
+		{
+			name:           "batch too large",
+			serviceError:   errors.New("invalid request: at most 1000 IBANs are allowed per request"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository failure",
+			serviceError:   errors.New("failed to get banks by codes: connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockResolver := new(MockIBANResolver)
+			router := setupIBANResolverRouter(mockResolver)
+
+			mockResolver.On("ResolveIBANs", mock.Anything, mock.Anything).Return(nil, tt.serviceError)
+
+			req, _ := http.NewRequest(http.MethodPost, "/banks/resolve-ibans", bytes.NewBufferString(`{"ibans": ["ES9121000418450200051332"]}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockResolver.AssertExpectations(t)
+		})
+	}
+}
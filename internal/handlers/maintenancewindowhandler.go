@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type MaintenanceWindowHandler struct {
+	maintenanceWindowService services.MaintenanceWindowService
+}
+
+func NewMaintenanceWindowHandler(maintenanceWindowService services.MaintenanceWindowService) *MaintenanceWindowHandler {
+	return &MaintenanceWindowHandler{
+		maintenanceWindowService: maintenanceWindowService,
+	}
+}
+
+// CreateMaintenanceWindow adds a one-off or recurring maintenance window to an environment of a bank
+func (h *MaintenanceWindowHandler) CreateMaintenanceWindow(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	var request services.CreateMaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid JSON request format",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"path", c.Request.URL.Path,
+			)
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid request format"),
+		})
+		return
+	}
+
+	window, err := h.maintenanceWindowService.CreateMaintenanceWindow(c.Request.Context(), bankID, &request)
+	if err != nil {
+		h.handleError(c, err, bankID, "create maintenance window for")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("maintenance window created",
+			"window_id", window.WindowID.String(),
+			"bank_id", window.BankID,
+			"environment", string(window.Environment),
+			"kind", window.Kind,
+		)
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse[*models.MaintenanceWindow]{
+		Success: true,
+		Data:    window,
+	})
+}
+
+// GetMaintenanceWindows lists the maintenance windows of a bank, optionally of a single environment
+func (h *MaintenanceWindowHandler) GetMaintenanceWindows(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	windows, err := h.maintenanceWindowService.GetMaintenanceWindows(c.Request.Context(), bankID, c.Query("env"))
+	if err != nil {
+		h.handleError(c, err, bankID, "retrieve maintenance windows of")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[[]models.MaintenanceWindow]{
+		Success: true,
+		Data:    windows,
+	})
+}
+
+// DeleteMaintenanceWindow removes a maintenance window of a bank
+func (h *MaintenanceWindowHandler) DeleteMaintenanceWindow(c *gin.Context) {
+	bankID := c.Param("bankId")
+	windowID := c.Param("windowId")
+
+	if err := h.maintenanceWindowService.DeleteMaintenanceWindow(c.Request.Context(), bankID, windowID); err != nil {
+		h.handleError(c, err, bankID, "delete maintenance window of")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("maintenance window deleted",
+			"window_id", windowID,
+			"bank_id", bankID,
+		)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MaintenanceWindowHandler) handleError(c *gin.Context, err error, bankID, action string) {
+	errorMessage := err.Error()
+
+	switch {
+	case strings.Contains(errorMessage, "invalid request"):
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(strings.TrimPrefix(errorMessage, "invalid request: ")),
+		})
+
+	case strings.Contains(errorMessage, "maintenance window") && strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Maintenance window not found"),
+		})
+
+	case strings.Contains(errorMessage, "environment config") && strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Environment config not found"),
+		})
+
+	case strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Bank not found"),
+		})
+
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to "+action+" bank",
+				"error", err,
+				"bank_id", bankID,
+			)
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to " + action + " bank"),
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockMaintenanceWindowService implements the MaintenanceWindowService interface for testing
+type MockMaintenanceWindowService struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, bankID string, request *services.CreateMaintenanceWindowRequest) (*models.MaintenanceWindow, error) {
+	args := m.Called(ctx, bankID, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) GetMaintenanceWindows(ctx context.Context, bankID, environment string) ([]models.MaintenanceWindow, error) {
+	args := m.Called(ctx, bankID, environment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockMaintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, bankID, windowID string) error {
+	args := m.Called(ctx, bankID, windowID)
+	return args.Error(0)
+}
+
+func newMaintenanceWindowRouter(handler *MaintenanceWindowHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/banks/:bankId/maintenance-windows", handler.CreateMaintenanceWindow)
+	router.GET("/banks/:bankId/maintenance-windows", handler.GetMaintenanceWindows)
+	router.DELETE("/banks/:bankId/maintenance-windows/:windowId", handler.DeleteMaintenanceWindow)
+	return router
+}
+
+func TestMaintenanceWindowHandler_CreateMaintenanceWindow(t *testing.T) {
+	mockService := new(MockMaintenanceWindowService)
+	router := newMaintenanceWindowRouter(NewMaintenanceWindowHandler(mockService))
+
+	schedule := "0 1 * * 1-5"
+	minutes := 90
+	window := &models.MaintenanceWindow{
+		WindowID:        uuid.New(),
+		BankID:          "BES0049",
+		Environment:     models.EnvironmentProduction,
+		Kind:            models.MaintenanceWindowRecurring,
+		Schedule:        &schedule,
+		DurationMinutes: &minutes,
+		Timezone:        "Europe/Madrid",
+		Impact:          models.EffectiveStatusDegraded,
+	}
+	mockService.On("CreateMaintenanceWindow", mock.Anything, "BES0049", mock.MatchedBy(func(request *services.CreateMaintenanceWindowRequest) bool {
+		return request.Kind == models.MaintenanceWindowRecurring &&
+			*request.Schedule == schedule &&
+			*request.DurationMinutes == minutes &&
+			request.Timezone == "Europe/Madrid"
+	})).Return(window, nil)
+
+	body := `{"environment":"production","kind":"recurring","schedule":"0 1 * * 1-5","duration_minutes":90,"timezone":"Europe/Madrid","impact":"degraded"}`
+	req, _ := http.NewRequest(http.MethodPost, "/banks/BES0049/maintenance-windows", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.APIResponse[models.MaintenanceWindow]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, window.WindowID, response.Data.WindowID)
+	mockService.AssertExpectations(t)
+}
+
+func TestMaintenanceWindowHandler_GetAndDelete(t *testing.T) {
+	mockService := new(MockMaintenanceWindowService)
+	router := newMaintenanceWindowRouter(NewMaintenanceWindowHandler(mockService))
+
+	windowID := uuid.New()
+	mockService.On("GetMaintenanceWindows", mock.Anything, "BES0049", "uat").
+		Return([]models.MaintenanceWindow{{WindowID: windowID, BankID: "BES0049"}}, nil)
+	mockService.On("DeleteMaintenanceWindow", mock.Anything, "BES0049", windowID.String()).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/maintenance-windows?env=uat", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.APIResponse[[]models.MaintenanceWindow]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, windowID, response.Data[0].WindowID)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/banks/BES0049/maintenance-windows/"+windowID.String(), http.NoBody)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestMaintenanceWindowHandler_Errors(t *testing.T) {
+	windowID := uuid.New().String()
+	validBody := `{"environment":"production","kind":"one_off","starts_at":"2026-11-07T22:00:00Z","ends_at":"2026-11-08T02:00:00Z","impact":"blocked"}`
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		serviceMethod  string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"malformed body", http.MethodPost, "/banks/BES0049/maintenance-windows", `{"environment":`, "", nil, http.StatusBadRequest, "Invalid request format"},
+		{"invalid window", http.MethodPost, "/banks/BES0049/maintenance-windows", validBody,
+			"CreateMaintenanceWindow", errors.New("invalid request: ends_at must be in the future"), http.StatusBadRequest, "ends_at must be in the future"},
+		{"missing environment config", http.MethodPost, "/banks/BES0049/maintenance-windows", validBody,
+			"CreateMaintenanceWindow", errors.New("environment config 'production' of bank 'BES0049' not found"), http.StatusNotFound, "Environment config not found"},
+		{"unknown bank", http.MethodGet, "/banks/BES0049/maintenance-windows", "", "GetMaintenanceWindows", errors.New("bank not found"), http.StatusNotFound, "Bank not found"},
+		{"unknown window", http.MethodDelete, "/banks/BES0049/maintenance-windows/" + windowID, "",
+			"DeleteMaintenanceWindow", errors.New("maintenance window '" + windowID + "' not found"), http.StatusNotFound, "Maintenance window not found"},
+		{"repository failure", http.MethodDelete, "/banks/BES0049/maintenance-windows/" + windowID, "",
+			"DeleteMaintenanceWindow", errors.New("failed to begin transaction: connection reset"), http.StatusInternalServerError, "Failed to delete maintenance window of bank"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMaintenanceWindowService)
+			router := newMaintenanceWindowRouter(NewMaintenanceWindowHandler(mockService))
+			switch tt.serviceMethod {
+			case "CreateMaintenanceWindow":
+				mockService.On(tt.serviceMethod, mock.Anything, "BES0049", mock.Anything).Return(nil, tt.serviceErr)
+			case "GetMaintenanceWindows":
+				mockService.On(tt.serviceMethod, mock.Anything, "BES0049", "").Return(nil, tt.serviceErr)
+			case "DeleteMaintenanceWindow":
+				mockService.On(tt.serviceMethod, mock.Anything, "BES0049", windowID).Return(tt.serviceErr)
+			}
+
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseListParam splits a comma-separated query parameter into its trimmed, non-empty values
+func parseListParam(raw string) []string {
+	var values []string
+	for value := range strings.SplitSeq(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parseMultiValueParam collects the values of a query parameter that may be repeated
+// (?country=ES&country=PT) and/or comma-separated (?country=ES,PT)
+func parseMultiValueParam(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		values = append(values, parseListParam(raw)...)
+	}
+	return values
+}
+
+// parseOptionalBoolParam parses a boolean query parameter, returning nil when it is absent
+func parseOptionalBoolParam(c *gin.Context, key string) (*bool, error) {
+	raw, exists := c.GetQuery(key)
+	if !exists {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameter: must be a boolean", key)
+	}
+	return &value, nil
+}
+
+// parseIntParam parses an integer query parameter, returning zero when it is absent
+func parseIntParam(c *gin.Context, key string) (int, error) {
+	raw, exists := c.GetQuery(key)
+	if !exists {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter: must be a number", key)
+	}
+	return value, nil
+}
+
+// parseOptionalTimeParam parses an RFC 3339 timestamp query parameter, returning nil when it is absent
+func parseOptionalTimeParam(c *gin.Context, key string) (*time.Time, error) {
+	raw, exists := c.GetQuery(key)
+	if !exists {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameter: must be an RFC 3339 timestamp", key)
+	}
+	return &value, nil
+}
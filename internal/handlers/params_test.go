@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListParam(t *testing.T) {
+	assert.Nil(t, parseListParam(""))
+	assert.Equal(t, []string{"bank_id", "name"}, parseListParam(" bank_id, name ,"))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// parseIfMatch reads the If-Match header into a version precondition. The entity tags of a resource
+// are its quoted versions, optionally followed by a dot and a hash of the representation; weak or
+// malformed tags never match, since If-Match uses the strong comparison.
+func parseIfMatch(c *gin.Context) services.VersionPrecondition {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	precondition := services.VersionPrecondition{}
+	for tag := range strings.SplitSeq(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		// GET responses append a representation hash to the version, after a dot
+		opaque, _, _ := strings.Cut(tag[1:len(tag)-1], ".")
+		version, err := strconv.ParseInt(opaque, 10, 64)
+		if err != nil {
+			continue
+		}
+		precondition = append(precondition, version)
+	}
+	return precondition
+}
+
+// setVersionETag sends the entity tag of a resource at version, to be used in a later If-Match
+func setVersionETag(c *gin.Context, version int64) {
+	c.Header("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// versionConflictStatus is the status of a write rejected with a version conflict: 412 when the client
+// sent If-Match, and 409 when an unconditional write raced with another one
+func versionConflictStatus(c *gin.Context) int {
+	if c.GetHeader("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected services.VersionPrecondition
+	}{
+		{"", nil},
+		{"*", nil},
+		{`"3"`, services.VersionPrecondition{3}},
+		{` "3", "5" `, services.VersionPrecondition{3, 5}},
+		{`"3.5d41402abc4b2a76"`, services.VersionPrecondition{3}},
+		{`W/"3"`, services.VersionPrecondition{}},
+		{`"abc", 3`, services.VersionPrecondition{}},
+		{`"5d41402abc4b2a76b9719d911017c592"`, services.VersionPrecondition{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			c.Request.Header.Set("If-Match", tt.header)
+
+			assert.Equal(t, tt.expected, parseIfMatch(c))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+type ScheduledChangeHandler struct {
+	scheduledChangeService services.ScheduledChangeService
+}
+
+func NewScheduledChangeHandler(scheduledChangeService services.ScheduledChangeService) *ScheduledChangeHandler {
+	return &ScheduledChangeHandler{
+		scheduledChangeService: scheduledChangeService,
+	}
+}
+
+// ScheduleChange schedules a change to an environment config of a bank
+func (h *ScheduledChangeHandler) ScheduleChange(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	var request services.ScheduleChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if log, ok := logger.GetLogger(c); ok {
+			log.Warn("invalid JSON request format",
+				"error", err.Error(),
+				"remote_addr", c.ClientIP(),
+				"path", c.Request.URL.Path,
+			)
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Invalid request format"),
+		})
+		return
+	}
+
+	change, err := h.scheduledChangeService.ScheduleChange(c.Request.Context(), bankID, &request)
+	if err != nil {
+		h.handleError(c, err, bankID, "schedule change for")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("config change scheduled",
+			"change_id", change.ChangeID.String(),
+			"bank_id", change.BankID,
+			"environment", string(change.Environment),
+			"apply_at", change.ApplyAt,
+		)
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse[*models.ScheduledChange]{
+		Success: true,
+		Data:    change,
+	})
+}
+
+// GetScheduledChanges lists the scheduled changes of a bank, optionally filtered by status
+func (h *ScheduledChangeHandler) GetScheduledChanges(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	changes, err := h.scheduledChangeService.GetScheduledChanges(c.Request.Context(), bankID, c.Query("status"))
+	if err != nil {
+		h.handleError(c, err, bankID, "retrieve scheduled changes of")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[[]models.ScheduledChange]{
+		Success: true,
+		Data:    changes,
+	})
+}
+
+// CancelScheduledChange cancels a scheduled change that has not completed yet
+func (h *ScheduledChangeHandler) CancelScheduledChange(c *gin.Context) {
+	bankID := c.Param("bankId")
+
+	change, err := h.scheduledChangeService.CancelScheduledChange(c.Request.Context(), bankID, c.Param("changeId"))
+	if err != nil {
+		h.handleError(c, err, bankID, "cancel scheduled change of")
+		return
+	}
+
+	if log, ok := logger.GetLogger(c); ok {
+		log.Info("scheduled change cancelled",
+			"change_id", change.ChangeID.String(),
+			"bank_id", change.BankID,
+			"reverted", change.RevertedAt != nil,
+		)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse[*models.ScheduledChange]{
+		Success: true,
+		Data:    change,
+	})
+}
+
+func (h *ScheduledChangeHandler) handleError(c *gin.Context, err error, bankID, action string) {
+	errorMessage := err.Error()
+
+	switch {
+	case strings.Contains(errorMessage, "invalid request"):
+		c.JSON(http.StatusBadRequest, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(strings.TrimPrefix(errorMessage, "invalid request: ")),
+		})
+
+	case strings.Contains(errorMessage, "conflicts with") || strings.Contains(errorMessage, "is already"):
+		c.JSON(http.StatusConflict, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr(errorMessage),
+		})
+
+	case strings.Contains(errorMessage, "scheduled change") && strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Scheduled change not found"),
+		})
+
+	case strings.Contains(errorMessage, "environment config") && strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Environment config not found"),
+		})
+
+	case strings.Contains(errorMessage, "not found"):
+		c.JSON(http.StatusNotFound, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Bank not found"),
+		})
+
+	default:
+		if log, ok := logger.GetLogger(c); ok {
+			log.Error("failed to "+action+" bank",
+				"error", err,
+				"bank_id", bankID,
+			)
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse[any]{
+			Success: false,
+			Error:   stringPtr("Failed to " + action + " bank"),
+		})
+	}
+}
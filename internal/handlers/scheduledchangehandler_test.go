@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/services"
+)
+
+// MockScheduledChangeService implements the ScheduledChangeService interface for testing
+type MockScheduledChangeService struct {
+	mock.Mock
+}
+
+func (m *MockScheduledChangeService) ScheduleChange(ctx context.Context, bankID string, request *services.ScheduleChangeRequest) (*models.ScheduledChange, error) {
+	args := m.Called(ctx, bankID, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeService) GetScheduledChanges(ctx context.Context, bankID, status string) ([]models.ScheduledChange, error) {
+	args := m.Called(ctx, bankID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeService) CancelScheduledChange(ctx context.Context, bankID, changeID string) (*models.ScheduledChange, error) {
+	args := m.Called(ctx, bankID, changeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeService) ApplyDueChanges(ctx context.Context, limit int) ([]models.ScheduledChange, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ScheduledChange), args.Error(1)
+}
+
+func newScheduledChangeRouter(handler *ScheduledChangeHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/banks/:bankId/scheduled-changes", handler.ScheduleChange)
+	router.GET("/banks/:bankId/scheduled-changes", handler.GetScheduledChanges)
+	router.POST("/banks/:bankId/scheduled-changes/:changeId/cancel", handler.CancelScheduledChange)
+	return router
+}
+
+func TestScheduledChangeHandler_ScheduleChange(t *testing.T) {
+	mockService := new(MockScheduledChangeService)
+	router := newScheduledChangeRouter(NewScheduledChangeHandler(mockService))
+
+	applyAt := time.Date(2026, 11, 7, 22, 0, 0, 0, time.UTC)
+	change := &models.ScheduledChange{
+		ChangeID:    uuid.New(),
+		BankID:      "BES0049",
+		Environment: models.EnvironmentProduction,
+		Changes:     map[string]any{"blocked": true},
+		ApplyAt:     applyAt,
+		Status:      models.ScheduledChangePending,
+		CreatedBy:   "ops@example.com",
+	}
+	mockService.On("ScheduleChange", mock.Anything, "BES0049", mock.MatchedBy(func(request *services.ScheduleChangeRequest) bool {
+		return request.Environment == "production" &&
+			request.Changes["blocked"] == true &&
+			request.ApplyAt.Equal(applyAt) &&
+			request.RevertAt != nil
+	})).Return(change, nil)
+
+	body := `{"environment":"production","changes":{"blocked":true},"apply_at":"2026-11-07T22:00:00Z","revert_at":"2026-11-08T06:00:00Z"}`
+	req, _ := http.NewRequest(http.MethodPost, "/banks/BES0049/scheduled-changes", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.APIResponse[models.ScheduledChange]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, change.ChangeID, response.Data.ChangeID)
+	assert.Equal(t, models.ScheduledChangePending, response.Data.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestScheduledChangeHandler_GetScheduledChanges(t *testing.T) {
+	mockService := new(MockScheduledChangeService)
+	router := newScheduledChangeRouter(NewScheduledChangeHandler(mockService))
+
+	changes := []models.ScheduledChange{{ChangeID: uuid.New(), BankID: "BES0049", Status: models.ScheduledChangeActive}}
+	mockService.On("GetScheduledChanges", mock.Anything, "BES0049", "active").Return(changes, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/banks/BES0049/scheduled-changes?status=active", http.NoBody)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.APIResponse[[]models.ScheduledChange]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, changes[0].ChangeID, response.Data[0].ChangeID)
+}
+
+func TestScheduledChangeHandler_Errors(t *testing.T) {
+	changeID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		serviceMethod  string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{"malformed body", http.MethodPost, "/banks/BES0049/scheduled-changes", `{"environment":`, "", nil, http.StatusBadRequest, "Invalid request format"},
+		{"invalid change", http.MethodPost, "/banks/BES0049/scheduled-changes", `{"environment":"production","changes":{"version":2},"apply_at":"2026-11-07T22:00:00Z"}`,
+			"ScheduleChange", errors.New("invalid request: version is read-only"), http.StatusBadRequest, "version is read-only"},
+		{"overlapping change", http.MethodPost, "/banks/BES0049/scheduled-changes", `{"environment":"production","changes":{"blocked":true},"apply_at":"2026-11-07T22:00:00Z"}`,
+			"ScheduleChange", errors.New("scheduled change conflicts with change 'x', which also sets blocked"), http.StatusConflict, "conflicts with"},
+		{"missing environment config", http.MethodPost, "/banks/BES0049/scheduled-changes", `{"environment":"uat","changes":{"blocked":true},"apply_at":"2026-11-07T22:00:00Z"}`,
+			"ScheduleChange", errors.New("environment config 'uat' of bank 'BES0049' not found"), http.StatusNotFound, "Environment config not found"},
+		{"unknown bank", http.MethodGet, "/banks/BES0049/scheduled-changes", "", "GetScheduledChanges", errors.New("bank not found"), http.StatusNotFound, "Bank not found"},
+		{"unknown change", http.MethodPost, "/banks/BES0049/scheduled-changes/" + changeID + "/cancel", "",
+			"CancelScheduledChange", errors.New("scheduled change '" + changeID + "' not found"), http.StatusNotFound, "Scheduled change not found"},
+		{"finished change", http.MethodPost, "/banks/BES0049/scheduled-changes/" + changeID + "/cancel", "",
+			"CancelScheduledChange", errors.New("scheduled change '" + changeID + "' is already completed"), http.StatusConflict, "is already completed"},
+		{"repository failure", http.MethodPost, "/banks/BES0049/scheduled-changes/" + changeID + "/cancel", "",
+			"CancelScheduledChange", errors.New("failed to begin transaction: connection reset"), http.StatusInternalServerError, "Failed to cancel scheduled change of bank"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockScheduledChangeService)
+			router := newScheduledChangeRouter(NewScheduledChangeHandler(mockService))
+			switch tt.serviceMethod {
+			case "ScheduleChange":
+				mockService.On(tt.serviceMethod, mock.Anything, "BES0049", mock.Anything).Return(nil, tt.serviceErr)
+			case "GetScheduledChanges":
+				mockService.On(tt.serviceMethod, mock.Anything, "BES0049", "").Return(nil, tt.serviceErr)
+			case "CancelScheduledChange":
+				mockService.On(tt.serviceMethod, mock.Anything, "BES0049", changeID).Return(nil, tt.serviceErr)
+			}
+
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.APIResponse[any]
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			require.NotNil(t, response.Error)
+			assert.Contains(t, *response.Error, tt.expectedError)
+		})
+	}
+}
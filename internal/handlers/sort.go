@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// parseSortParam parses a comma-separated sort expression such as "country,-updated_at".
+// A leading "-" sorts the field in descending order. Field names are validated by the services
+// against the columns each resource allows.
+func parseSortParam(raw string) ([]repository.SortField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	sort := make([]repository.SortField, 0, len(parts))
+	seen := make(map[string]bool)
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+
+		field := repository.SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field.Field = strings.TrimPrefix(part, "-")
+			field.Descending = true
+		}
+
+		if field.Field == "" {
+			return nil, errors.New("invalid sort parameter: empty field")
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("invalid sort parameter: duplicated field %s", field.Field)
+		}
+		seen[field.Field] = true
+
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
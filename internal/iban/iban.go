@@ -0,0 +1,137 @@
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidCharacters  = errors.New("invalid characters")
+	ErrUnsupportedCountry = errors.New("unsupported country")
+	ErrInvalidLength      = errors.New("invalid length")
+	ErrInvalidChecksum    = errors.New("invalid checksum")
+)
+
+// countryFormat describes the IBAN structure of a country as published in the SWIFT IBAN registry.
+// BankCodeOffset and BankCodeLength locate the national bank identifier inside the BBAN.
+type countryFormat struct {
+	Length         int
+	BankCodeOffset int
+	BankCodeLength int
+}
+
+var countryFormats = map[string]countryFormat{
+	"AD": {Length: 24, BankCodeOffset: 0, BankCodeLength: 4},
+	"AT": {Length: 20, BankCodeOffset: 0, BankCodeLength: 5},
+	"BE": {Length: 16, BankCodeOffset: 0, BankCodeLength: 3},
+	"BG": {Length: 22, BankCodeOffset: 0, BankCodeLength: 4},
+	"CH": {Length: 21, BankCodeOffset: 0, BankCodeLength: 5},
+	"CY": {Length: 28, BankCodeOffset: 0, BankCodeLength: 3},
+	"CZ": {Length: 24, BankCodeOffset: 0, BankCodeLength: 4},
+	"DE": {Length: 22, BankCodeOffset: 0, BankCodeLength: 8},
+	"DK": {Length: 18, BankCodeOffset: 0, BankCodeLength: 4},
+	"EE": {Length: 20, BankCodeOffset: 0, BankCodeLength: 2},
+	"ES": {Length: 24, BankCodeOffset: 0, BankCodeLength: 4},
+	"FI": {Length: 18, BankCodeOffset: 0, BankCodeLength: 3},
+	"FR": {Length: 27, BankCodeOffset: 0, BankCodeLength: 5},
+	"GB": {Length: 22, BankCodeOffset: 0, BankCodeLength: 4},
+	"GI": {Length: 23, BankCodeOffset: 0, BankCodeLength: 4},
+	"GR": {Length: 27, BankCodeOffset: 0, BankCodeLength: 3},
+	"HR": {Length: 21, BankCodeOffset: 0, BankCodeLength: 7},
+	"HU": {Length: 28, BankCodeOffset: 0, BankCodeLength: 3},
+	"IE": {Length: 22, BankCodeOffset: 0, BankCodeLength: 4},
+	"IS": {Length: 26, BankCodeOffset: 0, BankCodeLength: 2},
+	"IT": {Length: 27, BankCodeOffset: 1, BankCodeLength: 5},
+	"LI": {Length: 21, BankCodeOffset: 0, BankCodeLength: 5},
+	"LT": {Length: 20, BankCodeOffset: 0, BankCodeLength: 5},
+	"LU": {Length: 20, BankCodeOffset: 0, BankCodeLength: 3},
+	"LV": {Length: 21, BankCodeOffset: 0, BankCodeLength: 4},
+	"MC": {Length: 27, BankCodeOffset: 0, BankCodeLength: 5},
+	"MT": {Length: 31, BankCodeOffset: 0, BankCodeLength: 4},
+	"NL": {Length: 18, BankCodeOffset: 0, BankCodeLength: 4},
+	"NO": {Length: 15, BankCodeOffset: 0, BankCodeLength: 4},
+	"PL": {Length: 28, BankCodeOffset: 0, BankCodeLength: 8},
+	"PT": {Length: 25, BankCodeOffset: 0, BankCodeLength: 4},
+	"RO": {Length: 24, BankCodeOffset: 0, BankCodeLength: 4},
+	"SE": {Length: 24, BankCodeOffset: 0, BankCodeLength: 3},
+	"SI": {Length: 19, BankCodeOffset: 0, BankCodeLength: 5},
+	"SK": {Length: 24, BankCodeOffset: 0, BankCodeLength: 4},
+	"SM": {Length: 27, BankCodeOffset: 1, BankCodeLength: 5},
+}
+
+// IBAN is a validated International Bank Account Number split into its parts
+type IBAN struct {
+	Value       string // Electronic format: uppercase, without spaces
+	Country     string
+	CheckDigits string
+	BBAN        string
+	BankCode    string // National bank identifier extracted from the BBAN
+}
+
+// Parse normalizes and validates an IBAN (country length and mod-97 checksum)
+// and extracts its national bank identifier.
+func Parse(input string) (*IBAN, error) {
+	value := Normalize(input)
+
+	if len(value) < 5 {
+		return nil, fmt.Errorf("%w: got %d characters", ErrInvalidLength, len(value))
+	}
+
+	for i := range len(value) {
+		ch := value[i]
+		isLetter := ch >= 'A' && ch <= 'Z'
+		isDigit := ch >= '0' && ch <= '9'
+		if !isLetter && !isDigit {
+			return nil, fmt.Errorf("%w: %q at position %d", ErrInvalidCharacters, ch, i+1)
+		}
+		// Country code must be letters and check digits must be digits
+		if (i < 2 && !isLetter) || (i >= 2 && i < 4 && !isDigit) {
+			return nil, fmt.Errorf("%w: %q at position %d", ErrInvalidCharacters, ch, i+1)
+		}
+	}
+
+	country := value[:2]
+	format, ok := countryFormats[country]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCountry, country)
+	}
+
+	if len(value) != format.Length {
+		return nil, fmt.Errorf("%w: %s IBANs have %d characters, got %d", ErrInvalidLength, country, format.Length, len(value))
+	}
+
+	if mod97(value[4:]+value[:4]) != 1 {
+		return nil, ErrInvalidChecksum
+	}
+
+	bban := value[4:]
+
+	return &IBAN{
+		Value:       value,
+		Country:     country,
+		CheckDigits: value[2:4],
+		BBAN:        bban,
+		BankCode:    bban[format.BankCodeOffset : format.BankCodeOffset+format.BankCodeLength],
+	}, nil
+}
+
+// Normalize converts an IBAN to its electronic format (uppercase, no spaces)
+func Normalize(input string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(input), ""))
+}
+
+// mod97 computes the ISO 7064 MOD 97-10 remainder of an alphanumeric string,
+// where letters are expanded to two digits (A=10 ... Z=35).
+func mod97(value string) int {
+	remainder := 0
+	for i := range len(value) {
+		ch := value[i]
+		if ch >= 'A' && ch <= 'Z' {
+			remainder = (remainder*100 + int(ch-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(ch-'0')) % 97
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,116 @@
+package iban
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_ValidIBANs(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedValue    string
+		expectedCountry  string
+		expectedBankCode string
+	}{
+		{
+			name:             "spain",
+			input:            "ES91 2100 0418 4502 0005 1332",
+			expectedValue:    "ES9121000418450200051332",
+			expectedCountry:  "ES",
+			expectedBankCode: "2100",
+		},
+		{
+			name:             "germany",
+			input:            "DE89370400440532013000",
+			expectedValue:    "DE89370400440532013000",
+			expectedCountry:  "DE",
+			expectedBankCode: "37040044",
+		},
+		{
+			name:             "italy skips the CIN check character",
+			input:            "IT60X0542811101000000123456",
+			expectedValue:    "IT60X0542811101000000123456",
+			expectedCountry:  "IT",
+			expectedBankCode: "05428",
+		},
+		{
+			name:             "france with letters in the BBAN",
+			input:            "FR1420041010050500013M02606",
+			expectedValue:    "FR1420041010050500013M02606",
+			expectedCountry:  "FR",
+			expectedBankCode: "20041",
+		},
+		{
+			name:             "lowercase netherlands",
+			input:            "nl91abna0417164300",
+			expectedValue:    "NL91ABNA0417164300",
+			expectedCountry:  "NL",
+			expectedBankCode: "ABNA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedValue, parsed.Value)
+			assert.Equal(t, tt.expectedCountry, parsed.Country)
+			assert.Equal(t, tt.expectedBankCode, parsed.BankCode)
+		})
+	}
+}
+
+func TestParse_InvalidIBANs(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedError error
+	}{
+		{
+			name:          "empty",
+			input:         "",
+			expectedError: ErrInvalidLength,
+		},
+		{
+			name:          "wrong checksum",
+			input:         "ES9221000418450200051332",
+			expectedError: ErrInvalidChecksum,
+		},
+		{
+			name:          "too short for country",
+			input:         "ES912100041845020005133",
+			expectedError: ErrInvalidLength,
+		},
+		{
+			name:          "unsupported country",
+			input:         "XX9121000418450200051332",
+			expectedError: ErrUnsupportedCountry,
+		},
+		{
+			name:          "punctuation",
+			input:         "ES91-2100-0418-4502-0005-1332",
+			expectedError: ErrInvalidCharacters,
+		},
+		{
+			name:          "letters in check digits",
+			input:         "ESAB21000418450200051332",
+			expectedError: ErrInvalidCharacters,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse(tt.input)
+			require.Error(t, err)
+			assert.Nil(t, parsed)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "ES9121000418450200051332", Normalize(" es91 2100\t0418 4502 0005 1332 "))
+}
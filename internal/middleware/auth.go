@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/wukong0111/go-banks/internal/audit"
 	"github.com/wukong0111/go-banks/internal/auth"
 	"github.com/wukong0111/go-banks/internal/logger"
 	"github.com/wukong0111/go-banks/internal/models"
@@ -118,6 +119,13 @@ func (a *AuthMiddleware) RequireAuth(requiredPermissions ...string) gin.HandlerF
 		c.Set("user_id", claims.Subject)
 		c.Set("permissions", claims.Permissions)
 
+		// Writers record the caller and request in the audit trail
+		requestID, _ := logger.GetRequestID(c)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{
+			Subject:   claims.Subject,
+			RequestID: requestID,
+		}))
+
 		// Continue to the next handler
 		c.Next()
 	})
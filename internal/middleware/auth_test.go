@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/wukong0111/go-banks/internal/audit"
 	"github.com/wukong0111/go-banks/internal/auth"
 	"github.com/wukong0111/go-banks/internal/logger"
 )
@@ -35,7 +36,7 @@ func TestAuthMiddleware_RequireAuth_ValidToken(t *testing.T) {
 	middleware := NewAuthMiddleware(jwtService)
 
 	// Generate valid token
-	token, err := jwtService.GenerateToken([]string{"banks:read"})
+	token, err := jwtService.GenerateToken("test-client", []string{"banks:read"})
 	require.NoError(t, err)
 
 	// Create gin router
@@ -154,7 +155,7 @@ func TestAuthMiddleware_RequireAuth_ExpiredToken(t *testing.T) {
 	middleware := NewAuthMiddleware(jwtService)
 
 	// Generate token (will be expired immediately)
-	token, err := jwtService.GenerateToken([]string{"banks:read"})
+	token, err := jwtService.GenerateToken("test-client", []string{"banks:read"})
 	require.NoError(t, err)
 
 	// Wait to ensure token is expired
@@ -187,7 +188,7 @@ func TestAuthMiddleware_RequireAuth_InsufficientPermissions(t *testing.T) {
 	middleware := NewAuthMiddleware(jwtService)
 
 	// Generate token with only read permission
-	token, err := jwtService.GenerateToken([]string{"banks:read"})
+	token, err := jwtService.GenerateToken("test-client", []string{"banks:read"})
 	require.NoError(t, err)
 
 	// Create gin router that requires write permission
@@ -217,7 +218,7 @@ func TestAuthMiddleware_RequireAuth_MultiplePermissions(t *testing.T) {
 	middleware := NewAuthMiddleware(jwtService)
 
 	// Generate token with read permission
-	token, err := jwtService.GenerateToken([]string{"banks:read"})
+	token, err := jwtService.GenerateToken("test-client", []string{"banks:read"})
 	require.NoError(t, err)
 
 	// Create gin router that requires either read OR write permission
@@ -247,7 +248,7 @@ func TestAuthMiddleware_RequireAuth_NoPermissionsRequired(t *testing.T) {
 	middleware := NewAuthMiddleware(jwtService)
 
 	// Generate token with any permission
-	token, err := jwtService.GenerateToken([]string{"banks:read"})
+	token, err := jwtService.GenerateToken("test-client", []string{"banks:read"})
 	require.NoError(t, err)
 
 	// Create gin router with no specific permission requirements
@@ -277,7 +278,7 @@ func TestAuthMiddleware_GetClaims(t *testing.T) {
 	middleware := NewAuthMiddleware(jwtService)
 
 	// Generate valid token
-	token, err := jwtService.GenerateToken([]string{"banks:read", "banks:write"})
+	token, err := jwtService.GenerateToken("test-client", []string{"banks:read", "banks:write"})
 	require.NoError(t, err)
 
 	// Create gin router
@@ -290,7 +291,7 @@ func TestAuthMiddleware_GetClaims(t *testing.T) {
 			return
 		}
 
-		if claims.Subject != "api-client" {
+		if claims.Subject != "test-client" {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "wrong subject"})
 			return
 		}
@@ -325,7 +326,7 @@ func TestAuthMiddleware_GetPermissions(t *testing.T) {
 
 	// Generate valid token
 	expectedPermissions := []string{"banks:read", "banks:write"}
-	token, err := jwtService.GenerateToken(expectedPermissions)
+	token, err := jwtService.GenerateToken("test-client", expectedPermissions)
 	require.NoError(t, err)
 
 	// Create gin router
@@ -357,3 +358,33 @@ func TestAuthMiddleware_GetPermissions(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestAuthMiddleware_SetsAuditActor(t *testing.T) {
+	// Setup
+	testLogger := logger.NewDiscardLogger()
+	mockProvider := &testSecretProvider{secret: "test-secret"}
+	jwtService, err := auth.NewJWTService(mockProvider, time.Hour, testLogger)
+	require.NoError(t, err)
+	middleware := NewAuthMiddleware(jwtService)
+
+	token, err := jwtService.GenerateToken("ops@example.com", []string{"banks:write"})
+	require.NoError(t, err)
+
+	var actor audit.Actor
+	router := gin.New()
+	router.Use(logger.RequestLogger(testLogger))
+	router.PUT("/test", middleware.RequireAuth("banks:write"), func(c *gin.Context) {
+		actor = audit.ActorFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/test", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ops@example.com", actor.Subject)
+	assert.NotEmpty(t, actor.RequestID)
+}
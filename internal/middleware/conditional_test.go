@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLastModified = time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+func newConditionalRouter(status int) *gin.Engine {
+	router := gin.New()
+	router.GET("/test", ConditionalGET("private, max-age=60"), func(c *gin.Context) {
+		SetLastModified(c, testLastModified.Add(-time.Hour))
+		SetLastModified(c, testLastModified)
+		c.JSON(status, gin.H{"message": "success"})
+	})
+	return router
+}
+
+func doConditionalRequest(router *gin.Engine, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/test", http.NoBody)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestConditionalGET_SetsValidators(t *testing.T) {
+	w := doConditionalRequest(newConditionalRouter(http.StatusOK), nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"success"}`, w.Body.String())
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, w.Header().Get("ETag"))
+	assert.Equal(t, "Wed, 15 Jan 2025 10:30:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+}
+
+func TestConditionalGET_IfNoneMatch(t *testing.T) {
+	router := newConditionalRouter(http.StatusOK)
+	etag := doConditionalRequest(router, nil).Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	tests := []struct {
+		name         string
+		ifNoneMatch  string
+		expectedCode int
+	}{
+		{name: "matching etag", ifNoneMatch: etag, expectedCode: http.StatusNotModified},
+		{name: "weak form of matching etag", ifNoneMatch: "W/" + etag, expectedCode: http.StatusNotModified},
+		{name: "etag in list", ifNoneMatch: `"other", ` + etag, expectedCode: http.StatusNotModified},
+		{name: "wildcard", ifNoneMatch: "*", expectedCode: http.StatusNotModified},
+		{name: "stale etag", ifNoneMatch: `"other"`, expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doConditionalRequest(router, map[string]string{"If-None-Match": tt.ifNoneMatch})
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			if tt.expectedCode == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.NotEmpty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestConditionalGET_IfModifiedSince(t *testing.T) {
+	router := newConditionalRouter(http.StatusOK)
+
+	w := doConditionalRequest(router, map[string]string{"If-Modified-Since": "Wed, 15 Jan 2025 10:30:00 GMT"})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = doConditionalRequest(router, map[string]string{"If-Modified-Since": "Wed, 15 Jan 2025 10:29:59 GMT"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// If-None-Match takes precedence over If-Modified-Since
+	w = doConditionalRequest(router, map[string]string{
+		"If-None-Match":     `"other"`,
+		"If-Modified-Since": "Wed, 15 Jan 2025 10:30:00 GMT",
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestConditionalGET_IgnoresErrorResponses(t *testing.T) {
+	w := doConditionalRequest(newConditionalRouter(http.StatusInternalServerError), map[string]string{"If-None-Match": "*"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"message":"success"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestConditionalGET_KeepsVersionETag(t *testing.T) {
+	message := "before"
+	router := gin.New()
+	router.GET("/test", ConditionalGET("private, no-cache"), func(c *gin.Context) {
+		c.Header("ETag", `"7"`)
+		c.JSON(http.StatusOK, gin.H{"message": message})
+	})
+
+	etag := doConditionalRequest(router, nil).Header().Get("ETag")
+	assert.Regexp(t, `^"7\.[0-9a-f]{16}"$`, etag)
+
+	w := doConditionalRequest(router, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// A computed field changes the body at the same version
+	message = "after"
+	w = doConditionalRequest(router, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Regexp(t, `^"7\.[0-9a-f]{16}"$`, w.Header().Get("ETag"))
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
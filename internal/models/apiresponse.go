@@ -5,6 +5,8 @@ type APIResponse[T any] struct {
 	Data       T           `json:"data,omitempty"`
 	Error      *string     `json:"error,omitempty"`
 	Pagination *Pagination `json:"pagination,omitempty"`
+	// CursorPagination is set instead of Pagination on keyset-paginated listings
+	CursorPagination *CursorPagination `json:"cursor_pagination,omitempty"`
 }
 
 type Pagination struct {
@@ -13,3 +15,12 @@ type Pagination struct {
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
 }
+
+// CursorPagination describes a keyset-paginated page. NextCursor is nil on the last page
+// and Total is only present when explicitly requested.
+type CursorPagination struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+	Total      *int    `json:"total,omitempty"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations recorded in the audit trail
+const (
+	AuditOperationCreate  = "create"
+	AuditOperationUpdate  = "update"
+	AuditOperationDelete  = "delete"
+	AuditOperationRestore = "restore"
+	AuditOperationPurge   = "purge"
+)
+
+// AuditEntry is a catalog write recorded in the audit trail. Before and After are JSON snapshots of
+// the entity around the write, null on the side where it did not exist or was soft-deleted.
+type AuditEntry struct {
+	AuditID    int64           `json:"audit_id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Operation  string          `json:"operation"`
+	Actor      string          `json:"actor"`
+	RequestID  *string         `json:"request_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// BankHistoryEntry is a write of a bank with the fields it changed. Revision is the revision the write
+// left the bank at, absent when it removed the bank.
+type BankHistoryEntry struct {
+	AuditID   int64         `json:"audit_id"`
+	Revision  *int64        `json:"revision,omitempty"`
+	Operation string        `json:"operation"`
+	Actor     string        `json:"actor"`
+	RequestID *string       `json:"request_id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// FieldChange is a field whose value differs between two snapshots. Field is a dotted path such as
+// environment_configs.production.blocked; Before or After is null when the field did not exist.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// BankRevision is the state a write left a bank in. Revision is the version of the bank after the write.
+type BankRevision struct {
+	BankID    string               `json:"bank_id"`
+	Revision  int64                `json:"revision"`
+	Bank      BankWithEnvironments `json:"bank"`
+	CreatedAt time.Time            `json:"created_at"`
+}
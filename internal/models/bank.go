@@ -23,6 +23,7 @@ type Bank struct {
 	Keywords               map[string]any `json:"keywords" db:"keywords"`
 	Attribute              map[string]any `json:"attribute" db:"attribute"`
 	AuthTypeChoiceRequired bool           `json:"auth_type_choice_required" db:"auth_type_choice_required"`
+	Version                int64          `json:"version" db:"version"`
 	CreatedAt              time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt              time.Time      `json:"updated_at" db:"updated_at"`
 }
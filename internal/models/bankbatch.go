@@ -0,0 +1,7 @@
+package models
+
+// BankBatch is the result of fetching many banks by ID at once
+type BankBatch struct {
+	Banks    map[string]*BankWithEnvironments `json:"banks"`     // Found banks keyed by bank ID
+	NotFound []string                         `json:"not_found"` // Requested IDs without a bank, in request order
+}
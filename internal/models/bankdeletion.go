@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// BankDeletion is the outcome of soft-deleting a bank
+type BankDeletion struct {
+	BankID    string    `json:"bank_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// BankPurge is the outcome of permanently removing a bank
+type BankPurge struct {
+	BankID                    string `json:"bank_id"`
+	EnvironmentConfigsDeleted int64  `json:"environment_configs_deleted"`
+}
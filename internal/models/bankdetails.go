@@ -18,6 +18,7 @@ const (
 type BankWithEnvironment struct {
 	Bank
 	EnvironmentConfig *BankEnvironmentConfig `json:"environment_config"`
+	EffectiveStatus   *EffectiveStatus       `json:"effective_status,omitempty"`
 }
 
 // GetBank returns the bank information
@@ -34,6 +35,7 @@ func (b *BankWithEnvironment) GetType() BankDetailsType {
 type BankWithEnvironments struct {
 	Bank
 	EnvironmentConfigs map[string]*BankEnvironmentConfig `json:"environment_configs"`
+	EffectiveStatuses  map[string]*EffectiveStatus       `json:"effective_statuses,omitempty"`
 }
 
 // GetBank returns the bank information
@@ -31,6 +31,13 @@ type BankEnvironmentConfig struct {
 	FrequencyPeriodicPayment     *string         `json:"frequency_periodic_payment" db:"frequency_periodic_payment"`
 	ConfigPeriodicPayment        *string         `json:"config_periodic_payment" db:"config_periodic_payment"`
 	AppAuthSetupRequired         bool            `json:"app_auth_setup_required" db:"app_auth_setup_required"`
+	Version                      int64           `json:"version" db:"version"`
 	CreatedAt                    time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt                    time.Time       `json:"updated_at" db:"updated_at"`
 }
+
+// BankEnvironmentConfigWithStatus represents an environment configuration with its effective status
+type BankEnvironmentConfigWithStatus struct {
+	BankEnvironmentConfig
+	EffectiveStatus *EffectiveStatus `json:"effective_status,omitempty"`
+}
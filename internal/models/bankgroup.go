@@ -12,6 +12,21 @@ type BankGroup struct {
 	Description *string   `json:"description" db:"description"`
 	LogoURL     *string   `json:"logo_url" db:"logo_url"`
 	Website     *string   `json:"website" db:"website"`
+	Version     int64     `json:"version" db:"version"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// BankGroupDetails is a bank group with the number of its banks
+type BankGroupDetails struct {
+	BankGroup
+	MemberCounts BankGroupMemberCounts `json:"member_counts"`
+}
+
+// BankGroupMemberCounts counts the banks of a group, soft-deleted ones excluded. ByEnvironment counts
+// the banks that have a config for each environment.
+type BankGroupMemberCounts struct {
+	Total         int            `json:"total"`
+	ByCountry     map[string]int `json:"by_country"`
+	ByEnvironment map[string]int `json:"by_environment"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/google/uuid"
+
+// Policies for the member banks of a deleted bank group
+const (
+	BankGroupDeletePolicyReject   = "reject"   // refuse to delete a group that still has banks
+	BankGroupDeletePolicyDetach   = "detach"   // leave the banks without a group
+	BankGroupDeletePolicyReassign = "reassign" // move the banks to another group
+)
+
+// BankGroupDeletion is the outcome of deleting a bank group. Deleted is false when the reject
+// policy found member banks, which are then listed in AffectedBanks.
+type BankGroupDeletion struct {
+	GroupID       uuid.UUID  `json:"group_id"`
+	Policy        string     `json:"policy"`
+	ReassignedTo  *uuid.UUID `json:"reassigned_to,omitempty"`
+	Deleted       bool       `json:"deleted"`
+	AffectedBanks []string   `json:"affected_banks"` // IDs of the member banks, soft-deleted ones included
+}
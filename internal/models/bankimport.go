@@ -0,0 +1,36 @@
+package models
+
+// Actions applied to a bank during a bulk import
+const (
+	ImportActionCreate  = "create"
+	ImportActionUpdate  = "update"
+	ImportActionReplace = "replace"
+)
+
+// FieldError explains why a value was rejected. Field is empty for errors that concern the whole row.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// BankImportRowResult is the outcome of a single row of an import file
+type BankImportRowResult struct {
+	Row         int          `json:"row"`                   // Line number in the file
+	BankID      string       `json:"bank_id,omitempty"`     // Bank the row belongs to
+	Environment string       `json:"environment,omitempty"` // Environment config carried by the row, if any
+	Action      string       `json:"action,omitempty"`      // create, update or replace
+	Success     bool         `json:"success"`
+	Errors      []FieldError `json:"errors,omitempty"`
+}
+
+// BankImportReport summarizes a bulk import
+type BankImportReport struct {
+	Mode      string                `json:"mode"`
+	DryRun    bool                  `json:"dry_run"`
+	Atomic    bool                  `json:"atomic"`
+	Applied   bool                  `json:"applied"` // Whether any change was committed
+	Total     int                   `json:"total"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Rows      []BankImportRowResult `json:"rows"`
+}
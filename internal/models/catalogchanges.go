@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Entity types reported in catalog tombstones
+const (
+	EntityTypeBank              = "bank"
+	EntityTypeEnvironmentConfig = "environment_config"
+	EntityTypeBankGroup         = "bank_group"
+)
+
+// CatalogChanges is a page of catalog changes for delta sync. Clients apply Deleted first
+// and then upsert the changed entities, and resume from NextToken on the next call.
+type CatalogChanges struct {
+	Banks              []Bank                  `json:"banks"`
+	EnvironmentConfigs []BankEnvironmentConfig `json:"environment_configs"`
+	BankGroups         []BankGroup             `json:"bank_groups"`
+	Deleted            []Tombstone             `json:"deleted"`
+	NextToken          string                  `json:"next_token"`
+	HasMore            bool                    `json:"has_more"`
+}
+
+// Tombstone records the deletion of a catalog entity
+type Tombstone struct {
+	EntityType  string    `json:"entity_type"`
+	EntityID    string    `json:"entity_id"`
+	BankID      *string   `json:"bank_id,omitempty"`
+	Environment *string   `json:"environment,omitempty"`
+	DeletedAt   time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Drift statuses of a bank between two environments
+const (
+	DriftStatusDifferent       = "different"         // Both configs exist and some compared fields differ
+	DriftStatusMissingInBase   = "missing_in_base"   // Only the target environment has a config
+	DriftStatusMissingInTarget = "missing_in_target" // Only the base environment has a config
+)
+
+// EnvironmentDriftField is a compared field whose value differs between the two environments. The
+// value of an environment without a config is null.
+type EnvironmentDriftField struct {
+	Field  string `json:"field"`
+	Base   any    `json:"base"`
+	Target any    `json:"target"`
+}
+
+// EnvironmentDrift is a bank whose config of the target environment does not match that of the base
+type EnvironmentDrift struct {
+	BankID  string                  `json:"bank_id"`
+	Name    string                  `json:"name"`
+	Country string                  `json:"country"`
+	Status  string                  `json:"status"`
+	Fields  []EnvironmentDriftField `json:"fields"`
+}
+
+// EnvironmentDriftReport lists the banks whose environment configs differ between two environments
+type EnvironmentDriftReport struct {
+	Base        EnvironmentType    `json:"base"`
+	Target      EnvironmentType    `json:"target"`
+	Fields      []string           `json:"fields"`   // Compared fields
+	Compared    int                `json:"compared"` // Banks with a config in either environment
+	Drifted     int                `json:"drifted"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Banks       []EnvironmentDrift `json:"banks"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// EnvironmentPromotionResult is the outcome of promoting the environment config of a single bank
+type EnvironmentPromotionResult struct {
+	BankID  string        `json:"bank_id"`
+	Created bool          `json:"created"`           // Whether the target config did not exist and is created
+	Changes []FieldChange `json:"changes"`           // Promoted fields whose value differs in the target config
+	Version int64         `json:"version,omitempty"` // Version of the target config after the promotion
+	Success bool          `json:"success"`
+	Error   *string       `json:"error,omitempty"`
+}
+
+// EnvironmentPromotionReport summarizes the promotion of environment config values from one
+// environment to another, previewed when DryRun is set
+type EnvironmentPromotionReport struct {
+	From    EnvironmentType              `json:"from"`
+	To      EnvironmentType              `json:"to"`
+	Fields  []string                     `json:"fields"`
+	DryRun  bool                         `json:"dry_run"`
+	Applied bool                         `json:"applied"` // Whether any change was committed
+	Total   int                          `json:"total"`
+	Changed int                          `json:"changed"`
+	Failed  int                          `json:"failed"`
+	Banks   []EnvironmentPromotionResult `json:"banks"`
+}
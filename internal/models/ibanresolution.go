@@ -0,0 +1,11 @@
+package models
+
+// IBANResolution is the outcome of resolving a single IBAN to the banks that issue it
+type IBANResolution struct {
+	IBAN     string  `json:"iban"`
+	Valid    bool    `json:"valid"`
+	Country  string  `json:"country,omitempty"`
+	BankCode string  `json:"bank_code,omitempty"`
+	Banks    []Bank  `json:"banks"`
+	Error    *string `json:"error,omitempty"`
+}
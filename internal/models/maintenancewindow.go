@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of maintenance window
+const (
+	MaintenanceWindowOneOff    = "one_off"
+	MaintenanceWindowRecurring = "recurring"
+)
+
+// Effective statuses of a bank in an environment, from best to worst. The impact of a maintenance
+// window is the status it forces while it is in progress.
+const (
+	EffectiveStatusAvailable = "available"
+	EffectiveStatusDegraded  = "degraded"
+	EffectiveStatusBlocked   = "blocked"
+)
+
+// MaintenanceWindow is a period of planned maintenance of a bank in an environment. A one-off window
+// runs from StartsAt to EndsAt; a recurring one starts whenever Schedule, a cron expression evaluated
+// in Timezone, matches and lasts DurationMinutes.
+type MaintenanceWindow struct {
+	WindowID        uuid.UUID       `json:"window_id"`
+	BankID          string          `json:"bank_id"`
+	Environment     EnvironmentType `json:"environment"`
+	Kind            string          `json:"kind"`
+	StartsAt        *time.Time      `json:"starts_at,omitempty"`
+	EndsAt          *time.Time      `json:"ends_at,omitempty"`
+	Schedule        *string         `json:"schedule,omitempty"`
+	DurationMinutes *int            `json:"duration_minutes,omitempty"`
+	Timezone        string          `json:"timezone"`
+	Impact          string          `json:"impact"`
+	Reason          *string         `json:"reason"`
+	CreatedBy       string          `json:"created_by"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// EffectiveStatus is the availability of a bank in an environment at a given time, combining the flags
+// of its environment config with its maintenance windows. Until is the end of the maintenance window
+// in progress, if any. ChangedAt is the last time a maintenance window of the environment started or
+// ended, since the status can change then without the config changing.
+type EffectiveStatus struct {
+	Status              string     `json:"status"`
+	Reason              *string    `json:"reason"`
+	MaintenanceWindowID *uuid.UUID `json:"maintenance_window_id,omitempty"`
+	Until               *time.Time `json:"until,omitempty"`
+	ChangedAt           *time.Time `json:"changed_at,omitempty"`
+}
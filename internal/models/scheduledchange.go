@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lifecycle of a scheduled change: pending until ApplyAt, then active until RevertAt when it has one,
+// and completed once nothing is left to do
+const (
+	ScheduledChangePending   = "pending"
+	ScheduledChangeActive    = "active"
+	ScheduledChangeCompleted = "completed"
+	ScheduledChangeCancelled = "cancelled"
+	ScheduledChangeFailed    = "failed"
+)
+
+// ScheduledChange is a change to the environment config of a bank applied at a later time. Changes
+// maps config fields to their new values. When RevertAt is set, Previous holds the values the change
+// replaced, which are written back at RevertAt.
+type ScheduledChange struct {
+	ChangeID    uuid.UUID       `json:"change_id"`
+	BankID      string          `json:"bank_id"`
+	Environment EnvironmentType `json:"environment"`
+	Changes     map[string]any  `json:"changes"`
+	Previous    map[string]any  `json:"previous,omitempty"`
+	ApplyAt     time.Time       `json:"apply_at"`
+	RevertAt    *time.Time      `json:"revert_at"`
+	Reason      *string         `json:"reason"`
+	Status      string          `json:"status"`
+	Error       *string         `json:"error,omitempty"`
+	CreatedBy   string          `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+	AppliedAt   *time.Time      `json:"applied_at"`
+	RevertedAt  *time.Time      `json:"reverted_at"`
+	CancelledAt *time.Time      `json:"cancelled_at"`
+}
+
+// IsOpen reports whether the change still has something to apply or revert
+func (c *ScheduledChange) IsOpen() bool {
+	return c.Status == ScheduledChangePending || c.Status == ScheduledChangeActive
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// bankSnapshot is the audited representation of a bank: the bank with its environment configs and
+// its soft-delete marker, so that deletions and restores show up as a change of deleted_at
+type bankSnapshot struct {
+	models.BankWithEnvironments
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// auditBank records a write of a bank in the audit trail, given the snapshot taken before it, and
+// keeps the state it left the bank in as a revision
+func auditBank(ctx context.Context, tx pgx.Tx, bankID, operation string, before []byte) error {
+	snapshot, err := readBankSnapshot(ctx, tx, bankID)
+	if err != nil {
+		return err
+	}
+
+	var after []byte
+	if snapshot != nil {
+		if after, err = json.Marshal(snapshot); err != nil {
+			return fmt.Errorf("failed to encode bank snapshot: %w", err)
+		}
+	}
+
+	if err := insertAuditEntry(ctx, tx, models.EntityTypeBank, bankID, operation, before, after); err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return nil
+	}
+
+	query := "INSERT INTO bank_revisions (bank_id, revision, snapshot) VALUES ($1, $2, $3)"
+	if _, err := tx.Exec(ctx, query, bankID, snapshot.Version, string(after)); err != nil {
+		return fmt.Errorf("failed to record bank revision: %w", err)
+	}
+
+	return nil
+}
+
+// auditBankGroup records a write of a bank group in the audit trail, given the snapshot taken before it
+func auditBankGroup(ctx context.Context, tx pgx.Tx, groupID uuid.UUID, operation string, before []byte) error {
+	after, err := snapshotBankGroup(ctx, tx, groupID)
+	if err != nil {
+		return err
+	}
+	return insertAuditEntry(ctx, tx, models.EntityTypeBankGroup, groupID.String(), operation, before, after)
+}
+
+// snapshotBank locks a bank and returns its JSON snapshot, or nil when it does not exist
+func snapshotBank(ctx context.Context, tx pgx.Tx, bankID string) ([]byte, error) {
+	snapshot, err := readBankSnapshot(ctx, tx, bankID)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot)
+}
+
+// readBankSnapshot locks a bank and reads it with its environment configs, or returns nil when it
+// does not exist
+func readBankSnapshot(ctx context.Context, tx pgx.Tx, bankID string) (*bankSnapshot, error) {
+	query := `
+		SELECT ` + bankSelectColumns + `,
+			(SELECT COALESCE(jsonb_agg(to_jsonb(bec) ORDER BY bec.environment), '[]'::jsonb)
+			FROM bank_environment_configs bec
+			WHERE bec.bank_id = b.bank_id),
+			b.deleted_at
+		FROM banks b
+		WHERE b.bank_id = $1
+		FOR UPDATE OF b
+	`
+
+	var snapshot bankSnapshot
+	var configs []models.BankEnvironmentConfig
+	targets := append(bankScanTargets(&snapshot.Bank), &configs, &snapshot.DeletedAt)
+	if err := tx.QueryRow(ctx, query, bankID).Scan(targets...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read bank for audit: %w", err)
+	}
+
+	snapshot.EnvironmentConfigs = make(map[string]*models.BankEnvironmentConfig, len(configs))
+	for i := range configs {
+		snapshot.EnvironmentConfigs[string(configs[i].Environment)] = &configs[i]
+	}
+
+	return &snapshot, nil
+}
+
+// snapshotBankGroup locks a bank group and returns its JSON snapshot, or nil when it does not exist
+func snapshotBankGroup(ctx context.Context, tx pgx.Tx, groupID uuid.UUID) ([]byte, error) {
+	query := `
+		SELECT ` + bankGroupSelectColumns + `
+		FROM bank_groups bg
+		WHERE bg.group_id = $1
+		FOR UPDATE
+	`
+
+	var group models.BankGroup
+	if err := tx.QueryRow(ctx, query, groupID).Scan(bankGroupScanTargets(&group)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read bank group for audit: %w", err)
+	}
+
+	return json.Marshal(&group)
+}
+
+// insertAuditEntry records a write made by the actor of ctx. It runs in the transaction of the write,
+// so that the entry is only kept when the write is committed.
+func insertAuditEntry(ctx context.Context, tx pgx.Tx, entityType, entityID, operation string, before, after []byte) error {
+	actor := audit.ActorFromContext(ctx)
+	var requestID *string
+	if actor.RequestID != "" {
+		requestID = &actor.RequestID
+	}
+
+	query := `
+		INSERT INTO audit_log (entity_type, entity_id, operation, actor, request_id, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.Exec(ctx, query,
+		entityType, entityID, operation, actor.Subject, requestID,
+		nullableJSON(before), nullableJSON(after),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// nullableJSON maps a missing snapshot to SQL NULL rather than to an empty JSON document
+func nullableJSON(snapshot []byte) any {
+	if snapshot == nil {
+		return nil
+	}
+	return string(snapshot)
+}
+
+// PostgresAuditRepository implements AuditRepository interface
+type PostgresAuditRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresAuditRepository creates a new PostgresAuditRepository instance
+func NewPostgresAuditRepository(db *pgxpool.Pool) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// GetAuditEntries returns a page of the audit entries of an entity, newest first, and the total number
+// of entries it has
+func (r *PostgresAuditRepository) GetAuditEntries(ctx context.Context, entityType, entityID string, page, limit int) ([]models.AuditEntry, int, error) {
+	var total int
+	countQuery := "SELECT COUNT(*) FROM audit_log WHERE entity_type = $1 AND entity_id = $2"
+	if err := r.db.QueryRow(ctx, countQuery, entityType, entityID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+		SELECT audit_id, entity_type, entity_id, operation, actor, request_id, before, after, created_at
+		FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY audit_id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, entityType, entityID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after []byte
+		err := rows.Scan(
+			&entry.AuditID, &entry.EntityType, &entry.EntityID, &entry.Operation,
+			&entry.Actor, &entry.RequestID, &before, &after, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+func TestAuditRepository_Interface_Implementation(_ *testing.T) {
+	var _ AuditRepository = (*PostgresAuditRepository)(nil)
+}
+
+func TestBankSnapshot_JSON(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	snapshot := bankSnapshot{
+		BankWithEnvironments: models.BankWithEnvironments{
+			Bank: models.Bank{BankID: "BES0049", Name: "Santander"},
+			EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+				"production": {Environment: models.EnvironmentProduction, Blocked: true},
+			},
+		},
+		DeletedAt: &deletedAt,
+	}
+
+	data, err := json.Marshal(&snapshot)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "BES0049", decoded["bank_id"])
+	assert.Equal(t, "2026-03-02T10:00:00Z", decoded["deleted_at"])
+	configs := decoded["environment_configs"].(map[string]any)
+	assert.Equal(t, true, configs["production"].(map[string]any)["blocked"])
+}
+
+func TestNullableJSON(t *testing.T) {
+	assert.Nil(t, nullableJSON(nil))
+	assert.Equal(t, `{"name":"Santander"}`, nullableJSON([]byte(`{"name":"Santander"}`)))
+}
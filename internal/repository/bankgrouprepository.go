@@ -2,12 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/wukong0111/go-banks/internal/models"
 )
 
+// bankGroupSelectColumns lists the bank_groups columns in the order expected by bankGroupScanTargets
+const bankGroupSelectColumns = `bg.group_id, bg.name, bg.description, bg.logo_url, bg.website, bg.version, bg.created_at, bg.updated_at`
+
 // PostgresBankGroupRepository implements BankGroupRepository interface
 type PostgresBankGroupRepository struct {
 	db *pgxpool.Pool
@@ -18,15 +25,26 @@ func NewPostgresBankGroupRepository(db *pgxpool.Pool) *PostgresBankGroupReposito
 	return &PostgresBankGroupRepository{db: db}
 }
 
-// GetBankGroups retrieves all bank groups from the database
-func (r *PostgresBankGroupRepository) GetBankGroups(ctx context.Context) ([]models.BankGroup, error) {
+// GetBankGroups retrieves the bank groups matching the filters, a page of them when a limit is set
+func (r *PostgresBankGroupRepository) GetBankGroups(ctx context.Context, filters *BankGroupFilters) ([]models.BankGroup, error) {
+	whereClause, args := buildBankGroupWhereClause(filters)
+
 	query := `
-		SELECT group_id, name, description, logo_url, website, created_at, updated_at
-		FROM bank_groups 
-		ORDER BY name
-	`
+		SELECT ` + bankGroupSelectColumns + `
+		FROM bank_groups bg
+		` + whereClause + `
+	` + buildSortClause(filters.Sort, bankGroupSortColumns, "bg.name", "bg.group_id")
+
+	if filters.Limit > 0 {
+		offset := 0
+		if filters.Page > 1 {
+			offset = (filters.Page - 1) * filters.Limit
+		}
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filters.Limit, offset)
+	}
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,16 +53,7 @@ func (r *PostgresBankGroupRepository) GetBankGroups(ctx context.Context) ([]mode
 	var bankGroups []models.BankGroup
 	for rows.Next() {
 		var bg models.BankGroup
-		err := rows.Scan(
-			&bg.GroupID,
-			&bg.Name,
-			&bg.Description,
-			&bg.LogoURL,
-			&bg.Website,
-			&bg.CreatedAt,
-			&bg.UpdatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(bankGroupScanTargets(&bg)...); err != nil {
 			return nil, err
 		}
 		bankGroups = append(bankGroups, bg)
@@ -56,3 +65,101 @@ func (r *PostgresBankGroupRepository) GetBankGroups(ctx context.Context) ([]mode
 
 	return bankGroups, nil
 }
+
+// CountBankGroups returns the number of bank groups matching the filters, ignoring pagination
+func (r *PostgresBankGroupRepository) CountBankGroups(ctx context.Context, filters *BankGroupFilters) (int, error) {
+	whereClause, args := buildBankGroupWhereClause(filters)
+
+	var total int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM bank_groups bg "+whereClause, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count bank groups: %w", err)
+	}
+
+	return total, nil
+}
+
+// GetBankGroupByID retrieves a single bank group
+func (r *PostgresBankGroupRepository) GetBankGroupByID(ctx context.Context, groupID uuid.UUID) (*models.BankGroup, error) {
+	query := `
+		SELECT ` + bankGroupSelectColumns + `
+		FROM bank_groups bg
+		WHERE bg.group_id = $1
+	`
+
+	var group models.BankGroup
+	if err := r.db.QueryRow(ctx, query, groupID).Scan(bankGroupScanTargets(&group)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("bank group with ID '%s' not found", groupID)
+		}
+		return nil, fmt.Errorf("failed to get bank group: %w", err)
+	}
+
+	return &group, nil
+}
+
+// GetBankGroupMemberCounts counts the banks of a group per country and per configured environment
+func (r *PostgresBankGroupRepository) GetBankGroupMemberCounts(ctx context.Context, groupID uuid.UUID) (*models.BankGroupMemberCounts, error) {
+	counts := &models.BankGroupMemberCounts{
+		ByCountry:     make(map[string]int),
+		ByEnvironment: make(map[string]int),
+	}
+
+	countryQuery := `
+		SELECT b.country, COUNT(*)
+		FROM banks b
+		WHERE b.bank_group_id = $1 AND b.deleted_at IS NULL
+		GROUP BY b.country
+	`
+	if err := r.collectCounts(ctx, countryQuery, groupID, counts.ByCountry); err != nil {
+		return nil, fmt.Errorf("failed to count banks per country: %w", err)
+	}
+	for _, count := range counts.ByCountry {
+		counts.Total += count
+	}
+
+	environmentQuery := `
+		SELECT bec.environment::text, COUNT(*)
+		FROM bank_environment_configs bec
+		JOIN banks b ON b.bank_id = bec.bank_id
+		WHERE b.bank_group_id = $1 AND b.deleted_at IS NULL
+		GROUP BY bec.environment
+	`
+	if err := r.collectCounts(ctx, environmentQuery, groupID, counts.ByEnvironment); err != nil {
+		return nil, fmt.Errorf("failed to count banks per environment: %w", err)
+	}
+
+	return counts, nil
+}
+
+// collectCounts reads (key, count) rows into counts
+func (r *PostgresBankGroupRepository) collectCounts(ctx context.Context, query string, groupID uuid.UUID, counts map[string]int) error {
+	rows, err := r.db.Query(ctx, query, groupID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		counts[key] = count
+	}
+
+	return rows.Err()
+}
+
+// buildBankGroupWhereClause translates the list filters into a WHERE clause and its positional arguments
+func buildBankGroupWhereClause(filters *BankGroupFilters) (string, []any) {
+	if filters.Name == "" {
+		return "", nil
+	}
+	return "WHERE bg.name ILIKE $1", []any{"%" + filters.Name + "%"}
+}
+
+// bankGroupScanTargets returns the destinations of bankGroupSelectColumns
+func bankGroupScanTargets(bg *models.BankGroup) []any {
+	return []any{&bg.GroupID, &bg.Name, &bg.Description, &bg.LogoURL, &bg.Website, &bg.Version, &bg.CreatedAt, &bg.UpdatedAt}
+}
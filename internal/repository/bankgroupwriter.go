@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/wukong0111/go-banks/internal/models"
@@ -19,14 +22,23 @@ func NewPostgresBankGroupWriter(db *pgxpool.Pool) *PostgresBankGroupWriter {
 	return &PostgresBankGroupWriter{db: db}
 }
 
-// CreateBankGroup inserts a new bank group into the database
+// CreateBankGroup inserts a new bank group into the database and sets its version and timestamps on it
 func (w *PostgresBankGroupWriter) CreateBankGroup(ctx context.Context, bankGroup *models.BankGroup) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	query := `
 		INSERT INTO bank_groups (group_id, name, description, logo_url, website, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING version, created_at, updated_at
 	`
 
-	_, err := w.db.Exec(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		bankGroup.GroupID,
 		bankGroup.Name,
 		bankGroup.Description,
@@ -34,36 +46,183 @@ func (w *PostgresBankGroupWriter) CreateBankGroup(ctx context.Context, bankGroup
 		bankGroup.Website,
 		bankGroup.CreatedAt,
 		bankGroup.UpdatedAt,
-	)
+	).Scan(&bankGroup.Version, &bankGroup.CreatedAt, &bankGroup.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := auditBankGroup(ctx, tx, bankGroup.GroupID, models.AuditOperationCreate, nil); err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit(ctx)
 }
 
-// UpdateBankGroup updates an existing bank group in the database
+// UpdateBankGroup writes a bank group read at bankGroup.Version and sets the new version and
+// updated_at on it
 func (w *PostgresBankGroupWriter) UpdateBankGroup(ctx context.Context, bankGroup *models.BankGroup) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, err := snapshotBankGroup(ctx, tx, bankGroup.GroupID)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE bank_groups SET
 			name = $2, description = $3, logo_url = $4, website = $5,
-			updated_at = CURRENT_TIMESTAMP
-		WHERE group_id = $1
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE group_id = $1 AND version = $6
+		RETURNING version, updated_at
 	`
 
-	result, err := w.db.Exec(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		bankGroup.GroupID,
 		bankGroup.Name,
 		bankGroup.Description,
 		bankGroup.LogoURL,
 		bankGroup.Website,
-	)
+		bankGroup.Version,
+	).Scan(&bankGroup.Version, &bankGroup.UpdatedAt)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return bankGroupUpdateMissError(ctx, tx, bankGroup.GroupID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update bank group: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("bank group with ID '%s' not found", bankGroup.GroupID)
+	if err := auditBankGroup(ctx, tx, bankGroup.GroupID, models.AuditOperationUpdate, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBankGroup deletes a bank group and applies the policy to the banks that reference it, soft-deleted
+// ones included, in a single transaction. With the reject policy nothing is changed when the group still
+// has banks, and the returned deletion lists them with Deleted set to false.
+func (w *PostgresBankGroupWriter) DeleteBankGroup(ctx context.Context, groupID uuid.UUID, policy string, reassignTo *uuid.UUID) (*models.BankGroupDeletion, error) {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// The snapshot locks the group row
+	groupBefore, err := snapshotBankGroup(ctx, tx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if groupBefore == nil {
+		return nil, fmt.Errorf("bank group with ID '%s' not found", groupID)
+	}
+	if reassignTo != nil {
+		// The target is share-locked so that it cannot be deleted before the banks are moved
+		if err := lockBankGroup(ctx, tx, *reassignTo, "FOR SHARE"); err != nil {
+			return nil, fmt.Errorf("invalid reassign_to: %w", err)
+		}
+	}
+
+	deletion := &models.BankGroupDeletion{
+		GroupID:      groupID,
+		Policy:       policy,
+		ReassignedTo: reassignTo,
+	}
+
+	var membersQuery string
+	var args []any
+	switch policy {
+	case models.BankGroupDeletePolicyReject:
+		membersQuery = "SELECT bank_id FROM banks WHERE bank_group_id = $1 ORDER BY bank_id"
+		args = []any{groupID}
+	case models.BankGroupDeletePolicyDetach:
+		membersQuery = "UPDATE banks SET bank_group_id = NULL, version = version + 1 WHERE bank_group_id = $1 RETURNING bank_id"
+		args = []any{groupID}
+	case models.BankGroupDeletePolicyReassign:
+		membersQuery = "UPDATE banks SET bank_group_id = $2, version = version + 1 WHERE bank_group_id = $1 RETURNING bank_id"
+		args = []any{groupID, reassignTo}
+	default:
+		return nil, fmt.Errorf("invalid policy: %s", policy)
+	}
+
+	// Banks moved by the policy are audited as updates, so their snapshots are taken first
+	membersBefore := map[string][]byte{}
+	if policy != models.BankGroupDeletePolicyReject {
+		rows, err := tx.Query(ctx, "SELECT bank_id FROM banks WHERE bank_group_id = $1 ORDER BY bank_id", groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read member banks: %w", err)
+		}
+		members, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read member banks: %w", err)
+		}
+		for _, bankID := range members {
+			if membersBefore[bankID], err = snapshotBank(ctx, tx, bankID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	rows, err := tx.Query(ctx, membersQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s policy to member banks: %w", policy, err)
+	}
+	deletion.AffectedBanks, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read member banks: %w", err)
+	}
+	if deletion.AffectedBanks == nil {
+		deletion.AffectedBanks = []string{}
+	}
+
+	if policy == models.BankGroupDeletePolicyReject && len(deletion.AffectedBanks) > 0 {
+		return deletion, nil
 	}
 
+	for _, bankID := range deletion.AffectedBanks {
+		if err := auditBank(ctx, tx, bankID, models.AuditOperationUpdate, membersBefore[bankID]); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM bank_groups WHERE group_id = $1", groupID); err != nil {
+		return nil, fmt.Errorf("failed to delete bank group: %w", err)
+	}
+
+	if err := insertAuditEntry(ctx, tx, models.EntityTypeBankGroup, groupID.String(), models.AuditOperationDelete, groupBefore, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	deletion.Deleted = true
+	return deletion, nil
+}
+
+// lockBankGroup locks a bank group row for the rest of the transaction
+func lockBankGroup(ctx context.Context, tx pgx.Tx, groupID uuid.UUID, lock string) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, "SELECT group_id FROM bank_groups WHERE group_id = $1 "+lock, groupID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("bank group with ID '%s' not found", groupID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock bank group: %w", err)
+	}
 	return nil
 }
@@ -5,11 +5,29 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/wukong0111/go-banks/internal/models"
 )
 
+// bankSelectColumns lists the banks columns in the order expected by scanBank
+const bankSelectColumns = `
+	b.bank_id, b.name, b.bank_codes, b.bic, b.real_name, b.api, b.api_version,
+	b.aspsp, b.product_code, b.country, b.bank_group_id, b.logo_url,
+	b.documentation, b.keywords, b.attribute, b.auth_type_choice_required,
+	b.version, b.created_at, b.updated_at`
+
+// environmentConfigSelectColumns lists the bank_environment_configs columns in the order expected by scanEnvironmentConfig
+const environmentConfigSelectColumns = `
+	bec.bank_id, bec.environment, bec.enabled, bec.blocked, bec.blocked_text, bec.risky, bec.risky_message,
+	bec.supports_instant_payments, bec.instant_payments_activated, bec.instant_payments_limit,
+	bec.ok_status_codes_simple_payment, bec.ok_status_codes_instant_payment,
+	bec.ok_status_codes_periodic_payment, bec.enabled_periodic_payment,
+	bec.frequency_periodic_payment, bec.config_periodic_payment, bec.app_auth_setup_required,
+	bec.version, bec.created_at, bec.updated_at`
+
 type PostgresBankRepository struct {
 	db *pgxpool.Pool
 }
@@ -19,17 +37,198 @@ func NewPostgresBankRepository(db *pgxpool.Pool) *PostgresBankRepository {
 }
 
 func (r *PostgresBankRepository) GetBanks(ctx context.Context, filters *BankFilters) ([]models.Bank, *models.Pagination, error) {
-	// Build WHERE clause and arguments
-	var whereConditions []string
+	whereClause, args := buildBankWhereClause(filters)
+
+	// Count total records
+	total, err := r.countBanks(ctx, whereClause, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orderClause, args := buildBankOrderClause(filters, args)
+	argIndex := len(args) + 1
+
+	// Calculate pagination
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Limit < 1 || filters.Limit > 100 {
+		filters.Limit = 20
+	}
+
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+	offset := (filters.Page - 1) * filters.Limit
+
+	pagination := &models.Pagination{
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	// Get banks with pagination
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM banks b
+		%s
+		%s
+		LIMIT $%d OFFSET $%d
+	`, bankSelectColumns, whereClause, orderClause, argIndex, argIndex+1)
+
+	args = append(args, filters.Limit, offset)
+
+	banks, err := r.queryBanks(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return banks, pagination, nil
+}
+
+// GetBanksByCursor returns a page of banks using keyset pagination on (name, bank_id).
+// Unlike GetBanks it does not drift when banks are inserted or deleted during a walk and its cost
+// does not grow on deep pages. A rename can still move a bank across the cursor, so that it is
+// skipped or returned twice; sync clients should use the change feed of /api/banks/changes.
+func (r *PostgresBankRepository) GetBanksByCursor(ctx context.Context, filters *BankFilters) ([]models.Bank, *models.CursorPagination, error) {
+	if filters.Limit < 1 || filters.Limit > 100 {
+		filters.Limit = 20
+	}
+
+	whereClause, args := buildBankWhereClause(filters)
+
+	pagination := &models.CursorPagination{
+		Limit: filters.Limit,
+	}
+
+	// Total is optional in cursor mode because it requires a full scan
+	if filters.IncludeTotal {
+		total, err := r.countBanks(ctx, whereClause, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		pagination.Total = &total
+	}
+
+	if filters.Cursor != "" {
+		cursor, err := DecodeBankCursor(filters.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		whereClause += fmt.Sprintf(" AND (b.name, b.bank_id) > ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Name, cursor.BankID)
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM banks b
+		%s
+		ORDER BY b.name, b.bank_id
+		LIMIT $%d
+	`, bankSelectColumns, whereClause, len(args)+1)
+
+	args = append(args, filters.Limit+1)
+
+	banks, err := r.queryBanks(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(banks) > filters.Limit {
+		banks = banks[:filters.Limit]
+		last := banks[len(banks)-1]
+		nextCursor := EncodeBankCursor(BankCursor{Name: last.Name, BankID: last.BankID})
+		pagination.NextCursor = &nextCursor
+		pagination.HasMore = true
+	}
+
+	return banks, pagination, nil
+}
+
+// StreamBanks calls fn for every bank matching the filters, with its environment configs, ignoring
+// pagination. Rows are decoded one at a time as they arrive from the server, so memory use does not
+// grow with the catalog; the connection stays checked out until fn has seen the last bank.
+func (r *PostgresBankRepository) StreamBanks(ctx context.Context, filters *BankFilters, fn func(bank *models.BankWithEnvironments) error) error {
+	whereClause, args := buildBankWhereClause(filters)
+	orderClause, args := buildBankOrderClause(filters, args)
+
+	// Configs are aggregated per bank so that each row carries a complete bank
+	configFilter := ""
+	if filters.Environment != "" && filters.Environment != "all" {
+		args = append(args, filters.Environment)
+		configFilter = fmt.Sprintf("AND bec.environment = $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			(SELECT COALESCE(jsonb_agg(to_jsonb(bec) ORDER BY bec.environment), '[]'::jsonb)
+			FROM bank_environment_configs bec
+			WHERE bec.bank_id = b.bank_id %s)
+		FROM banks b
+		%s
+		%s
+	`, bankSelectColumns, configFilter, whereClause, orderClause)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query banks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bank models.BankWithEnvironments
+		var configs []models.BankEnvironmentConfig
+		if err := rows.Scan(append(bankScanTargets(&bank.Bank), &configs)...); err != nil {
+			return fmt.Errorf("failed to scan bank: %w", err)
+		}
+
+		bank.EnvironmentConfigs = make(map[string]*models.BankEnvironmentConfig, len(configs))
+		for i := range configs {
+			bank.EnvironmentConfigs[string(configs[i].Environment)] = &configs[i]
+		}
+
+		if err := fn(&bank); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating bank rows: %w", err)
+	}
+
+	return nil
+}
+
+// buildBankWhereClause translates the list filters into a WHERE clause and its positional arguments
+func buildBankWhereClause(filters *BankFilters) (string, []any) {
+	// Soft-deleted banks are never listed
+	whereConditions := []string{"b.deleted_at IS NULL"}
 	var args []any
 	argIndex := 1
 
-	// Environment filter
+	// Environment and environment state filters, evaluated on the same config row
+	var envConditions []string
 	if filters.Environment != "" && filters.Environment != "all" {
-		whereConditions = append(whereConditions, fmt.Sprintf("EXISTS (SELECT 1 FROM bank_environment_configs bec WHERE bec.bank_id = b.bank_id AND bec.environment = $%d)", argIndex))
+		envConditions = append(envConditions, fmt.Sprintf("bec.environment = $%d", argIndex))
 		args = append(args, filters.Environment)
 		argIndex++
 	}
+	for _, condition := range filters.EnvironmentState.conditions() {
+		envConditions = append(envConditions, fmt.Sprintf("%s = $%d", condition.column, argIndex))
+		args = append(args, *condition.value)
+		argIndex++
+	}
+	if filters.EnvironmentState.EffectiveStatus != "" {
+		var condition string
+		condition, args = effectiveStatusCondition(&filters.EnvironmentState, args)
+		envConditions = append(envConditions, condition)
+		argIndex = len(args) + 1
+	}
+	if len(envConditions) > 0 {
+		whereConditions = append(whereConditions, "EXISTS (SELECT 1 FROM bank_environment_configs bec WHERE bec.bank_id = b.bank_id AND "+
+			strings.Join(envConditions, " AND ")+")")
+	}
 
 	// Name filter (partial match)
 	if filters.Name != "" {
@@ -38,92 +237,192 @@ func (r *PostgresBankRepository) GetBanks(ctx context.Context, filters *BankFilt
 		argIndex++
 	}
 
-	// API filter
-	if filters.API != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("b.api = $%d", argIndex))
-		args = append(args, filters.API)
+	// API filter (any of)
+	if len(filters.APIs) > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.api = ANY($%d)", argIndex))
+		args = append(args, filters.APIs)
 		argIndex++
 	}
 
-	// Country filter
-	if filters.Country != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("b.country = $%d", argIndex))
-		args = append(args, filters.Country)
+	// Country filter (any of)
+	if len(filters.Countries) > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.country = ANY($%d)", argIndex))
+		args = append(args, filters.Countries)
 		argIndex++
 	}
 
-	// Build WHERE clause
-	whereClause := ""
-	if len(whereConditions) > 0 {
-		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	// Bank group filter
+	if filters.BankGroupID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.bank_group_id = $%d::uuid", argIndex))
+		args = append(args, filters.BankGroupID)
+		argIndex++
 	}
 
-	// Count total records
-	countQuery := "SELECT COUNT(*) FROM banks b " + whereClause
-	var total int
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, nil, fmt.Errorf("failed to count banks: %w", err)
+	// Auth type choice flag filter
+	if filters.AuthTypeChoiceRequired != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.auth_type_choice_required = $%d", argIndex))
+		args = append(args, *filters.AuthTypeChoiceRequired)
+		argIndex++
 	}
 
-	// Calculate pagination
-	if filters.Page < 1 {
-		filters.Page = 1
+	// BIC presence filter (empty strings count as missing)
+	if filters.HasBIC != nil {
+		if *filters.HasBIC {
+			whereConditions = append(whereConditions, "COALESCE(b.bic, '') <> ''")
+		} else {
+			whereConditions = append(whereConditions, "COALESCE(b.bic, '') = ''")
+		}
 	}
-	if filters.Limit < 1 || filters.Limit > 100 {
-		filters.Limit = 20
+
+	// Modification date filter
+	if filters.UpdatedSince != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("b.updated_at >= $%d", argIndex))
+		args = append(args, *filters.UpdatedSince)
+		argIndex++
 	}
 
-	totalPages := (total + filters.Limit - 1) / filters.Limit
-	offset := (filters.Page - 1) * filters.Limit
+	// Free-text search: full-text over name, real_name, bank_codes and keywords,
+	// plus trigram word similarity on names to tolerate typos and partial words
+	if filters.Query != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf(`(b.search_vector @@ websearch_to_tsquery('simple', immutable_unaccent($%[1]d))
+			OR lower(immutable_unaccent($%[1]d)) <%% lower(immutable_unaccent(b.name))
+			OR lower(immutable_unaccent($%[1]d)) <%% lower(immutable_unaccent(COALESCE(b.real_name, ''))))`, argIndex))
+		args = append(args, filters.Query)
+	}
 
-	pagination := &models.Pagination{
-		Page:       filters.Page,
-		Limit:      filters.Limit,
-		Total:      total,
-		TotalPages: totalPages,
+	return "WHERE " + strings.Join(whereConditions, " AND "), args
+}
+
+// conditions pairs every set flag with its bank_environment_configs column
+func (f *EnvironmentStateFilters) conditions() []environmentStateCondition {
+	candidates := []environmentStateCondition{
+		{column: "bec.enabled", value: f.Enabled},
+		{column: "bec.blocked", value: f.Blocked},
+		{column: "bec.risky", value: f.Risky},
+		{column: "bec.supports_instant_payments", value: f.SupportsInstantPayments},
+		{column: "bec.instant_payments_activated", value: f.InstantPaymentsActivated},
+		{column: "bec.enabled_periodic_payment", value: f.EnabledPeriodicPayment},
+		{column: "bec.app_auth_setup_required", value: f.AppAuthSetupRequired},
 	}
 
-	// Get banks with pagination
-	query := fmt.Sprintf(`
-		SELECT 
-			bank_id, name, bank_codes, bic, real_name, api, api_version, 
-			aspsp, product_code, country, bank_group_id, logo_url, 
-			documentation, keywords, attribute, auth_type_choice_required,
-			created_at, updated_at
-		FROM banks b
-		%s
-		ORDER BY b.name
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	var conditions []environmentStateCondition
+	for _, candidate := range candidates {
+		if candidate.value != nil {
+			conditions = append(conditions, candidate)
+		}
+	}
+	return conditions
+}
 
-	args = append(args, filters.Limit, offset)
+type environmentStateCondition struct {
+	column string
+	value  *bool
+}
+
+// effectiveStatusCondition matches the config rows whose effective status is the one requested. A config
+// is blocked when it is disabled, blocked or under a blocking maintenance window, degraded when it is
+// otherwise risky or under a degrading window, and available otherwise.
+func effectiveStatusCondition(f *EnvironmentStateFilters, args []any) (string, []any) {
+	maintenance := func(impact string) string {
+		var bankIDs, environments []string
+		for _, active := range f.ActiveMaintenance {
+			if active.Impact == impact {
+				bankIDs = append(bankIDs, active.BankID)
+				environments = append(environments, active.Environment)
+			}
+		}
+		if len(bankIDs) == 0 {
+			return "FALSE"
+		}
+		args = append(args, bankIDs, environments)
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest($%d::text[], $%d::text[]) AS m(bank_id, environment)"+
+			" WHERE m.bank_id = bec.bank_id AND m.environment = bec.environment::text)", len(args)-1, len(args))
+	}
+
+	blocked := "(NOT bec.enabled OR bec.blocked OR " + maintenance(models.EffectiveStatusBlocked) + ")"
+	degraded := "(bec.risky OR " + maintenance(models.EffectiveStatusDegraded) + ")"
+
+	switch f.EffectiveStatus {
+	case models.EffectiveStatusBlocked:
+		return blocked, args
+	case models.EffectiveStatusDegraded:
+		return "NOT " + blocked + " AND " + degraded, args
+	default:
+		return "NOT " + blocked + " AND NOT " + degraded, args
+	}
+}
+
+// buildBankOrderClause returns the ORDER BY clause for offset listings. Explicit sorts come first,
+// otherwise searches are ranked by relevance; name and bank_id always break ties so pages are deterministic.
+func buildBankOrderClause(filters *BankFilters, args []any) (string, []any) {
+	// An explicit sort always takes precedence over relevance
+	if len(filters.Sort) > 0 {
+		return buildSortClause(filters.Sort, bankSortColumns, "b.name", "b.bank_id"), args
+	}
+
+	if filters.Query == "" {
+		return "ORDER BY b.name, b.bank_id", args
+	}
+
+	argIndex := len(args) + 1
+	orderClause := fmt.Sprintf(`ORDER BY (
+			ts_rank(b.search_vector, websearch_to_tsquery('simple', immutable_unaccent($%[1]d)))
+			+ GREATEST(
+				word_similarity(lower(immutable_unaccent($%[1]d)), lower(immutable_unaccent(b.name))),
+				word_similarity(lower(immutable_unaccent($%[1]d)), lower(immutable_unaccent(COALESCE(b.real_name, ''))))
+			)
+		) DESC, b.name, b.bank_id`, argIndex)
+
+	return orderClause, append(args, filters.Query)
+}
+
+// countBanks returns the number of banks matching the given WHERE clause
+func (r *PostgresBankRepository) countBanks(ctx context.Context, whereClause string, args []any) (int, error) {
+	countQuery := "SELECT COUNT(*) FROM banks b " + whereClause
+	var total int
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count banks: %w", err)
+	}
+	return total, nil
+}
 
+// queryBanks runs a query selecting bankSelectColumns and scans every row
+func (r *PostgresBankRepository) queryBanks(ctx context.Context, query string, args ...any) ([]models.Bank, error) {
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to query banks: %w", err)
+		return nil, fmt.Errorf("failed to query banks: %w", err)
 	}
 	defer rows.Close()
 
 	var banks []models.Bank
 	for rows.Next() {
 		var bank models.Bank
-		err := rows.Scan(
-			&bank.BankID, &bank.Name, &bank.BankCodes, &bank.BIC, &bank.RealName,
-			&bank.API, &bank.APIVersion, &bank.ASPSP, &bank.ProductCode, &bank.Country,
-			&bank.BankGroupID, &bank.LogoURL, &bank.Documentation, &bank.Keywords,
-			&bank.Attribute, &bank.AuthTypeChoiceRequired, &bank.CreatedAt, &bank.UpdatedAt,
-		)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to scan bank: %w", err)
+		if err := scanBank(rows, &bank); err != nil {
+			return nil, fmt.Errorf("failed to scan bank: %w", err)
 		}
 		banks = append(banks, bank)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, nil, fmt.Errorf("error iterating bank rows: %w", err)
+		return nil, fmt.Errorf("error iterating bank rows: %w", err)
 	}
 
-	return banks, pagination, nil
+	return banks, nil
+}
+
+// scanBank scans a row selected with bankSelectColumns into bank
+func scanBank(row pgx.Row, bank *models.Bank) error {
+	return row.Scan(bankScanTargets(bank)...)
+}
+
+// bankScanTargets returns the destinations of bankSelectColumns, so that queries can select extra columns after them
+func bankScanTargets(bank *models.Bank) []any {
+	return []any{
+		&bank.BankID, &bank.Name, &bank.BankCodes, &bank.BIC, &bank.RealName,
+		&bank.API, &bank.APIVersion, &bank.ASPSP, &bank.ProductCode, &bank.Country,
+		&bank.BankGroupID, &bank.LogoURL, &bank.Documentation, &bank.Keywords,
+		&bank.Attribute, &bank.AuthTypeChoiceRequired, &bank.Version, &bank.CreatedAt, &bank.UpdatedAt,
+	}
 }
 
 func (r *PostgresBankRepository) GetBankByID(ctx context.Context, bankID string) (*models.Bank, error) {
@@ -132,9 +431,9 @@ func (r *PostgresBankRepository) GetBankByID(ctx context.Context, bankID string)
 			bank_id, name, bank_codes, bic, real_name, api, api_version, 
 			aspsp, product_code, country, bank_group_id, logo_url, 
 			documentation, keywords, attribute, auth_type_choice_required,
-			created_at, updated_at
+			version, created_at, updated_at
 		FROM banks 
-		WHERE bank_id = $1
+		WHERE bank_id = $1 AND deleted_at IS NULL
 	`
 
 	var bank models.Bank
@@ -142,7 +441,7 @@ func (r *PostgresBankRepository) GetBankByID(ctx context.Context, bankID string)
 		&bank.BankID, &bank.Name, &bank.BankCodes, &bank.BIC, &bank.RealName,
 		&bank.API, &bank.APIVersion, &bank.ASPSP, &bank.ProductCode, &bank.Country,
 		&bank.BankGroupID, &bank.LogoURL, &bank.Documentation, &bank.Keywords,
-		&bank.Attribute, &bank.AuthTypeChoiceRequired, &bank.CreatedAt, &bank.UpdatedAt,
+		&bank.Attribute, &bank.AuthTypeChoiceRequired, &bank.Version, &bank.CreatedAt, &bank.UpdatedAt,
 	)
 
 	if err != nil {
@@ -152,24 +451,33 @@ func (r *PostgresBankRepository) GetBankByID(ctx context.Context, bankID string)
 	return &bank, nil
 }
 
+// GetBanksByIDs loads many banks in a single query. Unknown IDs are simply absent from the result.
+func (r *PostgresBankRepository) GetBanksByIDs(ctx context.Context, bankIDs []string) ([]models.Bank, error) {
+	if len(bankIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + bankSelectColumns + `
+		FROM banks b
+		WHERE b.bank_id = ANY($1) AND b.deleted_at IS NULL
+	`
+
+	return r.queryBanks(ctx, query, bankIDs)
+}
+
 func (r *PostgresBankRepository) GetBankEnvironmentConfigs(ctx context.Context, bankID, environment string) (map[string]*models.BankEnvironmentConfig, error) {
 	query := `
-		SELECT 
-			bank_id, environment, enabled, blocked, blocked_text, risky, risky_message,
-			supports_instant_payments, instant_payments_activated, instant_payments_limit,
-			ok_status_codes_simple_payment, ok_status_codes_instant_payment, 
-			ok_status_codes_periodic_payment, enabled_periodic_payment, 
-			frequency_periodic_payment, config_periodic_payment, app_auth_setup_required,
-			created_at, updated_at
-		FROM bank_environment_configs 
-		WHERE bank_id = $1
+		SELECT ` + environmentConfigSelectColumns + `
+		FROM bank_environment_configs bec
+		WHERE bec.bank_id = $1
 	`
 
 	args := []any{bankID}
 
 	// If specific environment is requested, add filter
 	if environment != "" {
-		query += " AND environment = $2"
+		query += " AND bec.environment = $2"
 		args = append(args, environment)
 	}
 
@@ -182,17 +490,7 @@ func (r *PostgresBankRepository) GetBankEnvironmentConfigs(ctx context.Context,
 	configs := make(map[string]*models.BankEnvironmentConfig)
 	for rows.Next() {
 		var config models.BankEnvironmentConfig
-		err := rows.Scan(
-			&config.BankID, &config.Environment, &config.Enabled, &config.Blocked,
-			&config.BlockedText, &config.Risky, &config.RiskyMessage,
-			&config.SupportsInstantPayments, &config.InstantPaymentsActivated,
-			&config.InstantPaymentsLimit, &config.OkStatusCodesSimplePayment,
-			&config.OkStatusCodesInstantPayment, &config.OkStatusCodesPeriodicPayment,
-			&config.EnabledPeriodicPayment, &config.FrequencyPeriodicPayment,
-			&config.ConfigPeriodicPayment, &config.AppAuthSetupRequired,
-			&config.CreatedAt, &config.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanEnvironmentConfig(rows, &config); err != nil {
 			return nil, fmt.Errorf("failed to scan bank environment config: %w", err)
 		}
 		configs[string(config.Environment)] = &config
@@ -205,6 +503,213 @@ func (r *PostgresBankRepository) GetBankEnvironmentConfigs(ctx context.Context,
 	return configs, nil
 }
 
+// GetEnvironmentConfigsByBankIDs loads the environment configs of many banks in a single query,
+// keyed by bank ID and then by environment. Banks without configs are absent from the result.
+func (r *PostgresBankRepository) GetEnvironmentConfigsByBankIDs(ctx context.Context, bankIDs []string, environment string) (map[string]map[string]*models.BankEnvironmentConfig, error) {
+	configs := make(map[string]map[string]*models.BankEnvironmentConfig)
+	if len(bankIDs) == 0 {
+		return configs, nil
+	}
+
+	query := `
+		SELECT ` + environmentConfigSelectColumns + `
+		FROM bank_environment_configs bec
+		WHERE bec.bank_id = ANY($1)
+	`
+
+	args := []any{bankIDs}
+
+	// If specific environment is requested, add filter
+	if environment != "" {
+		query += " AND bec.environment = $2"
+		args = append(args, environment)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank environment configs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var config models.BankEnvironmentConfig
+		if err := scanEnvironmentConfig(rows, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan bank environment config: %w", err)
+		}
+		if configs[config.BankID] == nil {
+			configs[config.BankID] = make(map[string]*models.BankEnvironmentConfig)
+		}
+		configs[config.BankID][string(config.Environment)] = &config
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environment config rows: %w", err)
+	}
+
+	return configs, nil
+}
+
+// GetBanksByBIC returns the banks whose BIC identifies the same institution as bic.
+// A BIC8 (or BIC11 with the XXX primary office) matches both the BIC8 and BIC11 forms,
+// while a branch BIC11 only matches that branch or the institution's primary office.
+func (r *PostgresBankRepository) GetBanksByBIC(ctx context.Context, bic string) ([]models.Bank, error) {
+	institution := bic
+	branch := ""
+	if len(bic) > 8 {
+		institution = bic[:8]
+		branch = bic[8:]
+	}
+
+	// The institution match is served by the idx_banks_bic8 expression index
+	query := `
+		SELECT ` + bankSelectColumns + `
+		FROM banks b
+		WHERE LEFT(UPPER(b.bic), 8) = $1
+			AND b.deleted_at IS NULL
+			AND (
+				$2 = ''
+				OR COALESCE(NULLIF(SUBSTRING(UPPER(b.bic) FROM 9 FOR 3), ''), 'XXX') IN ($2, 'XXX')
+			)
+		ORDER BY b.name, b.bank_id
+	`
+
+	return r.queryBanks(ctx, query, institution, branch)
+}
+
+// GetBanksByCode returns the banks of a country whose bank_codes contain exactly code
+func (r *PostgresBankRepository) GetBanksByCode(ctx context.Context, code, country string) ([]models.Bank, error) {
+	query := `
+		SELECT ` + bankSelectColumns + `
+		FROM banks b
+		WHERE b.bank_codes @> jsonb_build_array($1::text)
+			AND b.country = $2
+			AND b.deleted_at IS NULL
+		ORDER BY b.name, b.bank_id
+	`
+
+	return r.queryBanks(ctx, query, code, country)
+}
+
+// GetBanksByCodes returns every bank whose bank_codes contain at least one of codes.
+// Callers are expected to match the country themselves since codes are only unique per country.
+func (r *PostgresBankRepository) GetBanksByCodes(ctx context.Context, codes []string) ([]models.Bank, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + bankSelectColumns + `
+		FROM banks b
+		WHERE b.bank_codes ?| $1
+			AND b.deleted_at IS NULL
+		ORDER BY b.name, b.bank_id
+	`
+
+	return r.queryBanks(ctx, query, codes)
+}
+
+// GetBankGroupsByIDs loads many bank groups in a single query, keyed by group ID.
+// Unknown IDs are absent from the result.
+func (r *PostgresBankRepository) GetBankGroupsByIDs(ctx context.Context, groupIDs []uuid.UUID) (map[uuid.UUID]*models.BankGroup, error) {
+	groups := make(map[uuid.UUID]*models.BankGroup)
+	if len(groupIDs) == 0 {
+		return groups, nil
+	}
+
+	ids := make([]string, 0, len(groupIDs))
+	for _, groupID := range groupIDs {
+		ids = append(ids, groupID.String())
+	}
+
+	query := `
+		SELECT ` + bankGroupSelectColumns + `
+		FROM bank_groups bg
+		WHERE bg.group_id = ANY($1::uuid[])
+	`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank groups: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var group models.BankGroup
+		if err := rows.Scan(bankGroupScanTargets(&group)...); err != nil {
+			return nil, fmt.Errorf("failed to scan bank group: %w", err)
+		}
+		groups[group.GroupID] = &group
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bank group rows: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetMaintenanceWindows returns the maintenance windows of a bank, or of every live bank when bankID is
+// empty, optionally restricted to an environment
+func (r *PostgresBankRepository) GetMaintenanceWindows(ctx context.Context, bankID, environment string) ([]models.MaintenanceWindow, error) {
+	query := `
+		SELECT ` + maintenanceWindowSelectColumns + `
+		FROM maintenance_windows mw
+		JOIN banks b ON b.bank_id = mw.bank_id
+		WHERE b.deleted_at IS NULL
+	`
+
+	var args []any
+	if bankID != "" {
+		args = append(args, bankID)
+		query += fmt.Sprintf(" AND mw.bank_id = $%d", len(args))
+	}
+	if environment != "" {
+		args = append(args, environment)
+		query += fmt.Sprintf(" AND mw.environment = $%d", len(args))
+	}
+	query += " ORDER BY mw.bank_id, mw.environment, mw.created_at, mw.window_id"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []models.MaintenanceWindow{}
+	for rows.Next() {
+		var window models.MaintenanceWindow
+		if err := scanMaintenanceWindow(rows, &window); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating maintenance window rows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// scanEnvironmentConfig scans a row selected with environmentConfigSelectColumns into config
+func scanEnvironmentConfig(row pgx.Row, config *models.BankEnvironmentConfig) error {
+	return row.Scan(environmentConfigScanTargets(config)...)
+}
+
+// environmentConfigScanTargets returns the destinations of environmentConfigSelectColumns
+func environmentConfigScanTargets(config *models.BankEnvironmentConfig) []any {
+	return []any{
+		&config.BankID, &config.Environment, &config.Enabled, &config.Blocked,
+		&config.BlockedText, &config.Risky, &config.RiskyMessage,
+		&config.SupportsInstantPayments, &config.InstantPaymentsActivated,
+		&config.InstantPaymentsLimit, &config.OkStatusCodesSimplePayment,
+		&config.OkStatusCodesInstantPayment, &config.OkStatusCodesPeriodicPayment,
+		&config.EnabledPeriodicPayment, &config.FrequencyPeriodicPayment,
+		&config.ConfigPeriodicPayment, &config.AppAuthSetupRequired,
+		&config.Version, &config.CreatedAt, &config.UpdatedAt,
+	}
+}
+
 func (r *PostgresBankRepository) GetAvailableFilters(ctx context.Context) (*models.BankFilters, error) {
 	filters := &models.BankFilters{}
 
@@ -212,6 +717,7 @@ func (r *PostgresBankRepository) GetAvailableFilters(ctx context.Context) (*mode
 	countriesQuery := `
 		SELECT country, COUNT(*) as count
 		FROM banks
+		WHERE deleted_at IS NULL
 		GROUP BY country
 		ORDER BY country
 	`
@@ -240,6 +746,7 @@ func (r *PostgresBankRepository) GetAvailableFilters(ctx context.Context) (*mode
 	apisQuery := `
 		SELECT api, COUNT(*) as count
 		FROM banks
+		WHERE deleted_at IS NULL
 		GROUP BY api
 		ORDER BY api
 	`
@@ -264,9 +771,10 @@ func (r *PostgresBankRepository) GetAvailableFilters(ctx context.Context) (*mode
 
 	// Get distinct environments
 	environmentsQuery := `
-		SELECT DISTINCT environment
-		FROM bank_environment_configs
-		ORDER BY environment
+		SELECT DISTINCT bec.environment
+		FROM bank_environment_configs bec
+		JOIN banks b ON b.bank_id = bec.bank_id AND b.deleted_at IS NULL
+		ORDER BY bec.environment
 	`
 
 	envRows, err := r.db.Query(ctx, environmentsQuery)
@@ -291,7 +799,7 @@ func (r *PostgresBankRepository) GetAvailableFilters(ctx context.Context) (*mode
 	bankGroupsQuery := `
 		SELECT bg.group_id, bg.name, COUNT(b.bank_id) as count
 		FROM bank_groups bg
-		LEFT JOIN banks b ON bg.group_id = b.bank_group_id
+		LEFT JOIN banks b ON bg.group_id = b.bank_group_id AND b.deleted_at IS NULL
 		GROUP BY bg.group_id, bg.name
 		ORDER BY bg.name
 	`
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBankRepository_Interface_Implementation(_ *testing.T) {
+	// Test that PostgresBankRepository implements BankRepository interface
+	var _ BankRepository = (*PostgresBankRepository)(nil)
+}
+
+func TestBuildBankWhereClause_NoFilters(t *testing.T) {
+	whereClause, args := buildBankWhereClause(&BankFilters{Environment: "all"})
+
+	assert.Equal(t, "WHERE b.deleted_at IS NULL", whereClause)
+	assert.Empty(t, args)
+}
+
+func TestBuildBankWhereClause_CombinedFilters(t *testing.T) {
+	filters := &BankFilters{
+		Environment: "production",
+		Name:        "caixa",
+		APIs:        []string{"berlin_group"},
+		Countries:   []string{"ES"},
+	}
+
+	whereClause, args := buildBankWhereClause(filters)
+
+	assert.Contains(t, whereClause, "bec.environment = $1")
+	assert.Contains(t, whereClause, "b.name ILIKE $2")
+	assert.Contains(t, whereClause, "b.api = ANY($3)")
+	assert.Contains(t, whereClause, "b.country = ANY($4)")
+	assert.Equal(t, []any{"production", "%caixa%", []string{"berlin_group"}, []string{"ES"}}, args)
+}
+
+func TestBuildBankWhereClause_ExtendedFilters(t *testing.T) {
+	authTypeChoiceRequired := true
+	hasBIC := false
+	updatedSince := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filters := &BankFilters{
+		Countries:              []string{"ES", "PT"},
+		APIs:                   []string{"berlin_group", "stet"},
+		BankGroupID:            "550e8400-e29b-41d4-a716-446655440000",
+		AuthTypeChoiceRequired: &authTypeChoiceRequired,
+		HasBIC:                 &hasBIC,
+		UpdatedSince:           &updatedSince,
+	}
+
+	whereClause, args := buildBankWhereClause(filters)
+
+	assert.Equal(t, "WHERE b.deleted_at IS NULL AND b.api = ANY($1) AND b.country = ANY($2) AND b.bank_group_id = $3::uuid"+
+		" AND b.auth_type_choice_required = $4 AND COALESCE(b.bic, '') = '' AND b.updated_at >= $5", whereClause)
+	assert.Equal(t, []any{
+		[]string{"berlin_group", "stet"},
+		[]string{"ES", "PT"},
+		"550e8400-e29b-41d4-a716-446655440000",
+		true,
+		updatedSince,
+	}, args)
+}
+
+func TestBuildBankWhereClause_EnvironmentState(t *testing.T) {
+	enabled := true
+	blocked := false
+	instant := true
+
+	t.Run("specific environment", func(t *testing.T) {
+		filters := &BankFilters{
+			Environment: "production",
+			EnvironmentState: EnvironmentStateFilters{
+				Enabled:                 &enabled,
+				Blocked:                 &blocked,
+				SupportsInstantPayments: &instant,
+			},
+		}
+
+		whereClause, args := buildBankWhereClause(filters)
+
+		assert.Equal(t, "WHERE b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM bank_environment_configs bec WHERE bec.bank_id = b.bank_id"+
+			" AND bec.environment = $1 AND bec.enabled = $2 AND bec.blocked = $3 AND bec.supports_instant_payments = $4)", whereClause)
+		assert.Equal(t, []any{"production", true, false, true}, args)
+	})
+
+	t.Run("any environment", func(t *testing.T) {
+		filters := &BankFilters{
+			Environment:      "all",
+			Name:             "caixa",
+			EnvironmentState: EnvironmentStateFilters{Blocked: &blocked},
+		}
+
+		whereClause, args := buildBankWhereClause(filters)
+
+		assert.Equal(t, "WHERE b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM bank_environment_configs bec WHERE bec.bank_id = b.bank_id"+
+			" AND bec.blocked = $1) AND b.name ILIKE $2", whereClause)
+		assert.Equal(t, []any{false, "%caixa%"}, args)
+	})
+}
+
+func TestBuildBankWhereClause_EffectiveStatus(t *testing.T) {
+	active := []ActiveMaintenance{
+		{BankID: "BES0049", Environment: "production", Impact: "blocked"},
+		{BankID: "BFR0001", Environment: "production", Impact: "degraded"},
+		{BankID: "BIT0001", Environment: "production", Impact: "degraded"},
+	}
+
+	t.Run("degraded", func(t *testing.T) {
+		filters := &BankFilters{
+			Environment:      "production",
+			EnvironmentState: EnvironmentStateFilters{EffectiveStatus: "degraded", ActiveMaintenance: active},
+		}
+
+		whereClause, args := buildBankWhereClause(filters)
+
+		assert.Equal(t, "WHERE b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM bank_environment_configs bec WHERE bec.bank_id = b.bank_id"+
+			" AND bec.environment = $1 AND NOT (NOT bec.enabled OR bec.blocked OR EXISTS (SELECT 1 FROM unnest($2::text[], $3::text[])"+
+			" AS m(bank_id, environment) WHERE m.bank_id = bec.bank_id AND m.environment = bec.environment::text))"+
+			" AND (bec.risky OR EXISTS (SELECT 1 FROM unnest($4::text[], $5::text[]) AS m(bank_id, environment)"+
+			" WHERE m.bank_id = bec.bank_id AND m.environment = bec.environment::text)))", whereClause)
+		assert.Equal(t, []any{
+			"production",
+			[]string{"BES0049"}, []string{"production"},
+			[]string{"BFR0001", "BIT0001"}, []string{"production", "production"},
+		}, args)
+	})
+
+	t.Run("available without maintenance", func(t *testing.T) {
+		filters := &BankFilters{
+			Environment:      "all",
+			EnvironmentState: EnvironmentStateFilters{EffectiveStatus: "available"},
+		}
+
+		whereClause, args := buildBankWhereClause(filters)
+
+		assert.Equal(t, "WHERE b.deleted_at IS NULL AND EXISTS (SELECT 1 FROM bank_environment_configs bec WHERE bec.bank_id = b.bank_id"+
+			" AND NOT (NOT bec.enabled OR bec.blocked OR FALSE) AND NOT (bec.risky OR FALSE))", whereClause)
+		assert.Empty(t, args)
+	})
+}
+
+func TestBuildBankWhereClause_Search(t *testing.T) {
+	whereClause, args := buildBankWhereClause(&BankFilters{Countries: []string{"ES"}, Query: "Credito"})
+
+	assert.Contains(t, whereClause, "b.search_vector @@ websearch_to_tsquery('simple', immutable_unaccent($2))")
+	assert.Contains(t, whereClause, "lower(immutable_unaccent($2)) <% lower(immutable_unaccent(b.name))")
+	assert.Equal(t, []any{[]string{"ES"}, "Credito"}, args)
+}
+
+func TestBuildBankOrderClause(t *testing.T) {
+	t.Run("default order", func(t *testing.T) {
+		orderClause, args := buildBankOrderClause(&BankFilters{}, []any{"ES"})
+
+		assert.Equal(t, "ORDER BY b.name, b.bank_id", orderClause)
+		assert.Equal(t, []any{"ES"}, args)
+	})
+
+	t.Run("search ranks by relevance", func(t *testing.T) {
+		orderClause, args := buildBankOrderClause(&BankFilters{Query: "caixa"}, []any{"ES", "caixa"})
+
+		assert.Contains(t, orderClause, "ts_rank(b.search_vector, websearch_to_tsquery('simple', immutable_unaccent($3)))")
+		assert.Contains(t, orderClause, "DESC, b.name, b.bank_id")
+		assert.Equal(t, []any{"ES", "caixa", "caixa"}, args)
+	})
+}
+
+func TestBuildBankOrderClause_ExplicitSort(t *testing.T) {
+	filters := &BankFilters{
+		Query: "caixa",
+		Sort:  []SortField{{Field: "country"}, {Field: "updated_at", Descending: true}},
+	}
+
+	orderClause, args := buildBankOrderClause(filters, []any{"caixa"})
+
+	assert.Equal(t, "ORDER BY b.country ASC NULLS LAST, b.updated_at DESC NULLS LAST, b.name, b.bank_id", orderClause)
+	assert.Equal(t, []any{"caixa"}, args)
+}
+
+func TestBuildSortClause_SkipsRepeatedTieBreakers(t *testing.T) {
+	orderClause := buildSortClause([]SortField{{Field: "name", Descending: true}}, bankGroupSortColumns, "bg.name", "bg.group_id")
+
+	assert.Equal(t, "ORDER BY bg.name DESC NULLS LAST, bg.group_id", orderClause)
+}
+
+func TestValidateSort(t *testing.T) {
+	assert.NoError(t, ValidateBankSort([]SortField{{Field: "country"}, {Field: "created_at"}}))
+	assert.NoError(t, ValidateBankGroupSort(nil))
+
+	err := ValidateBankSort([]SortField{{Field: "password"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sort field: password")
+
+	err = ValidateBankGroupSort([]SortField{{Field: "country"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "allowed: created_at, group_id, name, updated_at")
+}
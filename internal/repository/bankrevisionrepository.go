@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// PostgresBankRevisionRepository implements BankRevisionRepository interface
+type PostgresBankRevisionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresBankRevisionRepository creates a new PostgresBankRevisionRepository instance
+func NewPostgresBankRevisionRepository(db *pgxpool.Pool) *PostgresBankRevisionRepository {
+	return &PostgresBankRevisionRepository{db: db}
+}
+
+// GetBankRevision returns a revision of a bank with the environment configs it had at that revision
+func (r *PostgresBankRevisionRepository) GetBankRevision(ctx context.Context, bankID string, revision int64) (*models.BankRevision, error) {
+	query := `
+		SELECT snapshot, created_at
+		FROM bank_revisions
+		WHERE bank_id = $1 AND revision = $2
+	`
+
+	result := &models.BankRevision{BankID: bankID, Revision: revision}
+	var snapshot []byte
+	if err := r.db.QueryRow(ctx, query, bankID, revision).Scan(&snapshot, &result.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("revision %d of bank '%s' not found", revision, bankID)
+		}
+		return nil, fmt.Errorf("failed to get bank revision: %w", err)
+	}
+
+	if err := json.Unmarshal(snapshot, &result.Bank); err != nil {
+		return nil, fmt.Errorf("failed to decode bank revision: %w", err)
+	}
+
+	return result, nil
+}
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/wukong0111/go-banks/internal/models"
@@ -19,29 +23,28 @@ func NewPostgresBankWriter(db *pgxpool.Pool) *PostgresBankWriter {
 }
 
 func (w *PostgresBankWriter) CreateBank(ctx context.Context, bank *models.Bank) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	if bank.BankID == "" {
 		bank.BankID = uuid.New().String()
 	}
 
-	query := `
-		INSERT INTO banks (
-			bank_id, name, bank_codes, bic, real_name, api, api_version,
-			aspsp, product_code, country, bank_group_id, logo_url,
-			documentation, keywords, attribute, auth_type_choice_required
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-		)
-	`
+	if err = insertBankRow(ctx, tx, bank); err != nil {
+		return err
+	}
 
-	_, err := w.db.Exec(ctx, query,
-		bank.BankID, bank.Name, bank.BankCodes, bank.BIC, bank.RealName,
-		bank.API, bank.APIVersion, bank.ASPSP, bank.ProductCode, bank.Country,
-		bank.BankGroupID, bank.LogoURL, bank.Documentation, bank.Keywords,
-		bank.Attribute, bank.AuthTypeChoiceRequired,
-	)
+	if err = auditBank(ctx, tx, bank.BankID, models.AuditOperationCreate, nil); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to create bank: %w", err)
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -60,25 +63,90 @@ func (w *PostgresBankWriter) CreateBankWithEnvironments(ctx context.Context, ban
 		bank.BankID = uuid.New().String()
 	}
 
-	bankQuery := `
-		INSERT INTO banks (
-			bank_id, name, bank_codes, bic, real_name, api, api_version,
-			aspsp, product_code, country, bank_group_id, logo_url,
-			documentation, keywords, attribute, auth_type_choice_required
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-		)
-	`
+	if err = insertBankRow(ctx, tx, bank); err != nil {
+		return err
+	}
 
-	_, err = tx.Exec(ctx, bankQuery,
-		bank.BankID, bank.Name, bank.BankCodes, bank.BIC, bank.RealName,
-		bank.API, bank.APIVersion, bank.ASPSP, bank.ProductCode, bank.Country,
-		bank.BankGroupID, bank.LogoURL, bank.Documentation, bank.Keywords,
-		bank.Attribute, bank.AuthTypeChoiceRequired,
-	)
+	for _, config := range configs {
+		config.BankID = bank.BankID
+		if err = insertEnvironmentConfigRow(ctx, tx, config); err != nil {
+			return fmt.Errorf("failed to create environment config for %s: %w", config.Environment, err)
+		}
+	}
+
+	if err = auditBank(ctx, tx, bank.BankID, models.AuditOperationCreate, nil); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
 
+// UpdateBank writes a bank read at bank.Version and sets the new version and updated_at on it
+func (w *PostgresBankWriter) UpdateBank(ctx context.Context, bank *models.Bank) error {
+	tx, err := w.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create bank: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, err := snapshotBank(ctx, tx, bank.BankID)
+	if err != nil {
+		return err
+	}
+
+	if err := updateBankRow(ctx, tx, bank); err != nil {
+		return err
+	}
+
+	if err := auditBank(ctx, tx, bank.BankID, models.AuditOperationUpdate, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateBankWithEnvironments writes a bank read at bank.Version and replaces its environment configs.
+// Configs of the listed environments are updated in place, so that they keep their versions, and the
+// others are deleted.
+func (w *PostgresBankWriter) UpdateBankWithEnvironments(ctx context.Context, bank *models.Bank, configs []*models.BankEnvironmentConfig) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// The snapshot locks the bank row for the rest of the transaction
+	before, err := snapshotBank(ctx, tx, bank.BankID)
+	if err != nil {
+		return err
+	}
+
+	if err := updateBankRow(ctx, tx, bank); err != nil {
+		return err
+	}
+
+	environments := make([]string, 0, len(configs))
+	for _, config := range configs {
+		environments = append(environments, string(config.Environment))
+	}
+	_, err = tx.Exec(ctx,
+		"DELETE FROM bank_environment_configs WHERE bank_id = $1 AND NOT (environment::text = ANY($2))",
+		bank.BankID, environments,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete existing environment configs: %w", err)
 	}
 
 	configQuery := `
@@ -91,11 +159,13 @@ func (w *PostgresBankWriter) CreateBankWithEnvironments(ctx context.Context, ban
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 		)
+		ON CONFLICT (bank_id, environment) DO UPDATE SET ` + environmentConfigUpsertColumns + `
+		RETURNING version, created_at, updated_at
 	`
 
 	for _, config := range configs {
 		config.BankID = bank.BankID
-		_, err = tx.Exec(ctx, configQuery,
+		err = tx.QueryRow(ctx, configQuery,
 			config.BankID, config.Environment, config.Enabled, config.Blocked,
 			config.BlockedText, config.Risky, config.RiskyMessage,
 			config.SupportsInstantPayments, config.InstantPaymentsActivated,
@@ -103,13 +173,17 @@ func (w *PostgresBankWriter) CreateBankWithEnvironments(ctx context.Context, ban
 			config.OkStatusCodesInstantPayment, config.OkStatusCodesPeriodicPayment,
 			config.EnabledPeriodicPayment, config.FrequencyPeriodicPayment,
 			config.ConfigPeriodicPayment, config.AppAuthSetupRequired,
-		)
+		).Scan(&config.Version, &config.CreatedAt, &config.UpdatedAt)
 
 		if err != nil {
-			return fmt.Errorf("failed to create environment config for %s: %w", config.Environment, err)
+			return fmt.Errorf("failed to write environment config for %s: %w", config.Environment, err)
 		}
 	}
 
+	if err = auditBank(ctx, tx, bank.BankID, models.AuditOperationUpdate, before); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -117,37 +191,109 @@ func (w *PostgresBankWriter) CreateBankWithEnvironments(ctx context.Context, ban
 	return nil
 }
 
-func (w *PostgresBankWriter) UpdateBank(ctx context.Context, bank *models.Bank) error {
+// environmentConfigUpsertColumns is the SET list of an environment config upsert. It overwrites every
+// column with the inserted values and moves the config to its next version.
+const environmentConfigUpsertColumns = `
+			enabled = EXCLUDED.enabled, blocked = EXCLUDED.blocked, blocked_text = EXCLUDED.blocked_text,
+			risky = EXCLUDED.risky, risky_message = EXCLUDED.risky_message,
+			supports_instant_payments = EXCLUDED.supports_instant_payments,
+			instant_payments_activated = EXCLUDED.instant_payments_activated,
+			instant_payments_limit = EXCLUDED.instant_payments_limit,
+			ok_status_codes_simple_payment = EXCLUDED.ok_status_codes_simple_payment,
+			ok_status_codes_instant_payment = EXCLUDED.ok_status_codes_instant_payment,
+			ok_status_codes_periodic_payment = EXCLUDED.ok_status_codes_periodic_payment,
+			enabled_periodic_payment = EXCLUDED.enabled_periodic_payment,
+			frequency_periodic_payment = EXCLUDED.frequency_periodic_payment,
+			config_periodic_payment = EXCLUDED.config_periodic_payment,
+			app_auth_setup_required = EXCLUDED.app_auth_setup_required,
+			version = bank_environment_configs.version + 1,
+			updated_at = CURRENT_TIMESTAMP`
+
+// updateBankRow writes the columns of a bank only if its stored version is still bank.Version, and
+// sets the new version and updated_at on bank
+func updateBankRow(ctx context.Context, db rowQuerier, bank *models.Bank) error {
 	query := `
 		UPDATE banks SET
 			name = $2, bank_codes = $3, bic = $4, real_name = $5, api = $6,
 			api_version = $7, aspsp = $8, product_code = $9, country = $10,
 			bank_group_id = $11, logo_url = $12, documentation = $13,
 			keywords = $14, attribute = $15, auth_type_choice_required = $16,
-			updated_at = CURRENT_TIMESTAMP
-		WHERE bank_id = $1
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE bank_id = $1 AND deleted_at IS NULL AND version = $17
+		RETURNING version, updated_at
 	`
 
-	result, err := w.db.Exec(ctx, query,
+	err := db.QueryRow(ctx, query,
 		bank.BankID, bank.Name, bank.BankCodes, bank.BIC, bank.RealName,
 		bank.API, bank.APIVersion, bank.ASPSP, bank.ProductCode, bank.Country,
 		bank.BankGroupID, bank.LogoURL, bank.Documentation, bank.Keywords,
-		bank.Attribute, bank.AuthTypeChoiceRequired,
-	)
+		bank.Attribute, bank.AuthTypeChoiceRequired, bank.Version,
+	).Scan(&bank.Version, &bank.UpdatedAt)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return bankUpdateMissError(ctx, db, bank.BankID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update bank: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("bank with ID '%s' not found", bank.BankID)
+	return nil
+}
+
+// ImportBanks applies the operations of a bulk import and returns the error of each one, in order.
+// Every operation runs in its own savepoint so that a failure does not hide the outcome of the rest.
+// Atomic and dry-run imports share a single transaction, committed only when it is not a dry run
+// and no operation failed; otherwise each operation is committed on its own.
+// The second return value reports failures of the import as a whole, such as a failed commit.
+func (w *PostgresBankWriter) ImportBanks(ctx context.Context, operations []*BankImportOperation, options BankImportOptions) ([]error, error) {
+	results := make([]error, len(operations))
+
+	if !options.Atomic && !options.DryRun {
+		for i, operation := range operations {
+			results[i] = w.importInTransaction(ctx, operation)
+		}
+		return results, nil
+	}
+
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
 
-	return nil
+	failed := false
+	for i, operation := range operations {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		if err := applyImportOperation(ctx, savepoint, operation); err != nil {
+			_ = savepoint.Rollback(ctx)
+			results[i] = err
+			failed = true
+			continue
+		}
+
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if options.DryRun || failed {
+		return results, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return results, nil
 }
 
-func (w *PostgresBankWriter) UpdateBankWithEnvironments(ctx context.Context, bank *models.Bank, configs []*models.BankEnvironmentConfig) error {
+func (w *PostgresBankWriter) importInTransaction(ctx context.Context, operation *BankImportOperation) error {
 	tx, err := w.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -156,40 +302,64 @@ func (w *PostgresBankWriter) UpdateBankWithEnvironments(ctx context.Context, ban
 		_ = tx.Rollback(ctx)
 	}()
 
-	// Update bank table
-	bankQuery := `
-		UPDATE banks SET
-			name = $2, bank_codes = $3, bic = $4, real_name = $5, api = $6,
-			api_version = $7, aspsp = $8, product_code = $9, country = $10,
-			bank_group_id = $11, logo_url = $12, documentation = $13,
-			keywords = $14, attribute = $15, auth_type_choice_required = $16,
-			updated_at = CURRENT_TIMESTAMP
-		WHERE bank_id = $1
-	`
+	if err := applyImportOperation(ctx, tx, operation); err != nil {
+		return err
+	}
 
-	result, err := tx.Exec(ctx, bankQuery,
-		bank.BankID, bank.Name, bank.BankCodes, bank.BIC, bank.RealName,
-		bank.API, bank.APIVersion, bank.ASPSP, bank.ProductCode, bank.Country,
-		bank.BankGroupID, bank.LogoURL, bank.Documentation, bank.Keywords,
-		bank.Attribute, bank.AuthTypeChoiceRequired,
-	)
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
+	return nil
+}
+
+// applyImportOperation writes a bank and upserts its configs, so that environments listed in the
+// operation are replaced while the others are kept unless ReplaceConfigs is set. Optional flags
+// missing from the file fall back to the column defaults.
+func applyImportOperation(ctx context.Context, tx pgx.Tx, operation *BankImportOperation) error {
+	bank := operation.Bank
+
+	before, err := snapshotBank(ctx, tx, bank.BankID)
 	if err != nil {
-		return fmt.Errorf("failed to update bank: %w", err)
+		return err
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("bank with ID '%s' not found", bank.BankID)
+	if operation.Create {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO banks (
+				bank_id, name, bank_codes, bic, real_name, api, api_version,
+				aspsp, product_code, country, bank_group_id, logo_url,
+				documentation, keywords, attribute, auth_type_choice_required
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+			)
+		`,
+			bank.BankID, bank.Name, bank.BankCodes, bank.BIC, bank.RealName,
+			bank.API, bank.APIVersion, bank.ASPSP, bank.ProductCode, bank.Country,
+			bank.BankGroupID, bank.LogoURL, bank.Documentation, bank.Keywords,
+			bank.Attribute, bank.AuthTypeChoiceRequired,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create bank: %w", err)
+		}
+	} else if err := updateBankRow(ctx, tx, bank); err != nil {
+		return err
 	}
 
-	// Delete existing environment configs for this bank
-	deleteQuery := "DELETE FROM bank_environment_configs WHERE bank_id = $1"
-	if _, err = tx.Exec(ctx, deleteQuery, bank.BankID); err != nil {
-		return fmt.Errorf("failed to delete existing environment configs: %w", err)
+	if operation.ReplaceConfigs {
+		environments := make([]string, 0, len(operation.Configs))
+		for _, config := range operation.Configs {
+			environments = append(environments, string(config.Environment))
+		}
+		_, err := tx.Exec(ctx,
+			"DELETE FROM bank_environment_configs WHERE bank_id = $1 AND NOT (environment::text = ANY($2))",
+			bank.BankID, environments,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete replaced environment configs: %w", err)
+		}
 	}
 
-	// Insert new environment configs
 	configQuery := `
 		INSERT INTO bank_environment_configs (
 			bank_id, environment, enabled, blocked, blocked_text, risky, risky_message,
@@ -198,13 +368,15 @@ func (w *PostgresBankWriter) UpdateBankWithEnvironments(ctx context.Context, ban
 			ok_status_codes_periodic_payment, enabled_periodic_payment,
 			frequency_periodic_payment, config_periodic_payment, app_auth_setup_required
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+			$1, $2, $3, $4, $5, $6, $7, COALESCE($8, FALSE), COALESCE($9, FALSE), $10,
+			$11, $12, $13, COALESCE($14, FALSE), $15, $16, $17
 		)
+		ON CONFLICT (bank_id, environment) DO UPDATE SET ` + environmentConfigUpsertColumns + `
 	`
 
-	for _, config := range configs {
+	for _, config := range operation.Configs {
 		config.BankID = bank.BankID
-		_, err = tx.Exec(ctx, configQuery,
+		_, err := tx.Exec(ctx, configQuery,
 			config.BankID, config.Environment, config.Enabled, config.Blocked,
 			config.BlockedText, config.Risky, config.RiskyMessage,
 			config.SupportsInstantPayments, config.InstantPaymentsActivated,
@@ -213,15 +385,571 @@ func (w *PostgresBankWriter) UpdateBankWithEnvironments(ctx context.Context, ban
 			config.EnabledPeriodicPayment, config.FrequencyPeriodicPayment,
 			config.ConfigPeriodicPayment, config.AppAuthSetupRequired,
 		)
-
 		if err != nil {
-			return fmt.Errorf("failed to create environment config for %s: %w", config.Environment, err)
+			return fmt.Errorf("failed to write environment config for %s: %w", config.Environment, err)
 		}
 	}
 
-	if err = tx.Commit(ctx); err != nil {
+	operationName := models.AuditOperationUpdate
+	if operation.Create {
+		operationName = models.AuditOperationCreate
+	}
+	return auditBank(ctx, tx, bank.BankID, operationName, before)
+}
+
+// DeleteBank soft-deletes a bank. Its row and environment configs are kept so that it can be restored.
+func (w *PostgresBankWriter) DeleteBank(ctx context.Context, bankID string) (*models.BankDeletion, error) {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, err := snapshotBank(ctx, tx, bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE banks SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE bank_id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at
+	`
+
+	deletion := &models.BankDeletion{BankID: bankID}
+	if err := tx.QueryRow(ctx, query, bankID).Scan(&deletion.DeletedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("bank with ID '%s' not found", bankID)
+		}
+		return nil, fmt.Errorf("failed to delete bank: %w", err)
+	}
+
+	if err := auditBank(ctx, tx, bankID, models.AuditOperationDelete, before); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deletion, nil
+}
+
+// RestoreBank undoes a soft delete. The environment configs of the bank are touched as well so that
+// delta sync clients, which dropped them along with the bank, receive them again.
+func (w *PostgresBankWriter) RestoreBank(ctx context.Context, bankID string) (*models.Bank, error) {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, err := snapshotBank(ctx, tx, bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE banks b SET deleted_at = NULL, version = version + 1
+		WHERE b.bank_id = $1 AND b.deleted_at IS NOT NULL
+		RETURNING ` + bankSelectColumns
+
+	var bank models.Bank
+	if err := scanBank(tx.QueryRow(ctx, query, bankID), &bank); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to restore bank: %w", err)
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM banks WHERE bank_id = $1)", bankID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check bank: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("bank with ID '%s' is not deleted", bankID)
+		}
+		return nil, fmt.Errorf("bank with ID '%s' not found", bankID)
+	}
+
+	if _, err := tx.Exec(ctx, "UPDATE bank_environment_configs SET change_seq = nextval('catalog_change_seq') WHERE bank_id = $1", bankID); err != nil {
+		return nil, fmt.Errorf("failed to restore environment configs: %w", err)
+	}
+
+	if err := auditBank(ctx, tx, bankID, models.AuditOperationRestore, before); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &bank, nil
+}
+
+// PurgeBank permanently removes a bank, deleted or not, together with its environment configs
+func (w *PostgresBankWriter) PurgeBank(ctx context.Context, bankID string) (*models.BankPurge, error) {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, err := snapshotBank(ctx, tx, bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions go with the bank, so that a bank created again with the same ID numbers its own.
+	// The audit trail is kept.
+	if _, err := tx.Exec(ctx, "DELETE FROM bank_revisions WHERE bank_id = $1", bankID); err != nil {
+		return nil, fmt.Errorf("failed to delete bank revisions: %w", err)
+	}
+
+	configs, err := tx.Exec(ctx, "DELETE FROM bank_environment_configs WHERE bank_id = $1", bankID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete environment configs: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, "DELETE FROM banks WHERE bank_id = $1", bankID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge bank: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("bank with ID '%s' not found", bankID)
+	}
+
+	if err := insertAuditEntry(ctx, tx, models.EntityTypeBank, bankID, models.AuditOperationPurge, before, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.BankPurge{
+		BankID:                    bankID,
+		EnvironmentConfigsDeleted: configs.RowsAffected(),
+	}, nil
+}
+
+// writeEnvironmentConfigFields sets some fields of an environment config of a live bank, given as a map
+// from JSON field names to values, and returns the values they replaced. The bank moves to its next
+// version, so that the write gets a revision like any other write of the bank, and is audited as an update.
+func writeEnvironmentConfigFields(ctx context.Context, tx pgx.Tx, bankID string, environment models.EnvironmentType, values map[string]any) (map[string]any, error) {
+	snapshot, err := readBankSnapshot(ctx, tx, bankID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil || snapshot.DeletedAt != nil {
+		return nil, fmt.Errorf("bank with ID '%s' not found", bankID)
+	}
+	config, ok := snapshot.EnvironmentConfigs[string(environment)]
+	if !ok {
+		return nil, fmt.Errorf("environment config '%s' of bank '%s' not found", environment, bankID)
+	}
+
+	before, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bank snapshot: %w", err)
+	}
+
+	fields, err := environmentConfigFields(config)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[string]any, len(values))
+	for field, value := range values {
+		previous[field] = fields[field]
+		fields[field] = value
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode environment config: %w", err)
+	}
+	updated := *config
+	if err := json.Unmarshal(encoded, &updated); err != nil {
+		return nil, fmt.Errorf("invalid environment config values: %w", err)
+	}
+
+	if err := updateEnvironmentConfigRow(ctx, tx, &updated); err != nil {
+		return nil, err
+	}
+	if err := bumpBankVersion(ctx, tx, bankID); err != nil {
+		return nil, err
+	}
+
+	if err := auditBank(ctx, tx, bankID, models.AuditOperationUpdate, before); err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// SaveEnvironmentConfig writes a single environment config of a live bank and leaves its other configs
+// alone. config.Version is the version that was read, or zero for a config that did not exist yet; the
+// write fails with ErrVersionConflict if the stored config has moved on since. Like any other write of
+// the bank, it moves the bank to its next version and is audited as an update. The new version and
+// timestamps are set on config.
+func (w *PostgresBankWriter) SaveEnvironmentConfig(ctx context.Context, config *models.BankEnvironmentConfig) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, exists, err := lockEnvironmentConfig(ctx, tx, config.BankID, config.Environment, config.Version)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		err = updateEnvironmentConfigRow(ctx, tx, config)
+	} else {
+		err = insertEnvironmentConfigRow(ctx, tx, config)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := bumpBankVersion(ctx, tx, config.BankID); err != nil {
+		return err
+	}
+	if err := auditBank(ctx, tx, config.BankID, models.AuditOperationUpdate, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEnvironmentConfig removes a single environment config of a live bank, read at version. The
+// deletion is a write of the bank: it moves the bank to its next version and is audited as an update.
+func (w *PostgresBankWriter) DeleteEnvironmentConfig(ctx context.Context, bankID string, environment models.EnvironmentType, version int64) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	before, exists, err := lockEnvironmentConfig(ctx, tx, bankID, environment, version)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("environment config '%s' of bank '%s' not found", environment, bankID)
+	}
+
+	_, err = tx.Exec(ctx,
+		"DELETE FROM bank_environment_configs WHERE bank_id = $1 AND environment = $2",
+		bankID, environment,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete environment config: %w", err)
+	}
+
+	if err := bumpBankVersion(ctx, tx, bankID); err != nil {
+		return err
+	}
+	if err := auditBank(ctx, tx, bankID, models.AuditOperationUpdate, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
+
+// PromoteEnvironmentConfigs applies promotions of environment config values and returns the outcome
+// of each one, in order. They share a single transaction, committed only when it is not a dry run and
+// no promotion failed, so that a promotion of many banks applies to all of them or to none. Every
+// promotion runs in its own savepoint so that a failure does not hide the outcome of the rest.
+// The second return value reports failures of the promotion as a whole, such as a failed commit.
+func (w *PostgresBankWriter) PromoteEnvironmentConfigs(ctx context.Context, promotions []*EnvironmentPromotion, dryRun bool) ([]EnvironmentPromotionOutcome, error) {
+	outcomes := make([]EnvironmentPromotionOutcome, len(promotions))
+
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	failed := false
+	for i, promotion := range promotions {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		outcome, err := promoteEnvironmentConfig(ctx, savepoint, promotion)
+		if err != nil {
+			_ = savepoint.Rollback(ctx)
+			outcomes[i] = EnvironmentPromotionOutcome{Err: err}
+			failed = true
+			continue
+		}
+		outcomes[i] = *outcome
+
+		if err := savepoint.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if dryRun || failed {
+		return outcomes, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return outcomes, nil
+}
+
+// promoteEnvironmentConfig copies the fields of a promotion from the source config of a live bank onto
+// its target config. A target config that already holds every value is left alone; otherwise the write
+// moves the bank to its next version and is audited as an update.
+func promoteEnvironmentConfig(ctx context.Context, tx pgx.Tx, promotion *EnvironmentPromotion) (*EnvironmentPromotionOutcome, error) {
+	snapshot, err := readBankSnapshot(ctx, tx, promotion.BankID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil || snapshot.DeletedAt != nil {
+		return nil, fmt.Errorf("bank with ID '%s' not found", promotion.BankID)
+	}
+	source, ok := snapshot.EnvironmentConfigs[string(promotion.From)]
+	if !ok {
+		return nil, fmt.Errorf("environment config '%s' of bank '%s' not found", promotion.From, promotion.BankID)
+	}
+
+	// A new target config starts from the column defaults
+	target, exists := snapshot.EnvironmentConfigs[string(promotion.To)]
+	updated := models.BankEnvironmentConfig{BankID: promotion.BankID, Environment: promotion.To, Enabled: true}
+	if exists {
+		updated = *target
+	}
+	if promotion.Version != nil && updated.Version != *promotion.Version {
+		return nil, fmt.Errorf("%w: environment config '%s' of bank '%s' was modified concurrently",
+			ErrVersionConflict, promotion.To, promotion.BankID)
+	}
+
+	before, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bank snapshot: %w", err)
+	}
+
+	sourceFields, err := environmentConfigFields(source)
+	if err != nil {
+		return nil, err
+	}
+	targetFields, err := environmentConfigFields(&updated)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &EnvironmentPromotionOutcome{Created: !exists, Changes: []models.FieldChange{}}
+	for _, field := range promotion.Fields {
+		value := sourceFields[field]
+		var previous any
+		if exists {
+			previous = targetFields[field]
+			if reflect.DeepEqual(previous, value) {
+				continue
+			}
+		}
+		outcome.Changes = append(outcome.Changes, models.FieldChange{Field: field, Before: previous, After: value})
+		targetFields[field] = value
+	}
+
+	if exists && len(outcome.Changes) == 0 {
+		outcome.Version = updated.Version
+		return outcome, nil
+	}
+
+	encoded, err := json.Marshal(targetFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode environment config: %w", err)
+	}
+	if err := json.Unmarshal(encoded, &updated); err != nil {
+		return nil, fmt.Errorf("invalid environment config values: %w", err)
+	}
+
+	if exists {
+		err = updateEnvironmentConfigRow(ctx, tx, &updated)
+	} else {
+		err = insertEnvironmentConfigRow(ctx, tx, &updated)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bumpBankVersion(ctx, tx, promotion.BankID); err != nil {
+		return nil, err
+	}
+	if err := auditBank(ctx, tx, promotion.BankID, models.AuditOperationUpdate, before); err != nil {
+		return nil, err
+	}
+
+	outcome.Version = updated.Version
+	return outcome, nil
+}
+
+// environmentConfigFields decodes an environment config into a map from JSON field names to values
+func environmentConfigFields(config *models.BankEnvironmentConfig) (map[string]any, error) {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode environment config: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode environment config: %w", err)
+	}
+	return fields, nil
+}
+
+// lockEnvironmentConfig locks a live bank and checks that its config of environment is still at the
+// version that was read, zero standing for a config that does not exist. It returns the snapshot of
+// the bank to audit the write with and whether the config exists.
+func lockEnvironmentConfig(ctx context.Context, tx pgx.Tx, bankID string, environment models.EnvironmentType, version int64) ([]byte, bool, error) {
+	snapshot, err := readBankSnapshot(ctx, tx, bankID)
+	if err != nil {
+		return nil, false, err
+	}
+	if snapshot == nil || snapshot.DeletedAt != nil {
+		return nil, false, fmt.Errorf("bank with ID '%s' not found", bankID)
+	}
+
+	var stored int64
+	config, exists := snapshot.EnvironmentConfigs[string(environment)]
+	if exists {
+		stored = config.Version
+	}
+	if stored != version {
+		return nil, false, fmt.Errorf("%w: environment config '%s' of bank '%s' was modified concurrently",
+			ErrVersionConflict, environment, bankID)
+	}
+
+	before, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode bank snapshot: %w", err)
+	}
+
+	return before, exists, nil
+}
+
+// bumpBankVersion moves a bank to its next version after a write of one of its environment configs
+func bumpBankVersion(ctx context.Context, tx pgx.Tx, bankID string) error {
+	if _, err := tx.Exec(ctx, "UPDATE banks SET version = version + 1 WHERE bank_id = $1", bankID); err != nil {
+		return fmt.Errorf("failed to update bank version: %w", err)
+	}
+	return nil
+}
+
+// insertBankRow creates a bank and sets its version and timestamps on bank
+func insertBankRow(ctx context.Context, tx pgx.Tx, bank *models.Bank) error {
+	query := `
+		INSERT INTO banks (
+			bank_id, name, bank_codes, bic, real_name, api, api_version,
+			aspsp, product_code, country, bank_group_id, logo_url,
+			documentation, keywords, attribute, auth_type_choice_required
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		)
+		RETURNING version, created_at, updated_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		bank.BankID, bank.Name, bank.BankCodes, bank.BIC, bank.RealName,
+		bank.API, bank.APIVersion, bank.ASPSP, bank.ProductCode, bank.Country,
+		bank.BankGroupID, bank.LogoURL, bank.Documentation, bank.Keywords,
+		bank.Attribute, bank.AuthTypeChoiceRequired,
+	).Scan(&bank.Version, &bank.CreatedAt, &bank.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create bank: %w", err)
+	}
+
+	return nil
+}
+
+// insertEnvironmentConfigRow creates an environment config and sets its version and timestamps on config
+func insertEnvironmentConfigRow(ctx context.Context, tx pgx.Tx, config *models.BankEnvironmentConfig) error {
+	query := `
+		INSERT INTO bank_environment_configs (
+			bank_id, environment, enabled, blocked, blocked_text, risky, risky_message,
+			supports_instant_payments, instant_payments_activated, instant_payments_limit,
+			ok_status_codes_simple_payment, ok_status_codes_instant_payment,
+			ok_status_codes_periodic_payment, enabled_periodic_payment,
+			frequency_periodic_payment, config_periodic_payment, app_auth_setup_required
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+		)
+		RETURNING version, created_at, updated_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		config.BankID, config.Environment, config.Enabled, config.Blocked,
+		config.BlockedText, config.Risky, config.RiskyMessage,
+		config.SupportsInstantPayments, config.InstantPaymentsActivated,
+		config.InstantPaymentsLimit, config.OkStatusCodesSimplePayment,
+		config.OkStatusCodesInstantPayment, config.OkStatusCodesPeriodicPayment,
+		config.EnabledPeriodicPayment, config.FrequencyPeriodicPayment,
+		config.ConfigPeriodicPayment, config.AppAuthSetupRequired,
+	).Scan(&config.Version, &config.CreatedAt, &config.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create environment config: %w", err)
+	}
+
+	return nil
+}
+
+// updateEnvironmentConfigRow writes every column of an existing environment config, moves it to its
+// next version and sets the new version and updated_at on config
+func updateEnvironmentConfigRow(ctx context.Context, tx pgx.Tx, config *models.BankEnvironmentConfig) error {
+	query := `
+		UPDATE bank_environment_configs SET
+			enabled = $3, blocked = $4, blocked_text = $5, risky = $6, risky_message = $7,
+			supports_instant_payments = $8, instant_payments_activated = $9, instant_payments_limit = $10,
+			ok_status_codes_simple_payment = $11, ok_status_codes_instant_payment = $12,
+			ok_status_codes_periodic_payment = $13, enabled_periodic_payment = $14,
+			frequency_periodic_payment = $15, config_periodic_payment = $16, app_auth_setup_required = $17,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE bank_id = $1 AND environment = $2
+		RETURNING version, updated_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		config.BankID, config.Environment, config.Enabled, config.Blocked,
+		config.BlockedText, config.Risky, config.RiskyMessage,
+		config.SupportsInstantPayments, config.InstantPaymentsActivated,
+		config.InstantPaymentsLimit, config.OkStatusCodesSimplePayment,
+		config.OkStatusCodesInstantPayment, config.OkStatusCodesPeriodicPayment,
+		config.EnabledPeriodicPayment, config.FrequencyPeriodicPayment,
+		config.ConfigPeriodicPayment, config.AppAuthSetupRequired,
+	).Scan(&config.Version, &config.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("environment config '%s' of bank '%s' not found", config.Environment, config.BankID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update environment config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// PostgresChangeRepository implements ChangeRepository on top of the catalog change sequence and the
+// transaction IDs recorded next to it
+type PostgresChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresChangeRepository creates a new PostgresChangeRepository instance
+func NewPostgresChangeRepository(db *pgxpool.Pool) *PostgresChangeRepository {
+	return &PostgresChangeRepository{db: db}
+}
+
+// catalogChange is a single changed entity positioned in the catalog changes
+type catalogChange struct {
+	position ChangePosition
+	apply    func(changes *models.CatalogChanges)
+}
+
+// changeReader reads up to limit changes after since, in position order
+type changeReader func(ctx context.Context, tx pgx.Tx, since ChangePosition, limit int) ([]catalogChange, error)
+
+// GetChangesSince returns up to limit changes positioned after since, in position order.
+// Every table is read with its own limited query and the results are merged, so a page never
+// skips a change that sorts before the last one returned. The reads share one snapshot.
+func (r *PostgresChangeRepository) GetChangesSince(ctx context.Context, since ChangePosition, limit int) (*models.CatalogChanges, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Nothing is written, so the transaction is never committed
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// The first query takes the snapshot, whose xmin is the oldest transaction still in flight
+	var xmin uint64
+	if err := tx.QueryRow(ctx, "SELECT pg_snapshot_xmin(pg_current_snapshot())").Scan(&xmin); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var all []catalogChange
+	readers := []changeReader{
+		bankChanges,
+		environmentConfigChanges,
+		bankGroupChanges,
+		tombstoneChanges,
+	}
+	for _, read := range readers {
+		changes, err := read(ctx, tx, since, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, changes...)
+	}
+
+	return mergeCatalogChanges(all, since, xmin, limit), nil
+}
+
+// mergeCatalogChanges builds a page of up to limit changes, in position order, from changes read after
+// since. Changes of a transaction at or after xmin are held back: a transaction before them may still
+// commit changes that sort earlier, and a token past those would make clients skip them. Since changes
+// are ordered by transaction first, what is held back always comes after what is returned.
+func mergeCatalogChanges(all []catalogChange, since ChangePosition, xmin uint64, limit int) *models.CatalogChanges {
+	slices.SortFunc(all, func(a, b catalogChange) int {
+		return a.position.Compare(b.position)
+	})
+
+	heldBack := slices.IndexFunc(all, func(change catalogChange) bool {
+		return change.position.XID >= xmin
+	})
+	if heldBack >= 0 {
+		all = all[:heldBack]
+	}
+
+	result := &models.CatalogChanges{
+		Banks:              []models.Bank{},
+		EnvironmentConfigs: []models.BankEnvironmentConfig{},
+		BankGroups:         []models.BankGroup{},
+		Deleted:            []models.Tombstone{},
+	}
+
+	if len(all) > limit {
+		all = all[:limit]
+		result.HasMore = true
+	}
+
+	last := since
+	for _, change := range all {
+		change.apply(result)
+		last = change.position
+	}
+	result.NextToken = EncodeChangeToken(last)
+
+	return result
+}
+
+// bankChanges reads changed banks. Soft-deleted banks are reported as tombstones.
+func bankChanges(ctx context.Context, tx pgx.Tx, since ChangePosition, limit int) ([]catalogChange, error) {
+	query := `
+		SELECT ` + bankSelectColumns + `, b.change_xid, b.change_seq, b.deleted_at
+		FROM banks b
+		WHERE (b.change_xid, b.change_seq) > ($1::xid8, $2)
+		ORDER BY b.change_xid, b.change_seq
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(ctx, query, since.XID, since.Seq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []catalogChange
+	for rows.Next() {
+		var bank models.Bank
+		var position ChangePosition
+		var deletedAt *time.Time
+		if err := rows.Scan(append(bankScanTargets(&bank), &position.XID, &position.Seq, &deletedAt)...); err != nil {
+			return nil, fmt.Errorf("failed to scan bank change: %w", err)
+		}
+		if deletedAt != nil {
+			tombstone := models.Tombstone{
+				EntityType: models.EntityTypeBank,
+				EntityID:   bank.BankID,
+				BankID:     &bank.BankID,
+				DeletedAt:  *deletedAt,
+			}
+			changes = append(changes, catalogChange{position: position, apply: func(c *models.CatalogChanges) {
+				c.Deleted = append(c.Deleted, tombstone)
+			}})
+			continue
+		}
+		changes = append(changes, catalogChange{position: position, apply: func(c *models.CatalogChanges) {
+			c.Banks = append(c.Banks, bank)
+		}})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bank change rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+// environmentConfigChanges reads changed environment configs, skipping those of soft-deleted banks.
+// Restoring a bank touches its configs so that they are sent again.
+func environmentConfigChanges(ctx context.Context, tx pgx.Tx, since ChangePosition, limit int) ([]catalogChange, error) {
+	query := `
+		SELECT ` + environmentConfigSelectColumns + `, bec.change_xid, bec.change_seq
+		FROM bank_environment_configs bec
+		JOIN banks b ON b.bank_id = bec.bank_id AND b.deleted_at IS NULL
+		WHERE (bec.change_xid, bec.change_seq) > ($1::xid8, $2)
+		ORDER BY bec.change_xid, bec.change_seq
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(ctx, query, since.XID, since.Seq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query environment config changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []catalogChange
+	for rows.Next() {
+		var config models.BankEnvironmentConfig
+		var position ChangePosition
+		if err := rows.Scan(append(environmentConfigScanTargets(&config), &position.XID, &position.Seq)...); err != nil {
+			return nil, fmt.Errorf("failed to scan environment config change: %w", err)
+		}
+		changes = append(changes, catalogChange{position: position, apply: func(c *models.CatalogChanges) {
+			c.EnvironmentConfigs = append(c.EnvironmentConfigs, config)
+		}})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environment config change rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+func bankGroupChanges(ctx context.Context, tx pgx.Tx, since ChangePosition, limit int) ([]catalogChange, error) {
+	query := `
+		SELECT ` + bankGroupSelectColumns + `, bg.change_xid, bg.change_seq
+		FROM bank_groups bg
+		WHERE (bg.change_xid, bg.change_seq) > ($1::xid8, $2)
+		ORDER BY bg.change_xid, bg.change_seq
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(ctx, query, since.XID, since.Seq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bank group changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []catalogChange
+	for rows.Next() {
+		var group models.BankGroup
+		var position ChangePosition
+		if err := rows.Scan(append(bankGroupScanTargets(&group), &position.XID, &position.Seq)...); err != nil {
+			return nil, fmt.Errorf("failed to scan bank group change: %w", err)
+		}
+		changes = append(changes, catalogChange{position: position, apply: func(c *models.CatalogChanges) {
+			c.BankGroups = append(c.BankGroups, group)
+		}})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bank group change rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+// tombstoneChanges reads deletions, skipping those superseded by a later re-creation of the same
+// entity (e.g. environment configs replaced by UpdateBankWithEnvironments)
+func tombstoneChanges(ctx context.Context, tx pgx.Tx, since ChangePosition, limit int) ([]catalogChange, error) {
+	query := `
+		SELECT t.change_xid, t.change_seq, t.entity_type, t.entity_id, t.bank_id, t.environment::text, t.deleted_at
+		FROM catalog_tombstones t
+		WHERE (t.change_xid, t.change_seq) > ($1::xid8, $2)
+			AND NOT EXISTS (SELECT 1 FROM banks b
+				WHERE t.entity_type = 'bank' AND b.bank_id = t.entity_id
+					AND (b.change_xid, b.change_seq) > (t.change_xid, t.change_seq))
+			AND NOT EXISTS (SELECT 1 FROM bank_environment_configs bec
+				WHERE t.entity_type = 'environment_config' AND bec.bank_id = t.bank_id
+					AND bec.environment = t.environment AND (bec.change_xid, bec.change_seq) > (t.change_xid, t.change_seq))
+			AND NOT EXISTS (SELECT 1 FROM bank_groups bg
+				WHERE t.entity_type = 'bank_group' AND bg.group_id::text = t.entity_id
+					AND (bg.change_xid, bg.change_seq) > (t.change_xid, t.change_seq))
+		ORDER BY t.change_xid, t.change_seq
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(ctx, query, since.XID, since.Seq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []catalogChange
+	for rows.Next() {
+		var tombstone models.Tombstone
+		var position ChangePosition
+		if err := rows.Scan(
+			&position.XID, &position.Seq, &tombstone.EntityType, &tombstone.EntityID, &tombstone.BankID,
+			&tombstone.Environment, &tombstone.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tombstone: %w", err)
+		}
+		changes = append(changes, catalogChange{position: position, apply: func(c *models.CatalogChanges) {
+			c.Deleted = append(c.Deleted, tombstone)
+		}})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tombstone rows: %w", err)
+	}
+
+	return changes, nil
+}
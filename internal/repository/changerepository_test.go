@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+func TestChangeRepository_Interface_Implementation(_ *testing.T) {
+	// Test that PostgresChangeRepository implements ChangeRepository interface
+	var _ ChangeRepository = (*PostgresChangeRepository)(nil)
+}
+
+// bankChange is a change of a bank written by transaction xid with change sequence seq
+func bankChange(bankID string, xid uint64, seq int64) catalogChange {
+	bank := models.Bank{BankID: bankID}
+	return catalogChange{position: ChangePosition{XID: xid, Seq: seq}, apply: func(c *models.CatalogChanges) {
+		c.Banks = append(c.Banks, bank)
+	}}
+}
+
+func bankIDs(changes *models.CatalogChanges) []string {
+	ids := []string{}
+	for _, bank := range changes.Banks {
+		ids = append(ids, bank.BankID)
+	}
+	return ids
+}
+
+func TestMergeCatalogChanges_InterleavedWriters(t *testing.T) {
+	start := ChangePosition{XID: 90, Seq: 4}
+
+	t.Run("earlier sequence commits last", func(t *testing.T) {
+		// Transaction 100 takes seq 5 and transaction 101 takes seq 6, but 101 commits first.
+		// While 100 is in flight the snapshot only sees 101, and xmin is 100.
+		page := mergeCatalogChanges([]catalogChange{bankChange("BES0081", 101, 6)}, start, 100, 10)
+
+		assert.Empty(t, bankIDs(page))
+		assert.False(t, page.HasMore)
+		assert.Equal(t, EncodeChangeToken(start), page.NextToken)
+
+		// Once 100 commits both changes are returned, the late one included
+		since, err := DecodeChangeToken(page.NextToken)
+		require.NoError(t, err)
+		page = mergeCatalogChanges([]catalogChange{bankChange("BES0081", 101, 6), bankChange("BES0049", 100, 5)}, since, 102, 10)
+
+		assert.Equal(t, []string{"BES0049", "BES0081"}, bankIDs(page))
+		assert.Equal(t, EncodeChangeToken(ChangePosition{XID: 101, Seq: 6}), page.NextToken)
+	})
+
+	t.Run("earlier transaction takes the later sequence", func(t *testing.T) {
+		// Transaction 100 takes seq 6 and commits while transaction 101, which took seq 5, is in flight
+		page := mergeCatalogChanges([]catalogChange{bankChange("BES0081", 100, 6)}, start, 101, 10)
+
+		assert.Equal(t, []string{"BES0081"}, bankIDs(page))
+		since, err := DecodeChangeToken(page.NextToken)
+		require.NoError(t, err)
+
+		// Seq 5 is below the token's sequence but its transaction sorts after it, so it is not skipped
+		late := bankChange("BES0049", 101, 5)
+		assert.Positive(t, late.position.Compare(since))
+		page = mergeCatalogChanges([]catalogChange{late}, since, 102, 10)
+
+		assert.Equal(t, []string{"BES0049"}, bankIDs(page))
+	})
+}
+
+func TestMergeCatalogChanges_Limit(t *testing.T) {
+	changes := []catalogChange{
+		bankChange("BES0003", 0, 3),
+		bankChange("BES0010", 120, 10),
+		bankChange("BES0001", 0, 1),
+		bankChange("BES0011", 130, 11),
+	}
+
+	page := mergeCatalogChanges(changes, ChangePosition{}, 200, 2)
+
+	// Rows written before transactions were recorded come first, in sequence order
+	assert.Equal(t, []string{"BES0001", "BES0003"}, bankIDs(page))
+	assert.True(t, page.HasMore)
+	assert.Equal(t, EncodeChangeToken(ChangePosition{Seq: 3}), page.NextToken)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// BankCursor is the keyset position used by cursor pagination on bank listings
+type BankCursor struct {
+	Name   string `json:"n"`
+	BankID string `json:"id"`
+}
+
+// EncodeBankCursor serializes a cursor into an opaque, URL-safe token
+func EncodeBankCursor(cursor BankCursor) string {
+	// Marshalling a struct of strings cannot fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeBankCursor parses a token produced by EncodeBankCursor
+func DecodeBankCursor(token string) (*BankCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor BankCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	if cursor.BankID == "" {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// ChangePosition is the delta sync watermark: a position in the catalog changes, which are ordered by
+// the transaction that wrote them and then by change sequence. Tokens issued before transactions were
+// recorded decode with XID 0, the transaction of every row written before then.
+type ChangePosition struct {
+	XID uint64 `json:"x,omitempty"`
+	Seq int64  `json:"s"`
+}
+
+// Compare orders two positions, returning -1, 0 or +1
+func (p ChangePosition) Compare(other ChangePosition) int {
+	if c := cmp.Compare(p.XID, other.XID); c != 0 {
+		return c
+	}
+	return cmp.Compare(p.Seq, other.Seq)
+}
+
+// EncodeChangeToken serializes a change position into an opaque, URL-safe token
+func EncodeChangeToken(position ChangePosition) string {
+	// Marshalling a struct of integers cannot fail
+	data, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeChangeToken parses a token produced by EncodeChangeToken
+func DecodeChangeToken(token string) (ChangePosition, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ChangePosition{}, errors.New("invalid since token")
+	}
+
+	var position ChangePosition
+	if err := json.Unmarshal(data, &position); err != nil || position.Seq < 0 {
+		return ChangePosition{}, errors.New("invalid since token")
+	}
+
+	return position, nil
+}
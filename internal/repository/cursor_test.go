@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankCursor_RoundTrip(t *testing.T) {
+	cursor := BankCursor{Name: "Crédito Agrícola", BankID: "BPT0036"}
+
+	token := EncodeBankCursor(cursor)
+	assert.NotContains(t, token, "=")
+
+	decoded, err := DecodeBankCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+}
+
+func TestDecodeBankCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "not base64",
+			token: "%%%",
+		},
+		{
+			name:  "not json",
+			token: base64.RawURLEncoding.EncodeToString([]byte("plain text")),
+		},
+		{
+			name:  "missing bank id",
+			token: base64.RawURLEncoding.EncodeToString([]byte(`{"n":"Bank"}`)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeBankCursor(tt.token)
+			require.Error(t, err)
+			assert.Nil(t, cursor)
+			assert.Contains(t, err.Error(), "invalid cursor")
+		})
+	}
+}
+
+func TestChangeToken_RoundTrip(t *testing.T) {
+	position := ChangePosition{XID: 987654, Seq: 12345}
+	token := EncodeChangeToken(position)
+
+	decoded, err := DecodeChangeToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, position, decoded)
+}
+
+func TestDecodeChangeToken_SequenceOnly(t *testing.T) {
+	// Tokens issued before positions recorded transactions
+	decoded, err := DecodeChangeToken(base64.RawURLEncoding.EncodeToString([]byte(`{"s":42}`)))
+	require.NoError(t, err)
+	assert.Equal(t, ChangePosition{Seq: 42}, decoded)
+}
+
+func TestDecodeChangeToken_Invalid(t *testing.T) {
+	for _, token := range []string{"%%%", base64.RawURLEncoding.EncodeToString([]byte("nope")), EncodeChangeToken(ChangePosition{Seq: -1})} {
+		_, err := DecodeChangeToken(token)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid since token")
+	}
+}
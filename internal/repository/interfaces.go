@@ -2,43 +2,168 @@ package repository
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/wukong0111/go-banks/internal/models"
 )
 
 // BankFilters represents the search criteria for banks (domain boundary)
 type BankFilters struct {
+	Environment            string
+	Name                   string
+	APIs                   []string // Matches any of the given APIs
+	Countries              []string // Matches any of the given ISO country codes
+	BankGroupID            string
+	AuthTypeChoiceRequired *bool
+	HasBIC                 *bool
+	UpdatedSince           *time.Time
+	EnvironmentState       EnvironmentStateFilters
+	Query                  string // Free-text search over name, real_name, bank_codes and keywords
+	Sort                   []SortField
+	Page                   int
+	Limit                  int
+	Cursor                 string // Keyset pagination token; empty means the first page
+	IncludeTotal           bool   // Whether cursor pagination should also count the total
+}
+
+// EnvironmentStateFilters restricts banks by the flags of their environment configs. Every flag that
+// is set must hold on the same config row: the one of BankFilters.Environment, or any row when it is "all".
+type EnvironmentStateFilters struct {
+	Enabled                  *bool
+	Blocked                  *bool
+	Risky                    *bool
+	SupportsInstantPayments  *bool
+	InstantPaymentsActivated *bool
+	EnabledPeriodicPayment   *bool
+	AppAuthSetupRequired     *bool
+	// EffectiveStatus keeps the configs whose effective status, combining their flags with the
+	// maintenance windows in ActiveMaintenance, is available, degraded or blocked
+	EffectiveStatus   string
+	ActiveMaintenance []ActiveMaintenance
+}
+
+// ActiveMaintenance is a bank environment with a maintenance window in progress and its worst impact
+type ActiveMaintenance struct {
+	BankID      string
 	Environment string
-	Name        string
-	API         string
-	Country     string
-	Page        int
-	Limit       int
+	Impact      string
 }
 
 // BankRepository defines the methods that a bank repository must implement
 type BankRepository interface {
 	GetBanks(ctx context.Context, filters *BankFilters) ([]models.Bank, *models.Pagination, error)
+	GetBanksByCursor(ctx context.Context, filters *BankFilters) ([]models.Bank, *models.CursorPagination, error)
+	StreamBanks(ctx context.Context, filters *BankFilters, fn func(bank *models.BankWithEnvironments) error) error
 	GetBankByID(ctx context.Context, bankID string) (*models.Bank, error)
+	GetBanksByIDs(ctx context.Context, bankIDs []string) ([]models.Bank, error)
 	GetBankEnvironmentConfigs(ctx context.Context, bankID string, environment string) (map[string]*models.BankEnvironmentConfig, error)
+	GetEnvironmentConfigsByBankIDs(ctx context.Context, bankIDs []string, environment string) (map[string]map[string]*models.BankEnvironmentConfig, error)
+	GetBanksByBIC(ctx context.Context, bic string) ([]models.Bank, error)
+	GetBanksByCode(ctx context.Context, code, country string) ([]models.Bank, error)
+	GetBanksByCodes(ctx context.Context, codes []string) ([]models.Bank, error)
+	GetBankGroupsByIDs(ctx context.Context, groupIDs []uuid.UUID) (map[uuid.UUID]*models.BankGroup, error)
+	GetMaintenanceWindows(ctx context.Context, bankID, environment string) ([]models.MaintenanceWindow, error)
 	GetAvailableFilters(ctx context.Context) (*models.BankFilters, error)
 }
 
-// BankWriter defines the methods for creating and updating banks
+// BankWriter defines the methods for creating, updating and deleting banks
 type BankWriter interface {
 	CreateBank(ctx context.Context, bank *models.Bank) error
 	CreateBankWithEnvironments(ctx context.Context, bank *models.Bank, configs []*models.BankEnvironmentConfig) error
 	UpdateBank(ctx context.Context, bank *models.Bank) error
 	UpdateBankWithEnvironments(ctx context.Context, bank *models.Bank, configs []*models.BankEnvironmentConfig) error
+	SaveEnvironmentConfig(ctx context.Context, config *models.BankEnvironmentConfig) error
+	DeleteEnvironmentConfig(ctx context.Context, bankID string, environment models.EnvironmentType, version int64) error
+	ImportBanks(ctx context.Context, operations []*BankImportOperation, options BankImportOptions) ([]error, error)
+	PromoteEnvironmentConfigs(ctx context.Context, promotions []*EnvironmentPromotion, dryRun bool) ([]EnvironmentPromotionOutcome, error)
+	DeleteBank(ctx context.Context, bankID string) (*models.BankDeletion, error)
+	RestoreBank(ctx context.Context, bankID string) (*models.Bank, error)
+	PurgeBank(ctx context.Context, bankID string) (*models.BankPurge, error)
+}
+
+// BankImportOperation is the write of one bank, and its environment configs, during a bulk import
+type BankImportOperation struct {
+	Bank           *models.Bank
+	Configs        []*models.BankEnvironmentConfig
+	Create         bool // insert the bank instead of updating it
+	ReplaceConfigs bool // delete the configs of environments missing from Configs
+}
+
+// BankImportOptions controls how the operations of a bulk import are committed
+type BankImportOptions struct {
+	Atomic bool // commit every operation or none of them
+	DryRun bool // run every operation and roll all of them back
+}
+
+// EnvironmentPromotion copies fields of the config of one environment of a bank onto the config of
+// another environment, which is created when the bank has none
+type EnvironmentPromotion struct {
+	BankID  string
+	From    models.EnvironmentType
+	To      models.EnvironmentType
+	Fields  []string // JSON names of the config fields to copy
+	Version *int64   // version the target config must still be at, zero meaning it does not exist; nil skips the check
+}
+
+// EnvironmentPromotionOutcome is what a promotion did, or would do in a dry run, to the target config
+type EnvironmentPromotionOutcome struct {
+	Created bool                 // the target config did not exist
+	Changes []models.FieldChange // copied fields whose value differed, in the order of Fields
+	Version int64                // version of the target config after the promotion
+	Err     error
+}
+
+// BankGroupFilters represents the listing criteria for bank groups
+type BankGroupFilters struct {
+	Name  string // Partial, case-insensitive match on the group name
+	Sort  []SortField
+	Page  int
+	Limit int // Zero lists every group
 }
 
 // BankGroupRepository defines the methods that a bank group repository must implement
 type BankGroupRepository interface {
-	GetBankGroups(ctx context.Context) ([]models.BankGroup, error)
+	GetBankGroups(ctx context.Context, filters *BankGroupFilters) ([]models.BankGroup, error)
+	CountBankGroups(ctx context.Context, filters *BankGroupFilters) (int, error)
+	GetBankGroupByID(ctx context.Context, groupID uuid.UUID) (*models.BankGroup, error)
+	GetBankGroupMemberCounts(ctx context.Context, groupID uuid.UUID) (*models.BankGroupMemberCounts, error)
 }
 
-// BankGroupWriter defines the methods for creating and updating bank groups
+// BankGroupWriter defines the methods for creating, updating and deleting bank groups
 type BankGroupWriter interface {
 	CreateBankGroup(ctx context.Context, bankGroup *models.BankGroup) error
 	UpdateBankGroup(ctx context.Context, bankGroup *models.BankGroup) error
+	DeleteBankGroup(ctx context.Context, groupID uuid.UUID, policy string, reassignTo *uuid.UUID) (*models.BankGroupDeletion, error)
+}
+
+// ChangeRepository defines the methods for reading catalog changes for delta sync
+type ChangeRepository interface {
+	GetChangesSince(ctx context.Context, since ChangePosition, limit int) (*models.CatalogChanges, error)
+}
+
+// AuditRepository defines the methods for reading the audit trail of catalog entities
+type AuditRepository interface {
+	GetAuditEntries(ctx context.Context, entityType, entityID string, page, limit int) ([]models.AuditEntry, int, error)
+}
+
+// BankRevisionRepository defines the methods for reading the numbered revisions of banks
+type BankRevisionRepository interface {
+	GetBankRevision(ctx context.Context, bankID string, revision int64) (*models.BankRevision, error)
+}
+
+// ScheduledChangeRepository defines the methods for storing scheduled environment config changes and
+// applying them when they are due
+type ScheduledChangeRepository interface {
+	CreateScheduledChange(ctx context.Context, change *models.ScheduledChange, check func(open []models.ScheduledChange) error) error
+	GetScheduledChanges(ctx context.Context, bankID string, statuses []string) ([]models.ScheduledChange, error)
+	CancelScheduledChange(ctx context.Context, bankID string, changeID uuid.UUID) (*models.ScheduledChange, error)
+	ApplyNextDueScheduledChange(ctx context.Context, now time.Time) (*models.ScheduledChange, error)
+}
+
+// MaintenanceWindowWriter defines the methods for creating and deleting maintenance windows
+type MaintenanceWindowWriter interface {
+	CreateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error
+	DeleteMaintenanceWindow(ctx context.Context, bankID string, windowID uuid.UUID) error
 }
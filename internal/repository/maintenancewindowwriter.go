@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// maintenanceWindowSelectColumns lists the maintenance_windows columns in the order expected by scanMaintenanceWindow
+const maintenanceWindowSelectColumns = `
+	mw.window_id, mw.bank_id, mw.environment, mw.kind, mw.starts_at, mw.ends_at, mw.schedule,
+	mw.duration_minutes, mw.timezone, mw.impact, mw.reason, mw.created_by, mw.created_at`
+
+// PostgresMaintenanceWindowWriter implements MaintenanceWindowWriter interface
+type PostgresMaintenanceWindowWriter struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresMaintenanceWindowWriter creates a new PostgresMaintenanceWindowWriter instance
+func NewPostgresMaintenanceWindowWriter(db *pgxpool.Pool) *PostgresMaintenanceWindowWriter {
+	return &PostgresMaintenanceWindowWriter{db: db}
+}
+
+// CreateMaintenanceWindow stores a window for an existing environment config of a live bank and sets
+// its creation time
+func (w *PostgresMaintenanceWindowWriter) CreateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	found, err := touchEnvironmentConfig(ctx, tx, window.BankID, window.Environment)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("environment config '%s' of bank '%s' not found", window.Environment, window.BankID)
+	}
+
+	query := `
+		INSERT INTO maintenance_windows (
+			window_id, bank_id, environment, kind, starts_at, ends_at, schedule,
+			duration_minutes, timezone, impact, reason, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		window.WindowID, window.BankID, window.Environment, window.Kind, window.StartsAt, window.EndsAt,
+		window.Schedule, window.DurationMinutes, window.Timezone, window.Impact, window.Reason, window.CreatedBy,
+	).Scan(&window.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMaintenanceWindow removes a window of a bank
+func (w *PostgresMaintenanceWindowWriter) DeleteMaintenanceWindow(ctx context.Context, bankID string, windowID uuid.UUID) error {
+	tx, err := w.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var environment models.EnvironmentType
+	err = tx.QueryRow(ctx, `
+		DELETE FROM maintenance_windows
+		WHERE window_id = $1 AND bank_id = $2
+		RETURNING environment
+	`, windowID, bankID).Scan(&environment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("maintenance window '%s' not found", windowID)
+		}
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	// The config may be gone already, which leaves nothing to touch
+	if _, err := touchEnvironmentConfig(ctx, tx, bankID, environment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// touchEnvironmentConfig moves the updated_at of an environment config of a live bank forward. The
+// effective status of the config depends on its maintenance windows, so adding or removing one must
+// invalidate the validators of conditional requests and show up in the change feed. The version is
+// left alone, as the config itself does not change. It reports whether the config exists.
+func touchEnvironmentConfig(ctx context.Context, tx pgx.Tx, bankID string, environment models.EnvironmentType) (bool, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE bank_environment_configs bec SET updated_at = CURRENT_TIMESTAMP
+		FROM banks b
+		WHERE b.bank_id = bec.bank_id AND b.deleted_at IS NULL
+			AND bec.bank_id = $1 AND bec.environment = $2
+	`, bankID, environment)
+	if err != nil {
+		return false, fmt.Errorf("failed to update environment config: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// scanMaintenanceWindow scans a row selected with maintenanceWindowSelectColumns into window
+func scanMaintenanceWindow(row pgx.Row, window *models.MaintenanceWindow) error {
+	return row.Scan(
+		&window.WindowID, &window.BankID, &window.Environment, &window.Kind, &window.StartsAt, &window.EndsAt,
+		&window.Schedule, &window.DurationMinutes, &window.Timezone, &window.Impact, &window.Reason,
+		&window.CreatedBy, &window.CreatedAt,
+	)
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// scheduledChangeSelectColumns lists the scheduled_config_changes columns in the order expected by scanScheduledChange
+const scheduledChangeSelectColumns = `
+	change_id, bank_id, environment, changes, previous, apply_at, revert_at, reason,
+	status, error, created_by, created_at, applied_at, reverted_at, cancelled_at`
+
+// PostgresScheduledChangeRepository implements ScheduledChangeRepository interface
+type PostgresScheduledChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresScheduledChangeRepository creates a new PostgresScheduledChangeRepository instance
+func NewPostgresScheduledChangeRepository(db *pgxpool.Pool) *PostgresScheduledChangeRepository {
+	return &PostgresScheduledChangeRepository{db: db}
+}
+
+// CreateScheduledChange stores a pending change and sets its creation time, once check accepts the open
+// changes of the same environment config. The config row stays locked from the read of those changes
+// to the commit, so concurrent changes of a config are checked one after the other.
+func (r *PostgresScheduledChangeRepository) CreateScheduledChange(ctx context.Context, change *models.ScheduledChange, check func(open []models.ScheduledChange) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	lockQuery := `
+		SELECT bank_id
+		FROM bank_environment_configs
+		WHERE bank_id = $1 AND environment = $2
+		FOR UPDATE
+	`
+	var lockedBankID string
+	if err := tx.QueryRow(ctx, lockQuery, change.BankID, change.Environment).Scan(&lockedBankID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("environment config '%s' of bank '%s' not found", change.Environment, change.BankID)
+		}
+		return fmt.Errorf("failed to lock environment config: %w", err)
+	}
+
+	// Read after the lock is granted, so the changes committed by the previous holder are included
+	openQuery := `
+		SELECT ` + scheduledChangeSelectColumns + `
+		FROM scheduled_config_changes
+		WHERE bank_id = $1 AND environment = $2 AND status = ANY($3)
+		ORDER BY apply_at, created_at
+	`
+	rows, err := tx.Query(ctx, openQuery, change.BankID, change.Environment,
+		[]string{models.ScheduledChangePending, models.ScheduledChangeActive})
+	if err != nil {
+		return fmt.Errorf("failed to query scheduled changes: %w", err)
+	}
+	open, err := collectScheduledChanges(rows)
+	if err != nil {
+		return err
+	}
+
+	if err := check(open); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO scheduled_config_changes (
+			change_id, bank_id, environment, changes, apply_at, revert_at, reason, status, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(ctx, insertQuery,
+		change.ChangeID, change.BankID, change.Environment, change.Changes,
+		change.ApplyAt, change.RevertAt, change.Reason, change.Status, change.CreatedBy,
+	).Scan(&change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetScheduledChanges returns the scheduled changes of a bank in the given statuses, or in any
+// status when none is given, ordered by the time they apply at
+func (r *PostgresScheduledChangeRepository) GetScheduledChanges(ctx context.Context, bankID string, statuses []string) ([]models.ScheduledChange, error) {
+	query := `
+		SELECT ` + scheduledChangeSelectColumns + `
+		FROM scheduled_config_changes
+		WHERE bank_id = $1
+	`
+	args := []any{bankID}
+	if len(statuses) > 0 {
+		query += " AND status = ANY($2)"
+		args = append(args, statuses)
+	}
+	query += " ORDER BY apply_at, created_at"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled changes: %w", err)
+	}
+
+	return collectScheduledChanges(rows)
+}
+
+// CancelScheduledChange cancels a change that is still open. An active change, whose window has started,
+// is reverted on the spot by the actor of ctx.
+func (r *PostgresScheduledChangeRepository) CancelScheduledChange(ctx context.Context, bankID string, changeID uuid.UUID) (*models.ScheduledChange, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		SELECT ` + scheduledChangeSelectColumns + `
+		FROM scheduled_config_changes
+		WHERE change_id = $1 AND bank_id = $2
+		FOR UPDATE
+	`
+
+	var change models.ScheduledChange
+	if err := scanScheduledChange(tx.QueryRow(ctx, query, changeID, bankID), &change); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("scheduled change '%s' not found", changeID)
+		}
+		return nil, fmt.Errorf("failed to get scheduled change: %w", err)
+	}
+
+	if !change.IsOpen() {
+		return nil, fmt.Errorf("scheduled change '%s' is already %s", changeID, change.Status)
+	}
+
+	update := "status = $2, cancelled_at = CURRENT_TIMESTAMP"
+	if change.Status == models.ScheduledChangeActive {
+		if _, err := writeEnvironmentConfigFields(ctx, tx, change.BankID, change.Environment, change.Previous); err != nil {
+			return nil, fmt.Errorf("failed to revert the change: %w", err)
+		}
+		update += ", reverted_at = CURRENT_TIMESTAMP"
+	}
+
+	if err := r.updateStatus(ctx, tx, &change, update, models.ScheduledChangeCancelled); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &change, nil
+}
+
+// ApplyNextDueScheduledChange applies the pending change or reverts the active change that is due the
+// earliest at now, in a transaction of its own, and returns it; it returns nil when nothing is due.
+// Changes locked by another worker are skipped, so several API instances can run the scheduler.
+// A change that cannot be written, for instance because its environment config was removed, is
+// marked as failed instead of being retried.
+func (r *PostgresScheduledChangeRepository) ApplyNextDueScheduledChange(ctx context.Context, now time.Time) (*models.ScheduledChange, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		SELECT ` + scheduledChangeSelectColumns + `
+		FROM scheduled_config_changes
+		WHERE (status = 'pending' AND apply_at <= $1) OR (status = 'active' AND revert_at <= $1)
+		ORDER BY CASE WHEN status = 'pending' THEN apply_at ELSE revert_at END, created_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var change models.ScheduledChange
+	if err := scanScheduledChange(tx.QueryRow(ctx, query, now), &change); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get due scheduled change: %w", err)
+	}
+
+	// The write is audited on behalf of whoever scheduled it
+	ctx = audit.WithActor(ctx, audit.Actor{
+		Subject:   change.CreatedBy,
+		RequestID: "scheduled-change:" + change.ChangeID.String(),
+	})
+
+	values := change.Changes
+	if change.Status == models.ScheduledChangeActive {
+		values = change.Previous
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	previous, writeErr := writeEnvironmentConfigFields(ctx, savepoint, change.BankID, change.Environment, values)
+	if writeErr != nil {
+		_ = savepoint.Rollback(ctx)
+	} else if err := savepoint.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	switch {
+	case writeErr != nil:
+		message := writeErr.Error()
+		change.Error = &message
+		err = r.updateStatus(ctx, tx, &change, "status = $2, error = $3", models.ScheduledChangeFailed, message)
+	case change.Status == models.ScheduledChangeActive:
+		err = r.updateStatus(ctx, tx, &change, "status = $2, reverted_at = CURRENT_TIMESTAMP", models.ScheduledChangeCompleted)
+	case change.RevertAt != nil:
+		err = r.updateStatus(ctx, tx, &change, "status = $2, previous = $3, applied_at = CURRENT_TIMESTAMP", models.ScheduledChangeActive, previous)
+	default:
+		err = r.updateStatus(ctx, tx, &change, "status = $2, applied_at = CURRENT_TIMESTAMP", models.ScheduledChangeCompleted)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &change, nil
+}
+
+// updateStatus runs an UPDATE of the change with the given SET list, whose $2 onwards are args, and
+// reads the change back
+func (r *PostgresScheduledChangeRepository) updateStatus(ctx context.Context, tx pgx.Tx, change *models.ScheduledChange, set string, args ...any) error {
+	query := `
+		UPDATE scheduled_config_changes SET ` + set + `
+		WHERE change_id = $1
+		RETURNING ` + scheduledChangeSelectColumns
+
+	if err := scanScheduledChange(tx.QueryRow(ctx, query, append([]any{change.ChangeID}, args...)...), change); err != nil {
+		return fmt.Errorf("failed to update scheduled change: %w", err)
+	}
+	return nil
+}
+
+// collectScheduledChanges scans and closes rows selected with scheduledChangeSelectColumns
+func collectScheduledChanges(rows pgx.Rows) ([]models.ScheduledChange, error) {
+	defer rows.Close()
+
+	changes := []models.ScheduledChange{}
+	for rows.Next() {
+		var change models.ScheduledChange
+		if err := scanScheduledChange(rows, &change); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// scanScheduledChange scans a row selected with scheduledChangeSelectColumns into change
+func scanScheduledChange(row pgx.Row, change *models.ScheduledChange) error {
+	return row.Scan(
+		&change.ChangeID, &change.BankID, &change.Environment, &change.Changes, &change.Previous,
+		&change.ApplyAt, &change.RevertAt, &change.Reason, &change.Status, &change.Error,
+		&change.CreatedBy, &change.CreatedAt, &change.AppliedAt, &change.RevertedAt, &change.CancelledAt,
+	)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SortField is a single ordering term requested by a client
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// bankSortColumns whitelists the sortable bank fields and maps them to SQL columns
+var bankSortColumns = map[string]string{
+	"bank_id":     "b.bank_id",
+	"name":        "b.name",
+	"real_name":   "b.real_name",
+	"bic":         "b.bic",
+	"api":         "b.api",
+	"api_version": "b.api_version",
+	"aspsp":       "b.aspsp",
+	"country":     "b.country",
+	"created_at":  "b.created_at",
+	"updated_at":  "b.updated_at",
+}
+
+// bankGroupSortColumns whitelists the sortable bank group fields and maps them to SQL columns
+var bankGroupSortColumns = map[string]string{
+	"group_id":   "bg.group_id",
+	"name":       "bg.name",
+	"created_at": "bg.created_at",
+	"updated_at": "bg.updated_at",
+}
+
+// ValidateBankSort checks that every field is a sortable bank field
+func ValidateBankSort(sort []SortField) error {
+	return validateSort(sort, bankSortColumns)
+}
+
+// ValidateBankGroupSort checks that every field is a sortable bank group field
+func ValidateBankGroupSort(sort []SortField) error {
+	return validateSort(sort, bankGroupSortColumns)
+}
+
+func validateSort(sort []SortField, columns map[string]string) error {
+	for _, field := range sort {
+		if _, ok := columns[field.Field]; !ok {
+			allowed := slices.Sorted(maps.Keys(columns))
+			return fmt.Errorf("invalid sort field: %s (allowed: %s)", field.Field, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// buildSortClause renders an ORDER BY clause for the requested fields, followed by the
+// tie-breaker columns not already used so that pagination stays deterministic.
+// Fields are expected to be validated; unknown ones are skipped defensively.
+func buildSortClause(sort []SortField, columns map[string]string, tieBreakers ...string) string {
+	terms := make([]string, 0, len(sort)+len(tieBreakers))
+	used := make(map[string]bool)
+
+	for _, field := range sort {
+		column, ok := columns[field.Field]
+		if !ok || used[column] {
+			continue
+		}
+		used[column] = true
+
+		direction := "ASC"
+		if field.Descending {
+			direction = "DESC"
+		}
+		terms = append(terms, column+" "+direction+" NULLS LAST")
+	}
+
+	for _, column := range tieBreakers {
+		if !used[column] {
+			used[column] = true
+			terms = append(terms, column)
+		}
+	}
+
+	return "ORDER BY " + strings.Join(terms, ", ")
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrVersionConflict reports a write based on a version of a row that is no longer the stored one
+var ErrVersionConflict = errors.New("version conflict")
+
+// rowQuerier is the QueryRow method shared by pgxpool.Pool and pgx.Tx
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// bankUpdateMissError explains why a versioned bank update matched no row: either the bank does not
+// exist or it was written since the caller read it
+func bankUpdateMissError(ctx context.Context, db rowQuerier, bankID string) error {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM banks WHERE bank_id = $1 AND deleted_at IS NULL)"
+	if err := db.QueryRow(ctx, query, bankID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check bank: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("bank with ID '%s' not found", bankID)
+	}
+	return fmt.Errorf("%w: bank with ID '%s' was modified concurrently", ErrVersionConflict, bankID)
+}
+
+// bankGroupUpdateMissError is the bank group counterpart of bankUpdateMissError
+func bankGroupUpdateMissError(ctx context.Context, db rowQuerier, groupID uuid.UUID) error {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM bank_groups WHERE group_id = $1)"
+	if err := db.QueryRow(ctx, query, groupID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check bank group: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("bank group with ID '%s' not found", groupID)
+	}
+	return fmt.Errorf("%w: bank group with ID '%s' was modified concurrently", ErrVersionConflict, groupID)
+}
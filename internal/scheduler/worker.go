@@ -0,0 +1,78 @@
+// Package scheduler runs the background work of the API, such as applying scheduled config changes
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// ChangeApplier applies the scheduled changes that are due, up to limit per call
+type ChangeApplier interface {
+	ApplyDueChanges(ctx context.Context, limit int) ([]models.ScheduledChange, error)
+}
+
+// Worker periodically applies the scheduled changes that are due
+type Worker struct {
+	applier   ChangeApplier
+	interval  time.Duration
+	batchSize int
+	log       logger.Logger
+}
+
+// NewWorker creates a worker that applies up to batchSize changes every interval
+func NewWorker(applier ChangeApplier, interval time.Duration, batchSize int, log logger.Logger) *Worker {
+	return &Worker{
+		applier:   applier,
+		interval:  interval,
+		batchSize: batchSize,
+		log:       log,
+	}
+}
+
+// Run applies due changes right away and then on every tick until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	w.log.Info("scheduled change worker started", "interval", w.interval.String(), "batch_size", w.batchSize)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.log.Info("scheduled change worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick applies one batch of due changes and logs the outcome of each
+func (w *Worker) tick(ctx context.Context) {
+	changes, err := w.applier.ApplyDueChanges(ctx, w.batchSize)
+	for i := range changes {
+		change := &changes[i]
+		if change.Status == models.ScheduledChangeFailed {
+			w.log.Warn("scheduled change failed",
+				"change_id", change.ChangeID.String(),
+				"bank_id", change.BankID,
+				"environment", string(change.Environment),
+				"error", *change.Error,
+			)
+			continue
+		}
+		w.log.Info("scheduled change processed",
+			"change_id", change.ChangeID.String(),
+			"bank_id", change.BankID,
+			"environment", string(change.Environment),
+			"status", change.Status,
+		)
+	}
+	if err != nil && ctx.Err() == nil {
+		w.log.Error("failed to apply scheduled changes", "error", err)
+	}
+}
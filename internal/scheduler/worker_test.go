@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/wukong0111/go-banks/internal/logger"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockChangeApplier implements the ChangeApplier interface for testing
+type MockChangeApplier struct {
+	mock.Mock
+}
+
+func (m *MockChangeApplier) ApplyDueChanges(ctx context.Context, limit int) ([]models.ScheduledChange, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ScheduledChange), args.Error(1)
+}
+
+func TestWorker_Run(t *testing.T) {
+	applier := new(MockChangeApplier)
+	failure := "environment config 'uat' of bank 'BES0049' not found"
+	ctx, cancel := context.WithCancel(context.Background())
+
+	applier.On("ApplyDueChanges", mock.Anything, 10).Return([]models.ScheduledChange{
+		{ChangeID: uuid.New(), BankID: "BES0049", Environment: models.EnvironmentProduction, Status: models.ScheduledChangeActive},
+		{ChangeID: uuid.New(), BankID: "BES0049", Environment: models.EnvironmentUAT, Status: models.ScheduledChangeFailed, Error: &failure},
+	}, nil).Once()
+	applier.On("ApplyDueChanges", mock.Anything, 10).Run(func(mock.Arguments) {
+		cancel()
+	}).Return([]models.ScheduledChange{}, nil)
+
+	worker := NewWorker(applier, time.Millisecond, 10, logger.NewDiscardLogger())
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker did not stop after its context was cancelled")
+	}
+
+	assert.GreaterOrEqual(t, len(applier.Calls), 2)
+	applier.AssertExpectations(t)
+}
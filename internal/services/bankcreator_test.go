@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
 )
 
 // MockBankWriter implements the BankWriter interface for testing
@@ -39,6 +40,56 @@ func (m *MockBankWriter) UpdateBankWithEnvironments(ctx context.Context, bank *m
 	return args.Error(0)
 }
 
+func (m *MockBankWriter) ImportBanks(ctx context.Context, operations []*repository.BankImportOperation, options repository.BankImportOptions) ([]error, error) {
+	args := m.Called(ctx, operations, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockBankWriter) DeleteBank(ctx context.Context, bankID string) (*models.BankDeletion, error) {
+	args := m.Called(ctx, bankID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankDeletion), args.Error(1)
+}
+
+func (m *MockBankWriter) SaveEnvironmentConfig(ctx context.Context, config *models.BankEnvironmentConfig) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+func (m *MockBankWriter) DeleteEnvironmentConfig(ctx context.Context, bankID string, environment models.EnvironmentType, version int64) error {
+	args := m.Called(ctx, bankID, environment, version)
+	return args.Error(0)
+}
+
+func (m *MockBankWriter) PromoteEnvironmentConfigs(ctx context.Context, promotions []*repository.EnvironmentPromotion, dryRun bool) ([]repository.EnvironmentPromotionOutcome, error) {
+	args := m.Called(ctx, promotions, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.EnvironmentPromotionOutcome), args.Error(1)
+}
+
+func (m *MockBankWriter) RestoreBank(ctx context.Context, bankID string) (*models.Bank, error) {
+	args := m.Called(ctx, bankID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Bank), args.Error(1)
+}
+
+func (m *MockBankWriter) PurgeBank(ctx context.Context, bankID string) (*models.BankPurge, error) {
+	args := m.Called(ctx, bankID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankPurge), args.Error(1)
+}
+
 func TestBankCreatorService_CreateBank_Simple(t *testing.T) {
 	mockWriter := new(MockBankWriter)
 	service := NewBankCreatorService(mockWriter)
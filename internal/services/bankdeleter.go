@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// BankDeleter soft-deletes, restores and purges banks
+type BankDeleter interface {
+	DeleteBank(ctx context.Context, bankID string) (*models.BankDeletion, error)
+	RestoreBank(ctx context.Context, bankID string) (*models.Bank, error)
+	PurgeBank(ctx context.Context, bankID string) (*models.BankPurge, error)
+}
+
+type BankDeleterService struct {
+	writer repository.BankWriter
+}
+
+func NewBankDeleterService(writer repository.BankWriter) *BankDeleterService {
+	return &BankDeleterService{
+		writer: writer,
+	}
+}
+
+// DeleteBank hides a bank from every read until it is restored
+func (s *BankDeleterService) DeleteBank(ctx context.Context, bankID string) (*models.BankDeletion, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	deletion, err := s.writer.DeleteBank(ctx, bankID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete bank: %w", err)
+	}
+
+	return deletion, nil
+}
+
+// RestoreBank makes a soft-deleted bank visible again
+func (s *BankDeleterService) RestoreBank(ctx context.Context, bankID string) (*models.Bank, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	bank, err := s.writer.RestoreBank(ctx, bankID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore bank: %w", err)
+	}
+
+	return bank, nil
+}
+
+// PurgeBank permanently removes a bank and its environment configs
+func (s *BankDeleterService) PurgeBank(ctx context.Context, bankID string) (*models.BankPurge, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	purge, err := s.writer.PurgeBank(ctx, bankID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge bank: %w", err)
+	}
+
+	return purge, nil
+}
+
+func normalizeBankID(bankID string) (string, error) {
+	bankID = strings.TrimSpace(bankID)
+	if bankID == "" {
+		return "", errors.New("invalid request: bank ID is required")
+	}
+	return bankID, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+func TestBankDeleterService_DeleteBank(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	service := NewBankDeleterService(mockWriter)
+
+	deletion := &models.BankDeletion{BankID: "BES2100", DeletedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	mockWriter.On("DeleteBank", mock.Anything, "BES2100").Return(deletion, nil)
+
+	result, err := service.DeleteBank(context.Background(), " BES2100 ")
+
+	require.NoError(t, err)
+	assert.Equal(t, deletion, result)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankDeleterService_DeleteBank_NotFound(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	service := NewBankDeleterService(mockWriter)
+
+	mockWriter.On("DeleteBank", mock.Anything, "UNKNOWN").Return(nil, errors.New("bank with ID 'UNKNOWN' not found"))
+
+	result, err := service.DeleteBank(context.Background(), "UNKNOWN")
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestBankDeleterService_RestoreBank(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	service := NewBankDeleterService(mockWriter)
+
+	bank := &models.Bank{BankID: "BES2100", Name: "CaixaBank"}
+	mockWriter.On("RestoreBank", mock.Anything, "BES2100").Return(bank, nil)
+	mockWriter.On("RestoreBank", mock.Anything, "BES0049").Return(nil, errors.New("bank with ID 'BES0049' is not deleted"))
+
+	result, err := service.RestoreBank(context.Background(), "BES2100")
+	require.NoError(t, err)
+	assert.Equal(t, bank, result)
+
+	result, err = service.RestoreBank(context.Background(), "BES0049")
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "is not deleted")
+}
+
+func TestBankDeleterService_PurgeBank(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	service := NewBankDeleterService(mockWriter)
+
+	purge := &models.BankPurge{BankID: "BES2100", EnvironmentConfigsDeleted: 2}
+	mockWriter.On("PurgeBank", mock.Anything, "BES2100").Return(purge, nil)
+
+	result, err := service.PurgeBank(context.Background(), "BES2100")
+
+	require.NoError(t, err)
+	assert.Equal(t, purge, result)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankDeleterService_MissingBankID(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	service := NewBankDeleterService(mockWriter)
+
+	_, err := service.DeleteBank(context.Background(), "  ")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request")
+
+	_, err = service.RestoreBank(context.Background(), "")
+	require.Error(t, err)
+
+	_, err = service.PurgeBank(context.Background(), "")
+	require.Error(t, err)
+
+	mockWriter.AssertNotCalled(t, "DeleteBank", mock.Anything, mock.Anything)
+	mockWriter.AssertNotCalled(t, "RestoreBank", mock.Anything, mock.Anything)
+	mockWriter.AssertNotCalled(t, "PurgeBank", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// Supported catalog export formats
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportListSeparator joins string lists inside a single CSV cell
+const exportListSeparator = ";"
+
+// BankExportRow is a bank flattened with one of its environment configs. Banks without configs
+// are exported as a single row whose environment fields are empty.
+type BankExportRow struct {
+	models.Bank
+	Environment                  *models.EnvironmentType `json:"environment"`
+	Enabled                      *bool                   `json:"enabled"`
+	Blocked                      *bool                   `json:"blocked"`
+	BlockedText                  *string                 `json:"blocked_text"`
+	Risky                        *bool                   `json:"risky"`
+	RiskyMessage                 *string                 `json:"risky_message"`
+	SupportsInstantPayments      *bool                   `json:"supports_instant_payments"`
+	InstantPaymentsActivated     *bool                   `json:"instant_payments_activated"`
+	InstantPaymentsLimit         *int32                  `json:"instant_payments_limit"`
+	OkStatusCodesSimplePayment   []string                `json:"ok_status_codes_simple_payment"`
+	OkStatusCodesInstantPayment  []string                `json:"ok_status_codes_instant_payment"`
+	OkStatusCodesPeriodicPayment []string                `json:"ok_status_codes_periodic_payment"`
+	EnabledPeriodicPayment       *bool                   `json:"enabled_periodic_payment"`
+	FrequencyPeriodicPayment     *string                 `json:"frequency_periodic_payment"`
+	ConfigPeriodicPayment        *string                 `json:"config_periodic_payment"`
+	AppAuthSetupRequired         *bool                   `json:"app_auth_setup_required"`
+}
+
+// BankExportColumns is the CSV header. It has a column for every BankExportRow JSON field, in the
+// field order except for version, which comes after the timestamps.
+var BankExportColumns = []string{
+	"bank_id", "name", "bank_codes", "bic", "real_name", "api", "api_version", "aspsp",
+	"product_code", "country", "bank_group_id", "logo_url", "documentation", "keywords",
+	"attribute", "auth_type_choice_required", "created_at", "updated_at", "version",
+	"environment", "enabled", "blocked", "blocked_text", "risky", "risky_message",
+	"supports_instant_payments", "instant_payments_activated", "instant_payments_limit",
+	"ok_status_codes_simple_payment", "ok_status_codes_instant_payment", "ok_status_codes_periodic_payment",
+	"enabled_periodic_payment", "frequency_periodic_payment", "config_periodic_payment",
+	"app_auth_setup_required",
+}
+
+// FlattenBank returns the export rows of a bank, one per environment config in environment order
+func FlattenBank(bank *models.BankWithEnvironments) []BankExportRow {
+	if len(bank.EnvironmentConfigs) == 0 {
+		return []BankExportRow{{Bank: bank.Bank}}
+	}
+
+	environments := make([]string, 0, len(bank.EnvironmentConfigs))
+	for environment := range bank.EnvironmentConfigs {
+		environments = append(environments, environment)
+	}
+	slices.Sort(environments)
+
+	rows := make([]BankExportRow, 0, len(environments))
+	for _, environment := range environments {
+		config := bank.EnvironmentConfigs[environment]
+		rows = append(rows, BankExportRow{
+			Bank:                         bank.Bank,
+			Environment:                  &config.Environment,
+			Enabled:                      &config.Enabled,
+			Blocked:                      &config.Blocked,
+			BlockedText:                  config.BlockedText,
+			Risky:                        &config.Risky,
+			RiskyMessage:                 config.RiskyMessage,
+			SupportsInstantPayments:      config.SupportsInstantPayments,
+			InstantPaymentsActivated:     config.InstantPaymentsActivated,
+			InstantPaymentsLimit:         config.InstantPaymentsLimit,
+			OkStatusCodesSimplePayment:   config.OkStatusCodesSimplePayment,
+			OkStatusCodesInstantPayment:  config.OkStatusCodesInstantPayment,
+			OkStatusCodesPeriodicPayment: config.OkStatusCodesPeriodicPayment,
+			EnabledPeriodicPayment:       config.EnabledPeriodicPayment,
+			FrequencyPeriodicPayment:     config.FrequencyPeriodicPayment,
+			ConfigPeriodicPayment:        config.ConfigPeriodicPayment,
+			AppAuthSetupRequired:         &config.AppAuthSetupRequired,
+		})
+	}
+	return rows
+}
+
+// BankExportWriter encodes exported banks in a streaming format
+type BankExportWriter interface {
+	// WriteHeader writes the preamble of the format, if any
+	WriteHeader() error
+	// WriteBank writes every export row of a bank
+	WriteBank(bank *models.BankWithEnvironments) error
+	// Flush writes any buffered data to the underlying writer
+	Flush() error
+}
+
+// NewBankExportWriter creates a writer for the given export format
+func NewBankExportWriter(format string, w io.Writer) (BankExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvBankExportWriter{writer: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonBankExportWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("invalid format: %s (allowed: %s, %s)", format, ExportFormatCSV, ExportFormatNDJSON)
+	}
+}
+
+// ExportContentType returns the media type of an export format
+func ExportContentType(format string) string {
+	if format == ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type ndjsonBankExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonBankExportWriter) WriteHeader() error {
+	return nil
+}
+
+func (w *ndjsonBankExportWriter) WriteBank(bank *models.BankWithEnvironments) error {
+	for _, row := range FlattenBank(bank) {
+		if err := w.encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode bank %s: %w", bank.BankID, err)
+		}
+	}
+	return nil
+}
+
+func (w *ndjsonBankExportWriter) Flush() error {
+	return nil
+}
+
+type csvBankExportWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvBankExportWriter) WriteHeader() error {
+	return w.writer.Write(BankExportColumns)
+}
+
+func (w *csvBankExportWriter) WriteBank(bank *models.BankWithEnvironments) error {
+	for _, row := range FlattenBank(bank) {
+		record, err := csvRecord(&row)
+		if err != nil {
+			return fmt.Errorf("failed to encode bank %s: %w", bank.BankID, err)
+		}
+		if err := w.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *csvBankExportWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// csvRecord renders a row as CSV cells using its JSON encoding, so both formats share field names and
+// value formats. Nulls become empty cells, string lists are joined and objects are kept as JSON.
+func csvRecord(row *BankExportRow) ([]string, error) {
+	encoded, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(BankExportColumns))
+	for i, column := range BankExportColumns {
+		record[i], err = csvCell(fields[column])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column, err)
+		}
+	}
+	return record, nil
+}
+
+func csvCell(raw json.RawMessage) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if len(raw) > 0 {
+		if err := decoder.Decode(&value); err != nil {
+			return "", err
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, exportListSeparator), nil
+	default:
+		return string(raw), nil
+	}
+}
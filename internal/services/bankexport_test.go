@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+func exportTestBank() *models.BankWithEnvironments {
+	bic := "CAIXESBBXXX"
+	limit := int32(15000)
+	createdAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	return &models.BankWithEnvironments{
+		Bank: models.Bank{
+			BankID:    "BES2100",
+			Name:      "CaixaBank, S.A.",
+			BankCodes: []string{"2100", "2101"},
+			BIC:       &bic,
+			Country:   "ES",
+			Keywords:  map[string]any{"alias": "caixa"},
+			Version:   3,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+		EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"sandbox": {
+				BankID:      "BES2100",
+				Environment: models.EnvironmentSandbox,
+				Enabled:     true,
+			},
+			"production": {
+				BankID:                     "BES2100",
+				Environment:                models.EnvironmentProduction,
+				Enabled:                    true,
+				InstantPaymentsLimit:       &limit,
+				OkStatusCodesSimplePayment: []string{"ACSC", "ACCP"},
+			},
+		},
+	}
+}
+
+func TestFlattenBank(t *testing.T) {
+	rows := FlattenBank(exportTestBank())
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, models.EnvironmentProduction, *rows[0].Environment)
+	assert.Equal(t, models.EnvironmentSandbox, *rows[1].Environment)
+	assert.Equal(t, "BES2100", rows[1].BankID)
+
+	// Banks without configs still produce a row
+	rows = FlattenBank(&models.BankWithEnvironments{Bank: models.Bank{BankID: "BES0049"}})
+	require.Len(t, rows, 1)
+	assert.Nil(t, rows[0].Environment)
+	assert.Nil(t, rows[0].Enabled)
+}
+
+func TestBankExportColumns_MatchRowFields(t *testing.T) {
+	encoded, err := json.Marshal(FlattenBank(exportTestBank())[0])
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+
+	// Every JSON field of a row must have a CSV column, and every column a field
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(fields)), BankExportColumns)
+}
+
+func TestBankExportWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewBankExportWriter(ExportFormatCSV, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteHeader())
+	require.NoError(t, writer.WriteBank(exportTestBank()))
+	require.NoError(t, writer.Flush())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, BankExportColumns, records[0])
+
+	production := make(map[string]string)
+	for i, column := range BankExportColumns {
+		production[column] = records[1][i]
+	}
+	assert.Equal(t, "CaixaBank, S.A.", production["name"])
+	assert.Equal(t, "2100;2101", production["bank_codes"])
+	assert.Equal(t, "", production["real_name"])
+	assert.JSONEq(t, `{"alias":"caixa"}`, production["keywords"])
+	assert.Equal(t, "2025-01-15T10:30:00Z", production["created_at"])
+	assert.Equal(t, "3", production["version"])
+	assert.Equal(t, "production", production["environment"])
+	assert.Equal(t, "true", production["enabled"])
+	assert.Equal(t, "15000", production["instant_payments_limit"])
+	assert.Equal(t, "ACSC;ACCP", production["ok_status_codes_simple_payment"])
+	assert.Equal(t, "", production["supports_instant_payments"])
+}
+
+func TestBankExportWriter_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewBankExportWriter(ExportFormatNDJSON, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteHeader())
+	require.NoError(t, writer.WriteBank(exportTestBank()))
+	require.NoError(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal(t, "BES2100", row["bank_id"])
+	assert.Equal(t, "production", row["environment"])
+	assert.InDelta(t, 15000, row["instant_payments_limit"], 0)
+	assert.Equal(t, []any{"2100", "2101"}, row["bank_codes"])
+}
+
+func TestNewBankExportWriter_InvalidFormat(t *testing.T) {
+	writer, err := NewBankExportWriter("xlsx", &bytes.Buffer{})
+
+	require.Error(t, err)
+	assert.Nil(t, writer)
+	assert.Contains(t, err.Error(), "invalid format: xlsx")
+}
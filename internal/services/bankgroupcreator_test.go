@@ -27,6 +27,14 @@ func (m *MockBankGroupWriter) UpdateBankGroup(ctx context.Context, bankGroup *mo
 	return args.Error(0)
 }
 
+func (m *MockBankGroupWriter) DeleteBankGroup(ctx context.Context, groupID uuid.UUID, policy string, reassignTo *uuid.UUID) (*models.BankGroupDeletion, error) {
+	args := m.Called(ctx, groupID, policy, reassignTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroupDeletion), args.Error(1)
+}
+
 func TestBankGroupCreatorService_CreateBankGroup_Success(t *testing.T) {
 	ctx := context.Background()
 	mockWriter := new(MockBankGroupWriter)
@@ -263,8 +271,3 @@ func TestBankGroupCreatorService_CreateBankGroup_EmptyOptionalFields(t *testing.
 	assert.Nil(t, result.Website)
 	mockWriter.AssertExpectations(t)
 }
-
-// Helper function to create string pointers
-func stringPtr(s string) *string {
-	return &s
-}
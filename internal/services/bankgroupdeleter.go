@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// DeleteBankGroupRequest selects what happens to the banks of a deleted group
+type DeleteBankGroupRequest struct {
+	Policy     string // reject, detach or reassign; defaults to reassign when ReassignTo is set
+	ReassignTo string // target group of the reassign policy
+}
+
+type BankGroupDeleter interface {
+	DeleteBankGroup(ctx context.Context, groupID string, request *DeleteBankGroupRequest) (*models.BankGroupDeletion, error)
+}
+
+type BankGroupDeleterService struct {
+	writer repository.BankGroupWriter
+}
+
+func NewBankGroupDeleterService(writer repository.BankGroupWriter) *BankGroupDeleterService {
+	return &BankGroupDeleterService{
+		writer: writer,
+	}
+}
+
+var bankGroupDeletePolicies = []string{
+	models.BankGroupDeletePolicyReject,
+	models.BankGroupDeletePolicyDetach,
+	models.BankGroupDeletePolicyReassign,
+}
+
+func (s *BankGroupDeleterService) DeleteBankGroup(ctx context.Context, groupID string, request *DeleteBankGroupRequest) (*models.BankGroupDeletion, error) {
+	groupUUID, err := uuid.Parse(strings.TrimSpace(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid group_id: %w", err)
+	}
+
+	policy := strings.TrimSpace(request.Policy)
+	reassignTo := strings.TrimSpace(request.ReassignTo)
+	if policy == "" && reassignTo != "" {
+		policy = models.BankGroupDeletePolicyReassign
+	}
+
+	if policy == "" {
+		return nil, fmt.Errorf("invalid policy: a policy is required (allowed: %s)", strings.Join(bankGroupDeletePolicies, ", "))
+	}
+	if !slices.Contains(bankGroupDeletePolicies, policy) {
+		return nil, fmt.Errorf("invalid policy: %s (allowed: %s)", policy, strings.Join(bankGroupDeletePolicies, ", "))
+	}
+
+	var target *uuid.UUID
+	switch {
+	case policy == models.BankGroupDeletePolicyReassign && reassignTo == "":
+		return nil, fmt.Errorf("invalid reassign_to: required with the %s policy", models.BankGroupDeletePolicyReassign)
+	case policy != models.BankGroupDeletePolicyReassign && reassignTo != "":
+		return nil, fmt.Errorf("invalid reassign_to: only allowed with the %s policy", models.BankGroupDeletePolicyReassign)
+	case reassignTo != "":
+		parsed, err := uuid.Parse(reassignTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reassign_to: %w", err)
+		}
+		if parsed == groupUUID {
+			return nil, errors.New("invalid reassign_to: banks cannot be reassigned to the deleted group")
+		}
+		target = &parsed
+	}
+
+	deletion, err := s.writer.DeleteBankGroup(ctx, groupUUID, policy, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete bank group: %w", err)
+	}
+
+	return deletion, nil
+}
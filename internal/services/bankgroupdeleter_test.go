@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+func TestBankGroupDeleterService_DeleteBankGroup(t *testing.T) {
+	groupID := uuid.New()
+	targetID := uuid.New()
+
+	tests := []struct {
+		name             string
+		request          *DeleteBankGroupRequest
+		expectedPolicy   string
+		expectedReassign *uuid.UUID
+	}{
+		{
+			name:           "reject",
+			request:        &DeleteBankGroupRequest{Policy: "reject"},
+			expectedPolicy: models.BankGroupDeletePolicyReject,
+		},
+		{
+			name:           "detach",
+			request:        &DeleteBankGroupRequest{Policy: " detach "},
+			expectedPolicy: models.BankGroupDeletePolicyDetach,
+		},
+		{
+			name:             "reassign",
+			request:          &DeleteBankGroupRequest{Policy: "reassign", ReassignTo: targetID.String()},
+			expectedPolicy:   models.BankGroupDeletePolicyReassign,
+			expectedReassign: &targetID,
+		},
+		{
+			name:             "reassign_to implies the reassign policy",
+			request:          &DeleteBankGroupRequest{ReassignTo: targetID.String()},
+			expectedPolicy:   models.BankGroupDeletePolicyReassign,
+			expectedReassign: &targetID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWriter := new(MockBankGroupWriter)
+			service := NewBankGroupDeleterService(mockWriter)
+
+			deletion := &models.BankGroupDeletion{GroupID: groupID, Policy: tt.expectedPolicy, Deleted: true, AffectedBanks: []string{"BES2100"}}
+			mockWriter.On("DeleteBankGroup", mock.Anything, groupID, tt.expectedPolicy, tt.expectedReassign).Return(deletion, nil)
+
+			result, err := service.DeleteBankGroup(context.Background(), groupID.String(), tt.request)
+
+			require.NoError(t, err)
+			assert.Equal(t, deletion, result)
+			mockWriter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBankGroupDeleterService_DeleteBankGroup_InvalidRequests(t *testing.T) {
+	groupID := uuid.New()
+
+	tests := []struct {
+		name          string
+		groupID       string
+		request       *DeleteBankGroupRequest
+		expectedError string
+	}{
+		{
+			name:          "invalid group ID",
+			groupID:       "not-a-uuid",
+			request:       &DeleteBankGroupRequest{Policy: "detach"},
+			expectedError: "invalid group_id",
+		},
+		{
+			name:          "missing policy",
+			groupID:       groupID.String(),
+			request:       &DeleteBankGroupRequest{},
+			expectedError: "invalid policy: a policy is required",
+		},
+		{
+			name:          "unknown policy",
+			groupID:       groupID.String(),
+			request:       &DeleteBankGroupRequest{Policy: "cascade"},
+			expectedError: "invalid policy: cascade",
+		},
+		{
+			name:          "reassign without target",
+			groupID:       groupID.String(),
+			request:       &DeleteBankGroupRequest{Policy: "reassign"},
+			expectedError: "invalid reassign_to: required",
+		},
+		{
+			name:          "target with another policy",
+			groupID:       groupID.String(),
+			request:       &DeleteBankGroupRequest{Policy: "detach", ReassignTo: uuid.New().String()},
+			expectedError: "invalid reassign_to: only allowed",
+		},
+		{
+			name:          "invalid target",
+			groupID:       groupID.String(),
+			request:       &DeleteBankGroupRequest{Policy: "reassign", ReassignTo: "nope"},
+			expectedError: "invalid reassign_to",
+		},
+		{
+			name:          "target is the deleted group",
+			groupID:       groupID.String(),
+			request:       &DeleteBankGroupRequest{Policy: "reassign", ReassignTo: groupID.String()},
+			expectedError: "invalid reassign_to: banks cannot be reassigned to the deleted group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWriter := new(MockBankGroupWriter)
+			service := NewBankGroupDeleterService(mockWriter)
+
+			result, err := service.DeleteBankGroup(context.Background(), tt.groupID, tt.request)
+
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			mockWriter.AssertNotCalled(t, "DeleteBankGroup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankGroupDeleterService_DeleteBankGroup_WriterError(t *testing.T) {
+	mockWriter := new(MockBankGroupWriter)
+	service := NewBankGroupDeleterService(mockWriter)
+
+	groupID := uuid.New()
+	mockWriter.On("DeleteBankGroup", mock.Anything, groupID, "detach", (*uuid.UUID)(nil)).
+		Return(nil, errors.New("bank group with ID '"+groupID.String()+"' not found"))
+
+	result, err := service.DeleteBankGroup(context.Background(), groupID.String(), &DeleteBankGroupRequest{Policy: "detach"})
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// BankGroupPatcher applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents to bank groups
+type BankGroupPatcher interface {
+	PatchBankGroup(ctx context.Context, groupID, mediaType string, patch []byte, precondition VersionPrecondition) (*models.BankGroup, error)
+}
+
+type BankGroupPatcherService struct {
+	writer repository.BankGroupWriter
+	reader repository.BankGroupRepository
+}
+
+func NewBankGroupPatcherService(writer repository.BankGroupWriter, reader repository.BankGroupRepository) *BankGroupPatcherService {
+	return &BankGroupPatcherService{
+		writer: writer,
+		reader: reader,
+	}
+}
+
+// PatchBankGroup applies the patch to the stored bank group, validates the result and saves it. The
+// write only succeeds if the group is still at the version that was read, which must also satisfy
+// the precondition.
+func (s *BankGroupPatcherService) PatchBankGroup(ctx context.Context, groupID, mediaType string, patch []byte, precondition VersionPrecondition) (*models.BankGroup, error) {
+	id, err := uuid.Parse(strings.TrimSpace(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid group_id: %w", err)
+	}
+
+	existing, err := s.reader.GetBankGroupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(precondition, "bank group", existing.Version); err != nil {
+		return nil, err
+	}
+
+	patched, err := applyPatch(existing, mediaType, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fieldErrorsToError(validatePatchedBankGroup(existing, patched)); err != nil {
+		return nil, err
+	}
+
+	if err := s.writer.UpdateBankGroup(ctx, patched); err != nil {
+		return nil, fmt.Errorf("failed to update bank group: %w", err)
+	}
+
+	// Read the group back for the timestamps set by the database
+	return s.reader.GetBankGroupByID(ctx, id)
+}
+
+// validatePatchedBankGroup applies the rules of UpdateBankGroupRequest to a patched group and
+// rejects changes to the fields managed by the service
+func validatePatchedBankGroup(existing, patched *models.BankGroup) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if patched.GroupID != existing.GroupID {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "group_id", Message: "is read-only"})
+	}
+	if patched.Version != existing.Version {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "version", Message: "is read-only"})
+	}
+	if !patched.CreatedAt.Equal(existing.CreatedAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "created_at", Message: "is read-only"})
+	}
+	if !patched.UpdatedAt.Equal(existing.UpdatedAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "updated_at", Message: "is read-only"})
+	}
+
+	patched.Name = strings.TrimSpace(patched.Name)
+	if patched.Name == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "name", Message: "is required"})
+	}
+
+	return fieldErrors
+}
@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
 
 	"github.com/wukong0111/go-banks/internal/models"
 	"github.com/wukong0111/go-banks/internal/repository"
@@ -9,7 +13,8 @@ import (
 
 // BankGroupService defines the interface for bank group related operations.
 type BankGroupService interface {
-	GetBankGroups(ctx context.Context) ([]models.BankGroup, error)
+	GetBankGroups(ctx context.Context, filters *repository.BankGroupFilters) ([]models.BankGroup, *models.Pagination, error)
+	GetBankGroupDetails(ctx context.Context, groupID string) (*models.BankGroupDetails, error)
 }
 
 type bankGroupService struct {
@@ -22,6 +27,81 @@ func NewBankGroupService(bankGroupRepo repository.BankGroupRepository) BankGroup
 	}
 }
 
-func (s *bankGroupService) GetBankGroups(ctx context.Context) ([]models.BankGroup, error) {
-	return s.bankGroupRepo.GetBankGroups(ctx)
+// GetBankGroups lists the bank groups matching the filters. Every group is returned, without
+// pagination, unless a page or a limit is requested.
+func (s *bankGroupService) GetBankGroups(ctx context.Context, filters *repository.BankGroupFilters) ([]models.BankGroup, *models.Pagination, error) {
+	if err := repository.ValidateBankGroupSort(filters.Sort); err != nil {
+		return nil, nil, err
+	}
+
+	filters.Name = strings.TrimSpace(filters.Name)
+
+	if filters.Page == 0 && filters.Limit == 0 {
+		groups, err := s.bankGroupRepo.GetBankGroups(ctx, filters)
+		return groups, nil, err
+	}
+
+	s.normalizePagination(filters)
+
+	total, err := s.bankGroupRepo.CountBankGroups(ctx, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups, err := s.bankGroupRepo.GetBankGroups(ctx, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &models.Pagination{
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		Total:      total,
+		TotalPages: (total + filters.Limit - 1) / filters.Limit,
+	}
+
+	return groups, pagination, nil
+}
+
+// normalizePagination applies the same page and limit rules as the bank listing
+func (s *bankGroupService) normalizePagination(filters *repository.BankGroupFilters) {
+	const (
+		DefaultPage  = 1
+		DefaultLimit = 20
+		MaxLimit     = 100
+		MinLimit     = 1
+	)
+
+	if filters.Page < 1 {
+		filters.Page = DefaultPage
+	}
+
+	if filters.Limit < MinLimit {
+		filters.Limit = DefaultLimit
+	} else if filters.Limit > MaxLimit {
+		filters.Limit = MaxLimit
+	}
+}
+
+// GetBankGroupDetails returns a bank group with the number of its banks per country and environment
+func (s *bankGroupService) GetBankGroupDetails(ctx context.Context, groupID string) (*models.BankGroupDetails, error) {
+	id, err := uuid.Parse(strings.TrimSpace(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid group_id: %w", err)
+	}
+
+	group, err := s.bankGroupRepo.GetBankGroupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.bankGroupRepo.GetBankGroupMemberCounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BankGroupDetails{
+		BankGroup:    *group,
+		MemberCounts: *counts,
+	}, nil
 }
@@ -12,17 +12,39 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
 )
 
 type MockBankGroupRepository struct {
 	mock.Mock
 }
 
-func (m *MockBankGroupRepository) GetBankGroups(ctx context.Context) ([]models.BankGroup, error) {
-	args := m.Called(ctx)
+func (m *MockBankGroupRepository) GetBankGroups(ctx context.Context, filters *repository.BankGroupFilters) ([]models.BankGroup, error) {
+	args := m.Called(ctx, filters)
 	return args.Get(0).([]models.BankGroup), args.Error(1)
 }
 
+func (m *MockBankGroupRepository) CountBankGroups(ctx context.Context, filters *repository.BankGroupFilters) (int, error) {
+	args := m.Called(ctx, filters)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBankGroupRepository) GetBankGroupByID(ctx context.Context, groupID uuid.UUID) (*models.BankGroup, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroup), args.Error(1)
+}
+
+func (m *MockBankGroupRepository) GetBankGroupMemberCounts(ctx context.Context, groupID uuid.UUID) (*models.BankGroupMemberCounts, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroupMemberCounts), args.Error(1)
+}
+
 func TestBankGroupService_GetBankGroups_Success(t *testing.T) {
 	mockRepo := new(MockBankGroupRepository)
 	service := NewBankGroupService(mockRepo)
@@ -55,14 +77,15 @@ func TestBankGroupService_GetBankGroups_Success(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	mockRepo.On("GetBankGroups", ctx).Return(expectedGroups, nil)
+	mockRepo.On("GetBankGroups", ctx, mock.Anything).Return(expectedGroups, nil)
 
 	// Execute
-	result, err := service.GetBankGroups(ctx)
+	result, pagination, err := service.GetBankGroups(ctx, &repository.BankGroupFilters{})
 
 	// Assert
 	require.NoError(t, err)
 	assert.Equal(t, expectedGroups, result)
+	assert.Nil(t, pagination)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -72,14 +95,15 @@ func TestBankGroupService_GetBankGroups_EmptyResult(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup mock expectations
-	mockRepo.On("GetBankGroups", ctx).Return([]models.BankGroup{}, nil)
+	mockRepo.On("GetBankGroups", ctx, mock.Anything).Return([]models.BankGroup{}, nil)
 
 	// Execute
-	result, err := service.GetBankGroups(ctx)
+	result, pagination, err := service.GetBankGroups(ctx, &repository.BankGroupFilters{})
 
 	// Assert
 	require.NoError(t, err)
 	assert.Empty(t, result)
+	assert.Nil(t, pagination)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -91,14 +115,136 @@ func TestBankGroupService_GetBankGroups_RepositoryError(t *testing.T) {
 	expectedError := errors.New("database connection failed")
 
 	// Setup mock expectations
-	mockRepo.On("GetBankGroups", ctx).Return([]models.BankGroup{}, expectedError)
+	mockRepo.On("GetBankGroups", ctx, mock.Anything).Return([]models.BankGroup{}, expectedError)
 
 	// Execute
-	result, err := service.GetBankGroups(ctx)
+	result, pagination, err := service.GetBankGroups(ctx, &repository.BankGroupFilters{})
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
 	assert.Empty(t, result)
+	assert.Nil(t, pagination)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestBankGroupService_GetBankGroups_InvalidSort(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+
+	filters := &repository.BankGroupFilters{
+		Sort: []repository.SortField{{Field: "country"}},
+	}
+
+	result, pagination, err := service.GetBankGroups(context.Background(), filters)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sort field: country")
+	assert.Nil(t, result)
+	assert.Nil(t, pagination)
+	mockRepo.AssertNotCalled(t, "GetBankGroups", mock.Anything, mock.Anything)
+}
+
+func TestBankGroupService_GetBankGroups_Paginated(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+	ctx := context.Background()
+
+	groups := []models.BankGroup{{GroupID: uuid.New(), Name: "Santander Group"}}
+	filters := &repository.BankGroupFilters{Name: "  santander ", Page: 2, Limit: 500}
+
+	mockRepo.On("CountBankGroups", ctx, filters).Return(101, nil)
+	mockRepo.On("GetBankGroups", ctx, filters).Return(groups, nil)
+
+	result, pagination, err := service.GetBankGroups(ctx, filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, groups, result)
+	assert.Equal(t, "santander", filters.Name)
+	assert.Equal(t, &models.Pagination{Page: 2, Limit: 100, Total: 101, TotalPages: 2}, pagination)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankGroupService_GetBankGroups_DefaultLimit(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+	ctx := context.Background()
+
+	filters := &repository.BankGroupFilters{Page: 1}
+
+	mockRepo.On("CountBankGroups", ctx, filters).Return(0, nil)
+	mockRepo.On("GetBankGroups", ctx, filters).Return([]models.BankGroup{}, nil)
+
+	_, pagination, err := service.GetBankGroups(ctx, filters)
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.Pagination{Page: 1, Limit: 20, Total: 0, TotalPages: 0}, pagination)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankGroupService_GetBankGroups_CountError(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("CountBankGroups", ctx, mock.Anything).Return(0, errors.New("failed to count bank groups: timeout"))
+
+	result, pagination, err := service.GetBankGroups(ctx, &repository.BankGroupFilters{Limit: 10})
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Nil(t, pagination)
+	mockRepo.AssertNotCalled(t, "GetBankGroups", mock.Anything, mock.Anything)
+}
+
+func TestBankGroupService_GetBankGroupDetails_Success(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+	ctx := context.Background()
+
+	groupID := uuid.New()
+	group := &models.BankGroup{GroupID: groupID, Name: "BBVA Group"}
+	counts := &models.BankGroupMemberCounts{
+		Total:         3,
+		ByCountry:     map[string]int{"ES": 2, "MX": 1},
+		ByEnvironment: map[string]int{"production": 3, "sandbox": 1},
+	}
+
+	mockRepo.On("GetBankGroupByID", ctx, groupID).Return(group, nil)
+	mockRepo.On("GetBankGroupMemberCounts", ctx, groupID).Return(counts, nil)
+
+	details, err := service.GetBankGroupDetails(ctx, groupID.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, *group, details.BankGroup)
+	assert.Equal(t, *counts, details.MemberCounts)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankGroupService_GetBankGroupDetails_InvalidID(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+
+	details, err := service.GetBankGroupDetails(context.Background(), "not-a-uuid")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid group_id")
+	assert.Nil(t, details)
+	mockRepo.AssertNotCalled(t, "GetBankGroupByID", mock.Anything, mock.Anything)
+}
+
+func TestBankGroupService_GetBankGroupDetails_NotFound(t *testing.T) {
+	mockRepo := new(MockBankGroupRepository)
+	service := NewBankGroupService(mockRepo)
+	ctx := context.Background()
+
+	groupID := uuid.New()
+	mockRepo.On("GetBankGroupByID", ctx, groupID).Return(nil, errors.New("bank group with ID '"+groupID.String()+"' not found"))
+
+	details, err := service.GetBankGroupDetails(ctx, groupID.String())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Nil(t, details)
+	mockRepo.AssertNotCalled(t, "GetBankGroupMemberCounts", mock.Anything, mock.Anything)
+}
@@ -20,7 +20,7 @@ type UpdateBankGroupRequest struct {
 }
 
 type BankGroupUpdater interface {
-	UpdateBankGroup(ctx context.Context, groupID string, request *UpdateBankGroupRequest) (*models.BankGroup, error)
+	UpdateBankGroup(ctx context.Context, groupID string, request *UpdateBankGroupRequest, precondition VersionPrecondition) (*models.BankGroup, error)
 }
 
 type BankGroupUpdaterService struct {
@@ -35,7 +35,9 @@ func NewBankGroupUpdaterService(writer repository.BankGroupWriter, reader reposi
 	}
 }
 
-func (s *BankGroupUpdaterService) UpdateBankGroup(ctx context.Context, groupID string, request *UpdateBankGroupRequest) (*models.BankGroup, error) {
+// UpdateBankGroup applies the request to the stored bank group. The write only succeeds if the group is
+// still at the version that was read, which must also satisfy the precondition.
+func (s *BankGroupUpdaterService) UpdateBankGroup(ctx context.Context, groupID string, request *UpdateBankGroupRequest, precondition VersionPrecondition) (*models.BankGroup, error) {
 	// Parse and validate group ID
 	groupUUID, err := s.validateGroupID(groupID)
 	if err != nil {
@@ -43,7 +45,7 @@ func (s *BankGroupUpdaterService) UpdateBankGroup(ctx context.Context, groupID s
 	}
 
 	// Fetch existing bank group
-	existingGroups, err := s.reader.GetBankGroups(ctx)
+	existingGroups, err := s.reader.GetBankGroups(ctx, &repository.BankGroupFilters{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch bank groups: %w", err)
 	}
@@ -60,6 +62,10 @@ func (s *BankGroupUpdaterService) UpdateBankGroup(ctx context.Context, groupID s
 		return nil, errors.New("bank group not found")
 	}
 
+	if err := checkVersion(precondition, "bank group", existingGroup.Version); err != nil {
+		return nil, err
+	}
+
 	// Build updated bank group from request
 	updatedGroup, err := s.requestToBankGroup(existingGroup, request)
 	if err != nil {
@@ -96,6 +102,7 @@ func (s *BankGroupUpdaterService) requestToBankGroup(existing *models.BankGroup,
 		Description: existing.Description,
 		LogoURL:     existing.LogoURL,
 		Website:     existing.Website,
+		Version:     existing.Version,
 		CreatedAt:   existing.CreatedAt,
 		UpdatedAt:   existing.UpdatedAt, // Will be updated by database
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
 )
 
 type MockBankGroupUpdaterWriter struct {
@@ -28,15 +29,44 @@ func (m *MockBankGroupUpdaterWriter) UpdateBankGroup(ctx context.Context, bankGr
 	return args.Error(0)
 }
 
+func (m *MockBankGroupUpdaterWriter) DeleteBankGroup(ctx context.Context, groupID uuid.UUID, policy string, reassignTo *uuid.UUID) (*models.BankGroupDeletion, error) {
+	args := m.Called(ctx, groupID, policy, reassignTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroupDeletion), args.Error(1)
+}
+
 type MockBankGroupUpdaterReader struct {
 	mock.Mock
 }
 
-func (m *MockBankGroupUpdaterReader) GetBankGroups(ctx context.Context) ([]models.BankGroup, error) {
-	args := m.Called(ctx)
+func (m *MockBankGroupUpdaterReader) GetBankGroups(ctx context.Context, filters *repository.BankGroupFilters) ([]models.BankGroup, error) {
+	args := m.Called(ctx, filters)
 	return args.Get(0).([]models.BankGroup), args.Error(1)
 }
 
+func (m *MockBankGroupUpdaterReader) CountBankGroups(ctx context.Context, filters *repository.BankGroupFilters) (int, error) {
+	args := m.Called(ctx, filters)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBankGroupUpdaterReader) GetBankGroupByID(ctx context.Context, groupID uuid.UUID) (*models.BankGroup, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroup), args.Error(1)
+}
+
+func (m *MockBankGroupUpdaterReader) GetBankGroupMemberCounts(ctx context.Context, groupID uuid.UUID) (*models.BankGroupMemberCounts, error) {
+	args := m.Called(ctx, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankGroupMemberCounts), args.Error(1)
+}
+
 func TestBankGroupUpdaterService_UpdateBankGroup_Success(t *testing.T) {
 	// Arrange
 	mockWriter := new(MockBankGroupUpdaterWriter)
@@ -60,7 +90,7 @@ func TestBankGroupUpdaterService_UpdateBankGroup_Success(t *testing.T) {
 		LogoURL:     stringPtr("https://example.com/new-logo.png"),
 	}
 
-	mockReader.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
 	mockWriter.On("UpdateBankGroup", mock.Anything, mock.MatchedBy(func(bg *models.BankGroup) bool {
 		return bg.GroupID == groupID &&
 			bg.Name == "Updated Group" &&
@@ -70,7 +100,7 @@ func TestBankGroupUpdaterService_UpdateBankGroup_Success(t *testing.T) {
 	})).Return(nil)
 
 	// Act
-	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request)
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request, nil)
 
 	// Assert
 	require.NoError(t, err)
@@ -106,7 +136,7 @@ func TestBankGroupUpdaterService_UpdateBankGroup_PartialUpdate(t *testing.T) {
 		Name: stringPtr("Updated Group Only"),
 	}
 
-	mockReader.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
 	mockWriter.On("UpdateBankGroup", mock.Anything, mock.MatchedBy(func(bg *models.BankGroup) bool {
 		return bg.GroupID == groupID &&
 			bg.Name == "Updated Group Only" &&
@@ -116,7 +146,7 @@ func TestBankGroupUpdaterService_UpdateBankGroup_PartialUpdate(t *testing.T) {
 	})).Return(nil)
 
 	// Act
-	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request)
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request, nil)
 
 	// Assert
 	require.NoError(t, err)
@@ -138,7 +168,7 @@ func TestBankGroupUpdaterService_UpdateBankGroup_InvalidGroupID(t *testing.T) {
 	}
 
 	// Act & Assert
-	result, err := service.UpdateBankGroup(context.Background(), "invalid-uuid", request)
+	result, err := service.UpdateBankGroup(context.Background(), "invalid-uuid", request, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -158,7 +188,7 @@ func TestBankGroupUpdaterService_UpdateBankGroup_EmptyGroupID(t *testing.T) {
 	}
 
 	// Act & Assert
-	result, err := service.UpdateBankGroup(context.Background(), "", request)
+	result, err := service.UpdateBankGroup(context.Background(), "", request, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -179,10 +209,10 @@ func TestBankGroupUpdaterService_UpdateBankGroup_GroupNotFound(t *testing.T) {
 	}
 
 	// Return empty list (group not found)
-	mockReader.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{}, nil)
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{}, nil)
 
 	// Act
-	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request)
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -210,10 +240,10 @@ func TestBankGroupUpdaterService_UpdateBankGroup_EmptyName(t *testing.T) {
 		Name: stringPtr("   "), // Whitespace only
 	}
 
-	mockReader.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
 
 	// Act
-	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request)
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -233,10 +263,10 @@ func TestBankGroupUpdaterService_UpdateBankGroup_ReaderError(t *testing.T) {
 		Name: stringPtr("Updated Group"),
 	}
 
-	mockReader.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{}, errors.New("database connection failed"))
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{}, errors.New("database connection failed"))
 
 	// Act
-	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request)
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -263,11 +293,11 @@ func TestBankGroupUpdaterService_UpdateBankGroup_WriterError(t *testing.T) {
 		Name: stringPtr("Updated Group"),
 	}
 
-	mockReader.On("GetBankGroups", mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
 	mockWriter.On("UpdateBankGroup", mock.Anything, mock.Anything).Return(errors.New("database write failed"))
 
 	// Act
-	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request)
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(), request, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -276,3 +306,28 @@ func TestBankGroupUpdaterService_UpdateBankGroup_WriterError(t *testing.T) {
 	mockWriter.AssertExpectations(t)
 	mockReader.AssertExpectations(t)
 }
+
+func TestBankGroupUpdaterService_UpdateBankGroup_PreconditionFailed(t *testing.T) {
+	// Arrange
+	mockWriter := new(MockBankGroupUpdaterWriter)
+	mockReader := new(MockBankGroupUpdaterReader)
+	service := NewBankGroupUpdaterService(mockWriter, mockReader)
+
+	groupID := uuid.New()
+	existingGroup := models.BankGroup{
+		GroupID: groupID,
+		Name:    "Original Group",
+		Version: 5,
+	}
+
+	mockReader.On("GetBankGroups", mock.Anything, mock.Anything).Return([]models.BankGroup{existingGroup}, nil)
+
+	// Act
+	result, err := service.UpdateBankGroup(context.Background(), groupID.String(),
+		&UpdateBankGroupRequest{Name: stringPtr("Updated Group")}, VersionPrecondition{4})
+
+	// Assert
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Nil(t, result)
+	mockWriter.AssertNotCalled(t, "UpdateBankGroup", mock.Anything, mock.Anything)
+}
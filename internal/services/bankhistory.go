@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// BankHistoryService returns the audited writes of a bank as field-level changes
+type BankHistoryService interface {
+	GetBankHistory(ctx context.Context, bankID string, page, limit int) ([]models.BankHistoryEntry, *models.Pagination, error)
+}
+
+type bankHistoryService struct {
+	auditRepo repository.AuditRepository
+	bankRepo  repository.BankRepository
+}
+
+// NewBankHistoryService creates a new BankHistoryService
+func NewBankHistoryService(auditRepo repository.AuditRepository, bankRepo repository.BankRepository) BankHistoryService {
+	return &bankHistoryService{
+		auditRepo: auditRepo,
+		bankRepo:  bankRepo,
+	}
+}
+
+// historyIgnoredFields change on every write and would only add noise to the history
+var historyIgnoredFields = map[string]bool{
+	"version":    true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// GetBankHistory returns a page of the writes of a bank, newest first. The history outlives the bank,
+// so deleted and purged banks keep theirs; banks written before auditing started have an empty one.
+func (s *bankHistoryService) GetBankHistory(ctx context.Context, bankID string, page, limit int) ([]models.BankHistoryEntry, *models.Pagination, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	const (
+		DefaultPage  = 1
+		DefaultLimit = 20
+		MaxLimit     = 100
+	)
+	if page < 1 {
+		page = DefaultPage
+	}
+	if limit < 1 {
+		limit = DefaultLimit
+	} else if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	entries, total, err := s.auditRepo.GetAuditEntries(ctx, models.EntityTypeBank, bankID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if total == 0 {
+		if _, err := s.bankRepo.GetBankByID(ctx, bankID); err != nil {
+			if strings.Contains(err.Error(), "no rows in result set") {
+				return nil, nil, fmt.Errorf("bank not found")
+			}
+			return nil, nil, err
+		}
+	}
+
+	history := make([]models.BankHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		changes, err := diffSnapshots(entry.Before, entry.After)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to diff audit entry %d: %w", entry.AuditID, err)
+		}
+
+		history = append(history, models.BankHistoryEntry{
+			AuditID:   entry.AuditID,
+			Revision:  snapshotRevision(entry.After),
+			Operation: entry.Operation,
+			Actor:     entry.Actor,
+			RequestID: entry.RequestID,
+			Timestamp: entry.CreatedAt,
+			Changes:   changes,
+		})
+	}
+
+	pagination := &models.Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + limit - 1) / limit,
+	}
+
+	return history, pagination, nil
+}
+
+// diffSnapshots compares two JSON snapshots of an entity, either of which may be missing, and returns
+// the changed fields sorted by path. Objects are compared field by field; arrays and scalars as a whole.
+func diffSnapshots(before, after json.RawMessage) ([]models.FieldChange, error) {
+	var beforeValue, afterValue any
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &beforeValue); err != nil {
+			return nil, err
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &afterValue); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := []models.FieldChange{}
+	collectChanges("", beforeValue, afterValue, &changes)
+	slices.SortFunc(changes, func(a, b models.FieldChange) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+
+	return changes, nil
+}
+
+// collectChanges appends the differences between two decoded JSON values found under path
+func collectChanges(path string, before, after any, changes *[]models.FieldChange) {
+	beforeObject, beforeIsObject := before.(map[string]any)
+	afterObject, afterIsObject := after.(map[string]any)
+
+	// An object that appears or disappears is reported through its fields
+	if (beforeIsObject || before == nil) && (afterIsObject || after == nil) && (beforeIsObject || afterIsObject) {
+		keys := make(map[string]bool, len(beforeObject)+len(afterObject))
+		for key := range beforeObject {
+			keys[key] = true
+		}
+		for key := range afterObject {
+			keys[key] = true
+		}
+
+		for key := range keys {
+			if historyIgnoredFields[key] {
+				continue
+			}
+			// Nested configs repeat the identity of the bank and of their map key
+			if path != "" && (key == "bank_id" || key == "environment") {
+				continue
+			}
+
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			collectChanges(field, beforeObject[key], afterObject[key], changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, models.FieldChange{Field: path, Before: before, After: after})
+	}
+}
+
+// snapshotRevision returns the version recorded in a bank snapshot, which is the number of the revision
+// taken with it
+func snapshotRevision(snapshot json.RawMessage) *int64 {
+	var versioned struct {
+		Version *int64 `json:"version"`
+	}
+	if len(snapshot) == 0 || json.Unmarshal(snapshot, &versioned) != nil {
+		return nil
+	}
+	return versioned.Version
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockAuditRepository implements the AuditRepository interface for testing
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) GetAuditEntries(ctx context.Context, entityType, entityID string, page, limit int) ([]models.AuditEntry, int, error) {
+	args := m.Called(ctx, entityType, entityID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.AuditEntry), args.Int(1), args.Error(2)
+}
+
+func TestBankHistoryService_GetBankHistory(t *testing.T) {
+	auditRepo := new(MockAuditRepository)
+	bankRepo := new(MockBankRepository)
+	requestID := "req-1"
+	createdAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	entries := []models.AuditEntry{
+		{
+			AuditID:   7,
+			Operation: models.AuditOperationUpdate,
+			Actor:     "ops@example.com",
+			RequestID: &requestID,
+			Before: json.RawMessage(`{"bank_id":"BES0049","name":"Santander","version":3,"updated_at":"2026-03-01T00:00:00Z",
+				"environment_configs":{"production":{"bank_id":"BES0049","environment":"production","blocked":false,"blocked_text":null,"version":1}}}`),
+			After: json.RawMessage(`{"bank_id":"BES0049","name":"Santander","version":4,"updated_at":"2026-03-02T10:00:00Z",
+				"environment_configs":{"production":{"bank_id":"BES0049","environment":"production","blocked":true,"blocked_text":"ASPSP outage","version":2}}}`),
+			CreatedAt: createdAt,
+		},
+	}
+	auditRepo.On("GetAuditEntries", mock.Anything, models.EntityTypeBank, "BES0049", 1, 20).Return(entries, 1, nil)
+
+	service := NewBankHistoryService(auditRepo, bankRepo)
+
+	history, pagination, err := service.GetBankHistory(context.Background(), " BES0049 ", 0, 0)
+
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, int64(7), history[0].AuditID)
+	require.NotNil(t, history[0].Revision)
+	assert.Equal(t, int64(4), *history[0].Revision)
+	assert.Equal(t, "ops@example.com", history[0].Actor)
+	assert.Equal(t, &requestID, history[0].RequestID)
+	assert.Equal(t, createdAt, history[0].Timestamp)
+	assert.Equal(t, []models.FieldChange{
+		{Field: "environment_configs.production.blocked", Before: false, After: true},
+		{Field: "environment_configs.production.blocked_text", Before: nil, After: "ASPSP outage"},
+	}, history[0].Changes)
+	assert.Equal(t, &models.Pagination{Page: 1, Limit: 20, Total: 1, TotalPages: 1}, pagination)
+	bankRepo.AssertNotCalled(t, "GetBankByID", mock.Anything, mock.Anything)
+}
+
+func TestBankHistoryService_GetBankHistory_NoEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		bankErr     error
+		expectedErr string
+	}{
+		{"bank written before auditing", nil, ""},
+		{"unknown bank", errors.New("failed to get bank by ID: no rows in result set"), "bank not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRepo := new(MockAuditRepository)
+			bankRepo := new(MockBankRepository)
+			auditRepo.On("GetAuditEntries", mock.Anything, models.EntityTypeBank, "BES0049", 2, 100).Return([]models.AuditEntry{}, 0, nil)
+			if tt.bankErr != nil {
+				bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(nil, tt.bankErr)
+			} else {
+				bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+			}
+
+			service := NewBankHistoryService(auditRepo, bankRepo)
+
+			history, _, err := service.GetBankHistory(context.Background(), "BES0049", 2, 500)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, history)
+		})
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected []models.FieldChange
+	}{
+		{
+			name:   "creation lists every set field",
+			before: ``,
+			after:  `{"bank_id":"BES0049","name":"Santander","bic":null,"created_at":"2026-03-02T10:00:00Z"}`,
+			expected: []models.FieldChange{
+				{Field: "bank_id", Before: nil, After: "BES0049"},
+				{Field: "name", Before: nil, After: "Santander"},
+			},
+		},
+		{
+			name:   "soft delete",
+			before: `{"name":"Santander","deleted_at":null}`,
+			after:  `{"name":"Santander","deleted_at":"2026-03-02T10:00:00Z"}`,
+			expected: []models.FieldChange{
+				{Field: "deleted_at", Before: nil, After: "2026-03-02T10:00:00Z"},
+			},
+		},
+		{
+			name:   "arrays are compared as a whole",
+			before: `{"bank_codes":["0049","0030"]}`,
+			after:  `{"bank_codes":["0049"]}`,
+			expected: []models.FieldChange{
+				{Field: "bank_codes", Before: []any{"0049", "0030"}, After: []any{"0049"}},
+			},
+		},
+		{
+			name:   "removed environment config",
+			before: `{"environment_configs":{"uat":{"bank_id":"BES0049","environment":"uat","enabled":true}}}`,
+			after:  `{"environment_configs":{}}`,
+			expected: []models.FieldChange{
+				{Field: "environment_configs.uat.enabled", Before: true, After: nil},
+			},
+		},
+		{
+			name:     "version bump only",
+			before:   `{"name":"Santander","version":1}`,
+			after:    `{"name":"Santander","version":2}`,
+			expected: []models.FieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before, after json.RawMessage
+			if tt.before != "" {
+				before = json.RawMessage(tt.before)
+			}
+			if tt.after != "" {
+				after = json.RawMessage(tt.after)
+			}
+
+			changes, err := diffSnapshots(before, after)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, changes)
+		})
+	}
+}
@@ -0,0 +1,649 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// Supported bulk import modes
+const (
+	ImportModeCreateOnly = "create_only" // fail rows of banks that already exist
+	ImportModeUpsert     = "upsert"      // create new banks, merge the given fields and environments into existing ones
+	ImportModeReplace    = "replace"     // create new banks, overwrite existing ones with exactly the file contents
+)
+
+// MaxImportRows caps the number of data rows of a single import file
+const MaxImportRows = 10000
+
+// maxImportLineSize bounds a single NDJSON line
+const maxImportLineSize = 1 << 20
+
+// ImportBanksOptions selects how an import file is read and applied
+type ImportBanksOptions struct {
+	Format string
+	Mode   string
+	DryRun bool
+	Atomic bool
+}
+
+type BankImporter interface {
+	ImportBanks(ctx context.Context, r io.Reader, options *ImportBanksOptions) (*models.BankImportReport, error)
+}
+
+// BankImporterService loads banks and environment configs from files in the export format.
+// Rows go through the same request validation as BankCreatorService and BankUpdaterService.
+type BankImporterService struct {
+	writer  repository.BankWriter
+	reader  repository.BankRepository
+	creator *BankCreatorService
+	updater *BankUpdaterService
+}
+
+func NewBankImporterService(writer repository.BankWriter, reader repository.BankRepository) *BankImporterService {
+	return &BankImporterService{
+		writer:  writer,
+		reader:  reader,
+		creator: NewBankCreatorService(writer),
+		updater: NewBankUpdaterService(writer, reader),
+	}
+}
+
+// importColumnKind tells how a column is parsed from a CSV cell and validated
+type importColumnKind int
+
+const (
+	importString importColumnKind = iota
+	importBool
+	importInteger
+	importList
+	importObject
+	importIgnored // read-only columns accepted so that exports can be imported back
+)
+
+var bankImportColumns = map[string]importColumnKind{
+	"bank_id":                          importString,
+	"name":                             importString,
+	"bank_codes":                       importList,
+	"bic":                              importString,
+	"real_name":                        importString,
+	"api":                              importString,
+	"api_version":                      importString,
+	"aspsp":                            importString,
+	"product_code":                     importString,
+	"country":                          importString,
+	"bank_group_id":                    importString,
+	"logo_url":                         importString,
+	"documentation":                    importString,
+	"keywords":                         importObject,
+	"attribute":                        importObject,
+	"auth_type_choice_required":        importBool,
+	"version":                          importIgnored,
+	"created_at":                       importIgnored,
+	"updated_at":                       importIgnored,
+	"environment":                      importString,
+	"enabled":                          importBool,
+	"blocked":                          importBool,
+	"blocked_text":                     importString,
+	"risky":                            importBool,
+	"risky_message":                    importString,
+	"supports_instant_payments":        importBool,
+	"instant_payments_activated":       importBool,
+	"instant_payments_limit":           importInteger,
+	"ok_status_codes_simple_payment":   importList,
+	"ok_status_codes_instant_payment":  importList,
+	"ok_status_codes_periodic_payment": importList,
+	"enabled_periodic_payment":         importBool,
+	"frequency_periodic_payment":       importString,
+	"config_periodic_payment":          importString,
+	"app_auth_setup_required":          importBool,
+}
+
+func (k importColumnKind) message() string {
+	switch k {
+	case importBool:
+		return "must be a boolean"
+	case importInteger:
+		return "must be a 32-bit integer"
+	case importList:
+		return "must be a list of strings"
+	case importObject:
+		return "must be a JSON object"
+	default:
+		return "must be a string"
+	}
+}
+
+// importRow is a data row of the file with its non-null values encoded as JSON
+type importRow struct {
+	line   int
+	fields map[string]json.RawMessage
+	record bankImportRecord
+	errors []models.FieldError
+}
+
+// bankImportRecord holds the decoded values of a row
+type bankImportRecord struct {
+	CreateBankRequest
+	Environment string `json:"environment"`
+	EnvironmentConfig
+}
+
+// bankImportGroup gathers the rows of one bank
+type bankImportGroup struct {
+	bankID    string
+	rows      []*importRow
+	action    string
+	operation *repository.BankImportOperation
+}
+
+func (g *bankImportGroup) failed() bool {
+	return slices.ContainsFunc(g.rows, func(row *importRow) bool { return len(row.errors) > 0 })
+}
+
+// fail attaches an error to the first row of the group, where the bank fields are read from
+func (g *bankImportGroup) fail(field, message string) {
+	g.rows[0].errors = append(g.rows[0].errors, models.FieldError{Field: field, Message: message})
+}
+
+// ImportBanks validates every row of the file and writes the banks that passed, one operation per bank.
+// The report lists the outcome of every row; an error is only returned when the file or options are
+// invalid or the import could not run at all.
+func (s *BankImporterService) ImportBanks(ctx context.Context, r io.Reader, options *ImportBanksOptions) (*models.BankImportReport, error) {
+	if options.Mode == "" {
+		options.Mode = ImportModeCreateOnly
+	}
+	if !slices.Contains([]string{ImportModeCreateOnly, ImportModeUpsert, ImportModeReplace}, options.Mode) {
+		return nil, fmt.Errorf("invalid mode: %s (allowed: %s, %s, %s)", options.Mode, ImportModeCreateOnly, ImportModeUpsert, ImportModeReplace)
+	}
+
+	var rows []*importRow
+	var err error
+	switch options.Format {
+	case ExportFormatCSV:
+		rows, err = readCSVImportRows(r)
+	case ExportFormatNDJSON:
+		rows, err = readNDJSONImportRows(r)
+	default:
+		return nil, fmt.Errorf("invalid format: %s (allowed: %s, %s)", options.Format, ExportFormatCSV, ExportFormatNDJSON)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("invalid file: no data rows")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, fmt.Errorf("invalid file: at most %d rows are allowed per import", MaxImportRows)
+	}
+
+	groups := groupImportRows(rows)
+
+	bankIDs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if group.bankID != "" {
+			bankIDs = append(bankIDs, group.bankID)
+		}
+	}
+	existing, err := s.reader.GetBanksByIDs(ctx, bankIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing banks: %w", err)
+	}
+	existingByID := make(map[string]*models.Bank, len(existing))
+	for i := range existing {
+		existingByID[existing[i].BankID] = &existing[i]
+	}
+
+	for _, group := range groups {
+		if !group.failed() {
+			s.planGroup(group, existingByID[group.bankID], options.Mode)
+		}
+	}
+
+	var operations []*repository.BankImportOperation
+	var planned []*bankImportGroup
+	validationFailed := false
+	for _, group := range groups {
+		if group.failed() {
+			validationFailed = true
+			continue
+		}
+		operations = append(operations, group.operation)
+		planned = append(planned, group)
+	}
+
+	// An atomic import with invalid rows cannot be committed, so nothing is sent to the database
+	if options.Atomic && validationFailed {
+		planned = nil
+	}
+
+	writeFailed := false
+	if len(planned) > 0 {
+		results, err := s.writer.ImportBanks(ctx, operations, repository.BankImportOptions{
+			Atomic: options.Atomic,
+			DryRun: options.DryRun,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to import banks: %w", err)
+		}
+		for i, group := range planned {
+			if results[i] != nil {
+				group.fail(writeErrorField(results[i]), results[i].Error())
+				writeFailed = true
+			}
+		}
+	}
+
+	if options.Atomic && (validationFailed || writeFailed) {
+		for _, group := range groups {
+			if !group.failed() {
+				group.fail("", "not applied: the import is atomic and other rows failed")
+			}
+		}
+	}
+
+	return buildImportReport(groups, options), nil
+}
+
+// planGroup validates the rows of a bank and prepares its write
+func (s *BankImporterService) planGroup(group *bankImportGroup, existing *models.Bank, mode string) {
+	first := group.rows[0]
+
+	configurations := make(map[string]*EnvironmentConfig)
+	for _, row := range group.rows {
+		environment := row.record.Environment
+		if environment == "" {
+			continue
+		}
+		if _, duplicated := configurations[environment]; duplicated {
+			row.errors = append(row.errors, models.FieldError{
+				Field:   "environment",
+				Message: fmt.Sprintf("duplicate environment %s for bank %s", environment, group.bankID),
+			})
+			continue
+		}
+		configurations[environment] = &row.record.EnvironmentConfig
+	}
+	if group.failed() {
+		return
+	}
+
+	operation := &repository.BankImportOperation{Create: existing == nil}
+
+	switch {
+	case existing != nil && mode == ImportModeCreateOnly:
+		group.fail("bank_id", "bank already exists")
+		return
+
+	case existing != nil && mode == ImportModeUpsert:
+		group.action = models.ImportActionUpdate
+
+		var request UpdateBankRequest
+		decodeImportFields(first.fields, &request)
+		request.Configurations = configurations
+
+		bank, err := s.updater.requestToBank(existing, &request)
+		if err != nil {
+			group.fail("bank_group_id", err.Error())
+			return
+		}
+		operation.Bank = bank
+		operation.Configs = s.updater.buildConfigurationsConfigs(&request, group.bankID)
+
+	default:
+		group.action = models.ImportActionCreate
+		if existing != nil {
+			group.action = models.ImportActionReplace
+			operation.ReplaceConfigs = true
+		}
+
+		request := first.record.CreateBankRequest
+		request.Configurations = configurations
+
+		if fieldErrors := validateCreateBankRequest(&request); len(fieldErrors) > 0 {
+			first.errors = append(first.errors, fieldErrors...)
+			return
+		}
+
+		bank, err := s.creator.requestToBank(&request)
+		if err != nil {
+			group.fail("bank_group_id", err.Error())
+			return
+		}
+		if existing != nil {
+			// A replacement only applies to the version of the bank that was validated
+			bank.Version = existing.Version
+		}
+		operation.Bank = bank
+		operation.Configs = s.creator.buildConfigurationsConfigs(&request, group.bankID)
+	}
+
+	slices.SortFunc(operation.Configs, func(a, b *models.BankEnvironmentConfig) int {
+		return cmp.Compare(a.Environment, b.Environment)
+	})
+	group.operation = operation
+}
+
+// validateCreateBankRequest applies the binding rules of CreateBankRequest, as POST /api/banks does
+func validateCreateBankRequest(request *CreateBankRequest) []models.FieldError {
+	err := binding.Validator.ValidateStruct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []models.FieldError{{Message: err.Error()}}
+	}
+
+	requestType := reflect.TypeFor[CreateBankRequest]()
+	fieldErrors := make([]models.FieldError, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		field := validationError.StructField()
+		if structField, ok := requestType.FieldByName(field); ok {
+			field, _, _ = strings.Cut(structField.Tag.Get("json"), ",")
+		}
+
+		message := "failed " + validationError.Tag() + " validation"
+		if validationError.Tag() == "required" {
+			message = "is required"
+		}
+		fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: message})
+	}
+	return fieldErrors
+}
+
+// writeErrorField names the column behind a database error when PostgreSQL reports it
+func writeErrorField(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ColumnName
+	}
+	return ""
+}
+
+// groupImportRows decodes every row and groups the rows by bank, in order of first appearance.
+// Rows without a bank ID, including unreadable ones, form a group of their own.
+func groupImportRows(rows []*importRow) []*bankImportGroup {
+	var groups []*bankImportGroup
+	byBankID := make(map[string]*bankImportGroup)
+
+	for _, row := range rows {
+		decodeImportRow(row)
+
+		bankID := row.record.BankID
+		if bankID == "" {
+			if len(row.fields) > 0 || len(row.errors) == 0 {
+				row.errors = append(row.errors, models.FieldError{Field: "bank_id", Message: "is required"})
+			}
+			groups = append(groups, &bankImportGroup{rows: []*importRow{row}})
+			continue
+		}
+
+		group, exists := byBankID[bankID]
+		if !exists {
+			group = &bankImportGroup{bankID: bankID}
+			byBankID[bankID] = group
+			groups = append(groups, group)
+		}
+		group.rows = append(group.rows, row)
+	}
+
+	return groups
+}
+
+// decodeImportRow decodes the values of a row column by column, so that every invalid value is reported
+func decodeImportRow(row *importRow) {
+	for _, column := range slices.Sorted(maps.Keys(row.fields)) {
+		if err := decodeImportField(column, row.fields[column], &row.record); err != nil {
+			row.errors = append(row.errors, models.FieldError{Field: column, Message: bankImportColumns[column].message()})
+		}
+	}
+
+	row.record.BankID = strings.TrimSpace(row.record.BankID)
+	row.record.Environment = strings.TrimSpace(row.record.Environment)
+	if row.record.Environment != "" && !isValidEnvironment(row.record.Environment) {
+		row.errors = append(row.errors, models.FieldError{
+			Field:   "environment",
+			Message: "must be one of " + strings.Join(validEnvironments, ", "),
+		})
+	}
+}
+
+// decodeImportFields decodes the values of a row into target, ignoring invalid values already reported
+func decodeImportFields(fields map[string]json.RawMessage, target any) {
+	for column, value := range fields {
+		_ = decodeImportField(column, value, target)
+	}
+}
+
+func decodeImportField(column string, value json.RawMessage, target any) error {
+	object, err := json.Marshal(map[string]json.RawMessage{column: value})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(object, target)
+}
+
+// readCSVImportRows reads a CSV file whose header uses the export column names
+func readCSVImportRows(r io.Reader) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid file: missing header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid file: %w", err)
+	}
+
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\uFEFF")
+		}
+		if _, known := bankImportColumns[column]; !known {
+			return nil, fmt.Errorf("invalid file: unknown column %q", column)
+		}
+		if slices.Contains(header[:i], column) {
+			return nil, fmt.Errorf("invalid file: duplicate column %q", column)
+		}
+		header[i] = column
+	}
+	if !slices.Contains(header, "bank_id") {
+		return nil, errors.New("invalid file: missing bank_id column")
+	}
+
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid file: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &importRow{line: line, fields: make(map[string]json.RawMessage)}
+		rows = append(rows, row)
+
+		if len(record) != len(header) {
+			row.errors = append(row.errors, models.FieldError{
+				Message: fmt.Sprintf("expected %d columns, got %d", len(header), len(record)),
+			})
+			continue
+		}
+
+		for i, cell := range record {
+			column := header[i]
+			kind := bankImportColumns[column]
+			cell = strings.TrimSpace(cell)
+			if cell == "" || kind == importIgnored {
+				continue
+			}
+
+			value, err := csvImportValue(kind, cell)
+			if err != nil {
+				row.errors = append(row.errors, models.FieldError{Field: column, Message: kind.message()})
+				continue
+			}
+			row.fields[column] = value
+		}
+
+		if len(rows) > MaxImportRows {
+			break
+		}
+	}
+
+	return rows, nil
+}
+
+// csvImportValue converts a CSV cell into the JSON value of its column
+func csvImportValue(kind importColumnKind, cell string) (json.RawMessage, error) {
+	switch kind {
+	case importBool:
+		value, err := strconv.ParseBool(cell)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	case importInteger:
+		value, err := strconv.ParseInt(cell, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	case importList:
+		items := []string{}
+		for item := range strings.SplitSeq(cell, exportListSeparator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return json.Marshal(items)
+	case importObject:
+		var object map[string]any
+		if err := json.Unmarshal([]byte(cell), &object); err != nil || object == nil {
+			return nil, errors.New("not a JSON object")
+		}
+		return json.RawMessage(cell), nil
+	default:
+		return json.Marshal(cell)
+	}
+}
+
+// readNDJSONImportRows reads one JSON object per line, skipping blank lines
+func readNDJSONImportRows(r io.Reader) ([]*importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	var rows []*importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := &importRow{line: line, fields: make(map[string]json.RawMessage)}
+		rows = append(rows, row)
+		if len(rows) > MaxImportRows {
+			break
+		}
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			row.errors = append(row.errors, models.FieldError{Message: "invalid JSON object: " + err.Error()})
+			continue
+		}
+
+		for column, value := range object {
+			kind, known := bankImportColumns[column]
+			if !known {
+				row.errors = append(row.errors, models.FieldError{Field: column, Message: "unknown field"})
+				continue
+			}
+			if kind == importIgnored || string(value) == "null" {
+				continue
+			}
+			row.fields[column] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid file: %w", err)
+	}
+
+	return rows, nil
+}
+
+// buildImportReport lists the outcome of every row in file order
+func buildImportReport(groups []*bankImportGroup, options *ImportBanksOptions) *models.BankImportReport {
+	report := &models.BankImportReport{
+		Mode:   options.Mode,
+		DryRun: options.DryRun,
+		Atomic: options.Atomic,
+		Rows:   []models.BankImportRowResult{},
+	}
+
+	for _, group := range groups {
+		groupFailed := group.failed()
+
+		for _, row := range group.rows {
+			result := models.BankImportRowResult{
+				Row:         row.line,
+				BankID:      group.bankID,
+				Environment: row.record.Environment,
+				Action:      group.action,
+				Success:     !groupFailed,
+				Errors:      row.errors,
+			}
+			if groupFailed && len(row.errors) == 0 {
+				result.Errors = []models.FieldError{{
+					Message: fmt.Sprintf("not applied: another row of bank %s failed", group.bankID),
+				}}
+			}
+			report.Rows = append(report.Rows, result)
+		}
+
+		if !groupFailed && !options.DryRun {
+			report.Applied = true
+		}
+	}
+
+	slices.SortFunc(report.Rows, func(a, b models.BankImportRowResult) int {
+		return cmp.Compare(a.Row, b.Row)
+	})
+
+	report.Total = len(report.Rows)
+	for _, row := range report.Rows {
+		if row.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+
+	return report
+}
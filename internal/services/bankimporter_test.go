@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+const importCSVHeader = "bank_id,name,bank_codes,api,api_version,aspsp,country,bank_group_id,environment,enabled,instant_payments_limit\n"
+
+func newImporterWithMocks() (*BankImporterService, *MockBankWriter, *MockBankRepository) {
+	mockWriter := new(MockBankWriter)
+	mockRepo := new(MockBankRepository)
+	return NewBankImporterService(mockWriter, mockRepo), mockWriter, mockRepo
+}
+
+func findImportRow(t *testing.T, report *models.BankImportReport, line int) models.BankImportRowResult {
+	t.Helper()
+	for _, row := range report.Rows {
+		if row.Row == line {
+			return row
+		}
+	}
+	require.Failf(t, "row not found", "line %d", line)
+	return models.BankImportRowResult{}
+}
+
+func TestBankImporterService_ImportBanks_CSVCreateOnly(t *testing.T) {
+	service, mockWriter, mockRepo := newImporterWithMocks()
+
+	file := importCSVHeader +
+		"BPT0033,Millennium BCP,0033;0034,berlin_group,1.3.6,millennium,PT,,production,true,15000\n" +
+		"BPT0033,Millennium BCP,0033;0034,berlin_group,1.3.6,millennium,PT,,sandbox,false,\n" +
+		"BPT0035,Caixa Geral,0035,berlin_group,1.3.6,cgd,PT,,,,\n"
+
+	mockRepo.On("GetBanksByIDs", mock.Anything, []string{"BPT0033", "BPT0035"}).Return([]models.Bank{}, nil)
+	mockWriter.On("ImportBanks", mock.Anything, mock.MatchedBy(func(operations []*repository.BankImportOperation) bool {
+		if len(operations) != 2 {
+			return false
+		}
+		first, second := operations[0], operations[1]
+		return first.Create && !first.ReplaceConfigs &&
+			first.Bank.BankID == "BPT0033" &&
+			assert.ObjectsAreEqual([]string{"0033", "0034"}, first.Bank.BankCodes) &&
+			len(first.Configs) == 2 &&
+			first.Configs[0].Environment == models.EnvironmentProduction &&
+			first.Configs[0].Enabled && *first.Configs[0].InstantPaymentsLimit == 15000 &&
+			first.Configs[1].Environment == models.EnvironmentSandbox && !first.Configs[1].Enabled &&
+			second.Create && second.Bank.BankID == "BPT0035" && len(second.Configs) == 0
+	}), repository.BankImportOptions{}).Return([]error{nil, nil}, nil)
+
+	report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{Format: ExportFormatCSV})
+
+	require.NoError(t, err)
+	assert.Equal(t, ImportModeCreateOnly, report.Mode)
+	assert.True(t, report.Applied)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 3, report.Succeeded)
+	assert.Zero(t, report.Failed)
+
+	row := findImportRow(t, report, 3)
+	assert.Equal(t, "BPT0033", row.BankID)
+	assert.Equal(t, "sandbox", row.Environment)
+	assert.Equal(t, models.ImportActionCreate, row.Action)
+	assert.True(t, row.Success)
+
+	mockWriter.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankImporterService_ImportBanks_FieldErrors(t *testing.T) {
+	service, mockWriter, mockRepo := newImporterWithMocks()
+
+	file := importCSVHeader +
+		"BPT0033,Millennium BCP,0033,berlin_group,1.3.6,millennium,PT,,production,yes,\n" +
+		"BPT0035,,0035,berlin_group,1.3.6,cgd,PT,,,,\n" +
+		"BPT0036,Novo Banco,0036,berlin_group,1.3.6,novo,PT,not-a-uuid,,,\n" +
+		"BPT0037,Montepio,0037,berlin_group,1.3.6,montepio,PT,,staging,,\n" +
+		",Orphan,0038,berlin_group,1.3.6,orphan,PT,,,,\n" +
+		"BPT0039,Santander Totta,0018,berlin_group,1.3.6,santander,PT,,,,\n"
+
+	mockRepo.On("GetBanksByIDs", mock.Anything, mock.Anything).Return([]models.Bank{}, nil)
+	mockWriter.On("ImportBanks", mock.Anything, mock.MatchedBy(func(operations []*repository.BankImportOperation) bool {
+		return len(operations) == 1 && operations[0].Bank.BankID == "BPT0039"
+	}), mock.Anything).Return([]error{nil}, nil)
+
+	report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{Format: ExportFormatCSV})
+
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, 5, report.Failed)
+
+	assert.Equal(t, []models.FieldError{{Field: "enabled", Message: "must be a boolean"}}, findImportRow(t, report, 2).Errors)
+	assert.Equal(t, []models.FieldError{{Field: "name", Message: "is required"}}, findImportRow(t, report, 3).Errors)
+
+	groupErrors := findImportRow(t, report, 4).Errors
+	require.Len(t, groupErrors, 1)
+	assert.Equal(t, "bank_group_id", groupErrors[0].Field)
+	assert.Contains(t, groupErrors[0].Message, "invalid bank_group_id format")
+
+	environmentErrors := findImportRow(t, report, 5).Errors
+	require.Len(t, environmentErrors, 1)
+	assert.Equal(t, "environment", environmentErrors[0].Field)
+
+	assert.Equal(t, []models.FieldError{{Field: "bank_id", Message: "is required"}}, findImportRow(t, report, 6).Errors)
+	assert.True(t, findImportRow(t, report, 7).Success)
+
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankImporterService_ImportBanks_ExistingBanks(t *testing.T) {
+	realName := "Banco Comercial Português"
+	existing := models.Bank{
+		BankID:     "BPT0033",
+		Name:       "Millennium BCP",
+		RealName:   &realName,
+		BankCodes:  []string{"0033"},
+		API:        "berlin_group",
+		APIVersion: "1.3.6",
+		ASPSP:      "millennium",
+		Country:    "PT",
+	}
+
+	t.Run("create_only rejects existing banks", func(t *testing.T) {
+		service, mockWriter, mockRepo := newImporterWithMocks()
+		mockRepo.On("GetBanksByIDs", mock.Anything, []string{"BPT0033"}).Return([]models.Bank{existing}, nil)
+
+		file := importCSVHeader + "BPT0033,Millennium,0033,berlin_group,1.3.6,millennium,PT,,,,\n"
+		report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{Format: ExportFormatCSV})
+
+		require.NoError(t, err)
+		assert.False(t, report.Applied)
+		assert.Equal(t, []models.FieldError{{Field: "bank_id", Message: "bank already exists"}}, report.Rows[0].Errors)
+		mockWriter.AssertNotCalled(t, "ImportBanks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("upsert merges the given fields and environments", func(t *testing.T) {
+		service, mockWriter, mockRepo := newImporterWithMocks()
+		mockRepo.On("GetBanksByIDs", mock.Anything, []string{"BPT0033"}).Return([]models.Bank{existing}, nil)
+		mockWriter.On("ImportBanks", mock.Anything, mock.MatchedBy(func(operations []*repository.BankImportOperation) bool {
+			operation := operations[0]
+			return !operation.Create && !operation.ReplaceConfigs &&
+				operation.Bank.Name == "Millennium" &&
+				operation.Bank.RealName != nil && *operation.Bank.RealName == realName &&
+				len(operation.Configs) == 1 && operation.Configs[0].Environment == models.EnvironmentUAT
+		}), mock.Anything).Return([]error{nil}, nil)
+
+		file := "bank_id,name,environment,enabled\nBPT0033,Millennium,uat,true\n"
+		report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{
+			Format: ExportFormatCSV,
+			Mode:   ImportModeUpsert,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ImportActionUpdate, report.Rows[0].Action)
+		assert.True(t, report.Rows[0].Success)
+		mockWriter.AssertExpectations(t)
+	})
+
+	t.Run("replace requires every field and replaces environments", func(t *testing.T) {
+		service, mockWriter, mockRepo := newImporterWithMocks()
+		mockRepo.On("GetBanksByIDs", mock.Anything, mock.Anything).Return([]models.Bank{existing}, nil)
+		mockWriter.On("ImportBanks", mock.Anything, mock.MatchedBy(func(operations []*repository.BankImportOperation) bool {
+			operation := operations[0]
+			return !operation.Create && operation.ReplaceConfigs && operation.Bank.RealName == nil
+		}), mock.Anything).Return([]error{nil}, nil)
+
+		file := importCSVHeader + "BPT0033,Millennium,0033,berlin_group,1.3.6,millennium,PT,,production,true,\n"
+		report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{
+			Format: ExportFormatCSV,
+			Mode:   ImportModeReplace,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ImportActionReplace, report.Rows[0].Action)
+		assert.True(t, report.Rows[0].Success)
+		mockWriter.AssertExpectations(t)
+	})
+}
+
+func TestBankImporterService_ImportBanks_Atomic(t *testing.T) {
+	file := importCSVHeader +
+		"BPT0033,Millennium BCP,0033,berlin_group,1.3.6,millennium,PT,,,,\n" +
+		"BPT0035,Caixa Geral,0035,berlin_group,1.3.6,cgd,PT,,,,\n"
+
+	t.Run("invalid rows prevent any write", func(t *testing.T) {
+		service, mockWriter, mockRepo := newImporterWithMocks()
+		mockRepo.On("GetBanksByIDs", mock.Anything, mock.Anything).Return([]models.Bank{}, nil)
+
+		invalid := file + "BPT0036,,0036,berlin_group,1.3.6,novo,PT,,,,\n"
+		report, err := service.ImportBanks(context.Background(), strings.NewReader(invalid), &ImportBanksOptions{
+			Format: ExportFormatCSV,
+			Atomic: true,
+		})
+
+		require.NoError(t, err)
+		assert.False(t, report.Applied)
+		assert.Equal(t, 3, report.Failed)
+		assert.Contains(t, report.Rows[0].Errors[0].Message, "not applied")
+		mockWriter.AssertNotCalled(t, "ImportBanks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("write failures roll back every bank", func(t *testing.T) {
+		service, mockWriter, mockRepo := newImporterWithMocks()
+		mockRepo.On("GetBanksByIDs", mock.Anything, mock.Anything).Return([]models.Bank{}, nil)
+		mockWriter.On("ImportBanks", mock.Anything, mock.Anything, repository.BankImportOptions{Atomic: true}).
+			Return([]error{nil, errors.New("failed to create bank: duplicate key")}, nil)
+
+		report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{
+			Format: ExportFormatCSV,
+			Atomic: true,
+		})
+
+		require.NoError(t, err)
+		assert.False(t, report.Applied)
+		assert.Equal(t, 2, report.Failed)
+		assert.Contains(t, report.Rows[0].Errors[0].Message, "not applied")
+		assert.Contains(t, report.Rows[1].Errors[0].Message, "duplicate key")
+	})
+}
+
+func TestBankImporterService_ImportBanks_DryRunNDJSON(t *testing.T) {
+	service, mockWriter, mockRepo := newImporterWithMocks()
+
+	groupID := uuid.New()
+	file := `{"bank_id":"BPT0033","name":"Millennium BCP","bank_codes":["0033"],"api":"berlin_group","api_version":"1.3.6","aspsp":"millennium","country":"PT","bank_group_id":"` + groupID.String() + `","keywords":{"alias":"bcp"},"environment":"production","enabled":true,"created_at":"2025-01-15T10:30:00Z"}
+
+{"bank_id":"BPT0035","name":"Caixa Geral","bank_codes":"0035","api":"berlin_group","api_version":"1.3.6","aspsp":"cgd","country":"PT","color":"blue"}
+{"bank_id":
+`
+
+	mockRepo.On("GetBanksByIDs", mock.Anything, mock.Anything).Return([]models.Bank{}, nil)
+	mockWriter.On("ImportBanks", mock.Anything, mock.MatchedBy(func(operations []*repository.BankImportOperation) bool {
+		return len(operations) == 1 &&
+			*operations[0].Bank.BankGroupID == groupID &&
+			operations[0].Bank.Keywords["alias"] == "bcp"
+	}), repository.BankImportOptions{DryRun: true}).Return([]error{nil}, nil)
+
+	report, err := service.ImportBanks(context.Background(), strings.NewReader(file), &ImportBanksOptions{
+		Format: ExportFormatNDJSON,
+		DryRun: true,
+	})
+
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.True(t, report.DryRun)
+	assert.True(t, findImportRow(t, report, 1).Success)
+
+	assert.ElementsMatch(t, []models.FieldError{
+		{Field: "bank_codes", Message: "must be a list of strings"},
+		{Field: "color", Message: "unknown field"},
+	}, findImportRow(t, report, 3).Errors)
+
+	malformed := findImportRow(t, report, 4)
+	require.Len(t, malformed.Errors, 1)
+	assert.Contains(t, malformed.Errors[0].Message, "invalid JSON object")
+
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankImporterService_ImportBanks_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		options       *ImportBanksOptions
+		expectedError string
+	}{
+		{
+			name:          "unknown mode",
+			file:          importCSVHeader,
+			options:       &ImportBanksOptions{Format: ExportFormatCSV, Mode: "merge"},
+			expectedError: "invalid mode: merge",
+		},
+		{
+			name:          "unknown format",
+			file:          importCSVHeader,
+			options:       &ImportBanksOptions{Format: "xlsx"},
+			expectedError: "invalid format: xlsx",
+		},
+		{
+			name:          "unknown column",
+			file:          "bank_id,colour\nBPT0033,blue\n",
+			options:       &ImportBanksOptions{Format: ExportFormatCSV},
+			expectedError: `invalid file: unknown column "colour"`,
+		},
+		{
+			name:          "missing bank_id column",
+			file:          "name\nMillennium\n",
+			options:       &ImportBanksOptions{Format: ExportFormatCSV},
+			expectedError: "invalid file: missing bank_id column",
+		},
+		{
+			name:          "no data rows",
+			file:          importCSVHeader,
+			options:       &ImportBanksOptions{Format: ExportFormatCSV},
+			expectedError: "invalid file: no data rows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockWriter, mockRepo := newImporterWithMocks()
+
+			report, err := service.ImportBanks(context.Background(), strings.NewReader(tt.file), tt.options)
+
+			require.Error(t, err)
+			assert.Nil(t, report)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			mockRepo.AssertNotCalled(t, "GetBanksByIDs", mock.Anything, mock.Anything)
+			mockWriter.AssertNotCalled(t, "ImportBanks", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankImporterService_ImportBanks_ExportRoundTrip(t *testing.T) {
+	service, mockWriter, mockRepo := newImporterWithMocks()
+
+	var file strings.Builder
+	writer, err := NewBankExportWriter(ExportFormatCSV, &file)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteHeader())
+	bank := exportTestBank()
+	bank.API = "berlin_group"
+	bank.APIVersion = "1.3.6"
+	bank.ASPSP = "caixabank"
+	require.NoError(t, writer.WriteBank(bank))
+	require.NoError(t, writer.Flush())
+
+	mockRepo.On("GetBanksByIDs", mock.Anything, []string{"BES2100"}).Return([]models.Bank{}, nil)
+	mockWriter.On("ImportBanks", mock.Anything, mock.MatchedBy(func(operations []*repository.BankImportOperation) bool {
+		operation := operations[0]
+		return operation.Bank.Name == "CaixaBank, S.A." &&
+			*operation.Bank.BIC == "CAIXESBBXXX" &&
+			len(operation.Configs) == 2 &&
+			assert.ObjectsAreEqual([]string{"ACSC", "ACCP"}, operation.Configs[0].OkStatusCodesSimplePayment)
+	}), mock.Anything).Return([]error{nil}, nil)
+
+	report, err := service.ImportBanks(context.Background(), strings.NewReader(file.String()), &ImportBanksOptions{Format: ExportFormatCSV})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Succeeded)
+	mockWriter.AssertExpectations(t)
+}
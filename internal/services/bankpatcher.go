@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// BankPatcher applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents to banks
+type BankPatcher interface {
+	PatchBank(ctx context.Context, bankID, mediaType string, patch []byte, precondition VersionPrecondition) (*models.Bank, error)
+}
+
+type BankPatcherService struct {
+	writer repository.BankWriter
+	reader repository.BankRepository
+}
+
+func NewBankPatcherService(writer repository.BankWriter, reader repository.BankRepository) *BankPatcherService {
+	return &BankPatcherService{
+		writer: writer,
+		reader: reader,
+	}
+}
+
+// PatchBank applies the patch to the stored bank, validates the result and saves it. Unlike
+// UpdateBank, a patch can set nullable fields such as bic or logo_url to null. Environment
+// configs are not part of the patched document. The write only succeeds if the bank is still at the
+// version that was read, which must also satisfy the precondition.
+func (s *BankPatcherService) PatchBank(ctx context.Context, bankID, mediaType string, patch []byte, precondition VersionPrecondition) (*models.Bank, error) {
+	existing, err := s.getBank(ctx, bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVersion(precondition, "bank", existing.Version); err != nil {
+		return nil, err
+	}
+
+	patched, err := applyPatch(existing, mediaType, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fieldErrorsToError(validatePatchedBank(existing, patched)); err != nil {
+		return nil, err
+	}
+
+	if err := s.writer.UpdateBank(ctx, patched); err != nil {
+		return nil, fmt.Errorf("failed to update bank: %w", err)
+	}
+
+	// Read the bank back for the timestamps set by the database
+	return s.getBank(ctx, bankID)
+}
+
+func (s *BankPatcherService) getBank(ctx context.Context, bankID string) (*models.Bank, error) {
+	bank, err := s.reader.GetBankByID(ctx, bankID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("bank not found")
+		}
+		return nil, fmt.Errorf("failed to get bank: %w", err)
+	}
+	return bank, nil
+}
+
+// validatePatchedBank applies the rules of CreateBankRequest to a patched bank and rejects
+// changes to the fields managed by the service
+func validatePatchedBank(existing, patched *models.Bank) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if patched.BankID != existing.BankID {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "bank_id", Message: "is read-only"})
+	}
+	if patched.Version != existing.Version {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "version", Message: "is read-only"})
+	}
+	if !patched.CreatedAt.Equal(existing.CreatedAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "created_at", Message: "is read-only"})
+	}
+	if !patched.UpdatedAt.Equal(existing.UpdatedAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "updated_at", Message: "is read-only"})
+	}
+
+	required := []struct {
+		field string
+		value string
+	}{
+		{"name", patched.Name},
+		{"api", patched.API},
+		{"api_version", patched.APIVersion},
+		{"aspsp", patched.ASPSP},
+		{"country", patched.Country},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: r.field, Message: "is required"})
+		}
+	}
+	if len(patched.BankCodes) == 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "bank_codes", Message: "is required"})
+	}
+
+	return fieldErrors
+}
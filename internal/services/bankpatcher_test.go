@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+func patchableBank() *models.Bank {
+	return &models.Bank{
+		BankID:     "BES0049",
+		Name:       "Santander",
+		BankCodes:  []string{"0049"},
+		BIC:        stringPtr("BSCHESMM"),
+		API:        "berlin_group",
+		APIVersion: "1.3.6",
+		ASPSP:      "santander",
+		Country:    "ES",
+		LogoURL:    stringPtr("https://example.com/santander.png"),
+		Keywords:   map[string]any{"alias": "santa"},
+		Version:    3,
+		CreatedAt:  time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC),
+		UpdatedAt:  time.Date(2024, 6, 7, 8, 9, 10, 0, time.UTC),
+	}
+}
+
+func TestBankPatcherService_PatchBank_MergePatch(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	mockReader := new(MockBankRepository)
+	service := NewBankPatcherService(mockWriter, mockReader)
+
+	stored := patchableBank()
+	saved := patchableBank()
+	saved.BIC = nil
+	saved.UpdatedAt = time.Now().UTC()
+
+	mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(stored, nil).Once()
+	mockWriter.On("UpdateBank", mock.Anything, mock.MatchedBy(func(bank *models.Bank) bool {
+		// Null clears the member; absent members keep their value
+		return bank.BIC == nil && bank.LogoURL != nil && bank.Name == "Banco Santander" &&
+			bank.Keywords["alias"] == "santa" && bank.Keywords["brand"] == "openbank"
+	})).Return(nil)
+	mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(saved, nil).Once()
+
+	patch := `{"bic":null,"name":"Banco Santander","keywords":{"brand":"openbank"}}`
+	result, err := service.PatchBank(context.Background(), "BES0049", jsonpatch.MergePatchMediaType, []byte(patch), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, saved, result)
+	mockWriter.AssertExpectations(t)
+	mockReader.AssertExpectations(t)
+}
+
+func TestBankPatcherService_PatchBank_JSONPatch(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	mockReader := new(MockBankRepository)
+	service := NewBankPatcherService(mockWriter, mockReader)
+
+	groupID := uuid.New()
+	mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(patchableBank(), nil)
+	mockWriter.On("UpdateBank", mock.Anything, mock.MatchedBy(func(bank *models.Bank) bool {
+		return bank.LogoURL == nil && bank.BankGroupID != nil && *bank.BankGroupID == groupID &&
+			len(bank.BankCodes) == 2 && bank.BankCodes[1] == "0030"
+	})).Return(nil)
+
+	patch := fmt.Sprintf(`[
+		{"op":"test","path":"/bic","value":"BSCHESMM"},
+		{"op":"replace","path":"/logo_url","value":null},
+		{"op":"replace","path":"/bank_group_id","value":%q},
+		{"op":"add","path":"/bank_codes/-","value":"0030"}
+	]`, groupID)
+	_, err := service.PatchBank(context.Background(), "BES0049", jsonpatch.JSONPatchMediaType, []byte(patch), nil)
+
+	require.NoError(t, err)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankPatcherService_PatchBank_Rejected(t *testing.T) {
+	tests := []struct {
+		name          string
+		mediaType     string
+		patch         string
+		expectedError string
+	}{
+		{"failed test operation", jsonpatch.JSONPatchMediaType, `[{"op":"test","path":"/name","value":"BBVA"},{"op":"remove","path":"/bic"}]`, "patch test failed"},
+		{"missing path", jsonpatch.JSONPatchMediaType, `[{"op":"remove","path":"/missing"}]`, "patch cannot be applied"},
+		{"malformed patch", jsonpatch.MergePatchMediaType, `{"name":`, "invalid patch"},
+		{"required field cleared", jsonpatch.MergePatchMediaType, `{"name":null,"bank_codes":[]}`, "invalid patch result: name is required, bank_codes is required"},
+		{"read-only field", jsonpatch.JSONPatchMediaType, `[{"op":"replace","path":"/bank_id","value":"BES9999"}]`, "invalid patch result: bank_id is read-only"},
+		{"read-only version", jsonpatch.MergePatchMediaType, `{"version":4}`, "invalid patch result: version is read-only"},
+		{"unknown field", jsonpatch.MergePatchMediaType, `{"nickname":"santi"}`, `invalid patch result: json: unknown field "nickname"`},
+		{"wrong type", jsonpatch.MergePatchMediaType, `{"auth_type_choice_required":"yes"}`, "invalid patch result"},
+		{"invalid group ID", jsonpatch.MergePatchMediaType, `{"bank_group_id":"group-1"}`, "invalid patch result"},
+		{"unsupported media type", "application/json", `{"name":"x"}`, "unsupported patch media type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWriter := new(MockBankWriter)
+			mockReader := new(MockBankRepository)
+			service := NewBankPatcherService(mockWriter, mockReader)
+
+			mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(patchableBank(), nil)
+
+			result, err := service.PatchBank(context.Background(), "BES0049", tt.mediaType, []byte(tt.patch), nil)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.Nil(t, result)
+			mockWriter.AssertNotCalled(t, "UpdateBank", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankPatcherService_PatchBank_Precondition(t *testing.T) {
+	tests := []struct {
+		name         string
+		precondition VersionPrecondition
+		matches      bool
+	}{
+		{"any version", nil, true},
+		{"current version", VersionPrecondition{3}, true},
+		{"one of several versions", VersionPrecondition{1, 3}, true},
+		{"stale version", VersionPrecondition{2}, false},
+		{"no usable entity tag", VersionPrecondition{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWriter := new(MockBankWriter)
+			mockReader := new(MockBankRepository)
+			service := NewBankPatcherService(mockWriter, mockReader)
+
+			mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(patchableBank(), nil)
+			mockWriter.On("UpdateBank", mock.Anything, mock.MatchedBy(func(bank *models.Bank) bool {
+				// The writer only applies the update while the bank is at the version that was read
+				return bank.Version == 3
+			})).Return(nil)
+
+			_, err := service.PatchBank(context.Background(), "BES0049", jsonpatch.MergePatchMediaType,
+				[]byte(`{"name":"Banco Santander"}`), tt.precondition)
+
+			if tt.matches {
+				require.NoError(t, err)
+				mockWriter.AssertExpectations(t)
+				return
+			}
+			require.ErrorIs(t, err, repository.ErrVersionConflict)
+			assert.Contains(t, err.Error(), "bank is at version 3")
+			mockWriter.AssertNotCalled(t, "UpdateBank", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankPatcherService_PatchBank_NotFound(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	mockReader := new(MockBankRepository)
+	service := NewBankPatcherService(mockWriter, mockReader)
+
+	mockReader.On("GetBankByID", mock.Anything, "missing").Return(nil, fmt.Errorf("failed to get bank by ID: %w", pgx.ErrNoRows))
+
+	result, err := service.PatchBank(context.Background(), "missing", jsonpatch.MergePatchMediaType, []byte(`{}`), nil)
+
+	require.Error(t, err)
+	assert.Equal(t, "bank not found", err.Error())
+	assert.Nil(t, result)
+}
+
+func TestBankPatcherService_PatchBank_WriterError(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	mockReader := new(MockBankRepository)
+	service := NewBankPatcherService(mockWriter, mockReader)
+
+	mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(patchableBank(), nil)
+	mockWriter.On("UpdateBank", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+	result, err := service.PatchBank(context.Background(), "BES0049", jsonpatch.MergePatchMediaType, []byte(`{"name":"Banco Santander"}`), nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update bank")
+	assert.Nil(t, result)
+}
+
+func TestBankGroupPatcherService_PatchBankGroup(t *testing.T) {
+	mockWriter := new(MockBankGroupWriter)
+	mockReader := new(MockBankGroupRepository)
+	service := NewBankGroupPatcherService(mockWriter, mockReader)
+
+	groupID := uuid.New()
+	stored := &models.BankGroup{
+		GroupID:     groupID,
+		Name:        "Santander Group",
+		Description: stringPtr("Spanish banking group"),
+		Website:     stringPtr("https://santander.com"),
+		CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	mockReader.On("GetBankGroupByID", mock.Anything, groupID).Return(stored, nil)
+	mockWriter.On("UpdateBankGroup", mock.Anything, mock.MatchedBy(func(group *models.BankGroup) bool {
+		return group.Name == "Grupo Santander" && group.Website == nil && group.Description != nil
+	})).Return(nil)
+
+	result, err := service.PatchBankGroup(context.Background(), groupID.String(), jsonpatch.MergePatchMediaType,
+		[]byte(`{"name":"  Grupo Santander ","website":null}`), nil)
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankGroupPatcherService_PatchBankGroup_Rejected(t *testing.T) {
+	groupID := uuid.New()
+
+	tests := []struct {
+		name          string
+		groupID       string
+		patch         string
+		expectedError string
+	}{
+		{"invalid group ID", "not-a-uuid", `[]`, "invalid group_id"},
+		{"empty name", groupID.String(), `[{"op":"replace","path":"/name","value":" "}]`, "invalid patch result: name is required"},
+		{"read-only group ID", groupID.String(), `[{"op":"replace","path":"/group_id","value":"` + uuid.NewString() + `"}]`, "group_id is read-only"},
+		{"failed test operation", groupID.String(), `[{"op":"test","path":"/website","value":"https://bbva.com"}]`, "patch test failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWriter := new(MockBankGroupWriter)
+			mockReader := new(MockBankGroupRepository)
+			service := NewBankGroupPatcherService(mockWriter, mockReader)
+
+			mockReader.On("GetBankGroupByID", mock.Anything, groupID).Return(&models.BankGroup{GroupID: groupID, Name: "Group"}, nil)
+
+			result, err := service.PatchBankGroup(context.Background(), tt.groupID, jsonpatch.JSONPatchMediaType, []byte(tt.patch), nil)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.Nil(t, result)
+			mockWriter.AssertNotCalled(t, "UpdateBankGroup", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankGroupPatcherService_PatchBankGroup_NotFound(t *testing.T) {
+	mockWriter := new(MockBankGroupWriter)
+	mockReader := new(MockBankGroupRepository)
+	service := NewBankGroupPatcherService(mockWriter, mockReader)
+
+	groupID := uuid.New()
+	mockReader.On("GetBankGroupByID", mock.Anything, groupID).Return(nil, fmt.Errorf("bank group with ID '%s' not found", groupID))
+
+	result, err := service.PatchBankGroup(context.Background(), groupID.String(), jsonpatch.MergePatchMediaType, []byte(`{}`), nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Nil(t, result)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Relations that can be embedded in bank responses with the include parameter
+const (
+	IncludeEnvironmentConfigs = "environment_configs"
+	IncludeBankGroup          = "bank_group"
+)
+
+// bankFieldNames lists the JSON fields of a bank that can be requested in a sparse fieldset
+var bankFieldNames = []string{
+	"bank_id", "name", "bank_codes", "bic", "real_name", "api", "api_version", "aspsp",
+	"product_code", "country", "bank_group_id", "logo_url", "documentation", "keywords",
+	"attribute", "auth_type_choice_required", "version", "created_at", "updated_at",
+}
+
+var bankIncludeNames = []string{IncludeEnvironmentConfigs, IncludeBankGroup}
+
+// BankRepresentation selects the bank fields rendered in a response and the relations embedded in it.
+// The zero value renders every field without relations, which is the default bank representation.
+type BankRepresentation struct {
+	Fields  []string // Sparse fieldset; bank_id is always rendered
+	Include []string // Relations to embed: environment_configs, bank_group
+}
+
+// Validate checks that every requested field and relation exists
+func (r *BankRepresentation) Validate() error {
+	for _, field := range r.Fields {
+		if !slices.Contains(bankFieldNames, field) {
+			return fmt.Errorf("invalid fields parameter: unknown field %s (allowed: %s)", field, strings.Join(bankFieldNames, ", "))
+		}
+	}
+	for _, relation := range r.Include {
+		if !slices.Contains(bankIncludeNames, relation) {
+			return fmt.Errorf("invalid include parameter: unknown relation %s (allowed: %s)", relation, strings.Join(bankIncludeNames, ", "))
+		}
+	}
+	return nil
+}
+
+// IsDefault reports whether the representation is the full bank without relations
+func (r *BankRepresentation) IsDefault() bool {
+	return r == nil || (len(r.Fields) == 0 && len(r.Include) == 0)
+}
+
+// Includes reports whether relation has been requested
+func (r *BankRepresentation) Includes(relation string) bool {
+	return slices.Contains(r.Include, relation)
+}
+
+// project encodes v as a JSON object and keeps only the requested fields plus the always-present keys.
+// Values stay as raw JSON so nested documents are rendered exactly as in the full representation.
+func (r *BankRepresentation) project(v any, keep ...string) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bank: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode bank: %w", err)
+	}
+
+	projected := make(map[string]any, len(raw))
+	for key, value := range raw {
+		if len(r.Fields) == 0 || key == "bank_id" || slices.Contains(r.Fields, key) || slices.Contains(keep, key) {
+			projected[key] = value
+		}
+	}
+
+	return projected, nil
+}
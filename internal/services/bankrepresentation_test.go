@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankRepresentation_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		representation BankRepresentation
+		expectedError  string
+	}{
+		{name: "default", representation: BankRepresentation{}},
+		{name: "valid fields and includes", representation: BankRepresentation{
+			Fields:  []string{"bank_id", "name", "logo_url"},
+			Include: []string{IncludeEnvironmentConfigs, IncludeBankGroup},
+		}},
+		{name: "unknown field", representation: BankRepresentation{Fields: []string{"secret"}}, expectedError: "invalid fields parameter: unknown field secret"},
+		{name: "unknown relation", representation: BankRepresentation{Include: []string{"owner"}}, expectedError: "invalid include parameter: unknown relation owner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.representation.Validate()
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestBankRepresentation_IsDefault(t *testing.T) {
+	assert.True(t, (*BankRepresentation)(nil).IsDefault())
+	assert.True(t, (&BankRepresentation{}).IsDefault())
+	assert.False(t, (&BankRepresentation{Fields: []string{"name"}}).IsDefault())
+	assert.False(t, (&BankRepresentation{Include: []string{IncludeBankGroup}}).IsDefault())
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// BankReverter reads the revisions of banks and restores banks to them
+type BankReverter interface {
+	GetBankRevision(ctx context.Context, bankID string, revision int64) (*models.BankRevision, error)
+	RevertBank(ctx context.Context, bankID string, revision int64, precondition VersionPrecondition) (*models.BankWithEnvironments, error)
+}
+
+type BankReverterService struct {
+	writer    repository.BankWriter
+	reader    repository.BankRepository
+	revisions repository.BankRevisionRepository
+}
+
+func NewBankReverterService(writer repository.BankWriter, reader repository.BankRepository, revisions repository.BankRevisionRepository) *BankReverterService {
+	return &BankReverterService{
+		writer:    writer,
+		reader:    reader,
+		revisions: revisions,
+	}
+}
+
+// GetBankRevision returns a revision of a bank
+func (s *BankReverterService) GetBankRevision(ctx context.Context, bankID string, revision int64) (*models.BankRevision, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+	if revision < 1 {
+		return nil, errors.New("invalid request: revision must be a positive number")
+	}
+
+	return s.revisions.GetBankRevision(ctx, bankID, revision)
+}
+
+// RevertBank writes the bank and the full set of environment configs it had at a revision over the
+// current ones. The revert is a regular update of the current version, so it gets a revision of its own.
+func (s *BankReverterService) RevertBank(ctx context.Context, bankID string, revision int64, precondition VersionPrecondition) (*models.BankWithEnvironments, error) {
+	target, err := s.GetBankRevision(ctx, bankID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.reader.GetBankByID(ctx, target.BankID)
+	if err != nil {
+		return nil, fmt.Errorf("bank not found: %w", err)
+	}
+
+	if err := checkVersion(precondition, "bank", current.Version); err != nil {
+		return nil, err
+	}
+
+	bank := target.Bank.Bank
+	bank.Version = current.Version
+
+	environments := make([]string, 0, len(target.Bank.EnvironmentConfigs))
+	for environment := range target.Bank.EnvironmentConfigs {
+		environments = append(environments, environment)
+	}
+	slices.Sort(environments)
+
+	configs := make([]*models.BankEnvironmentConfig, 0, len(environments))
+	for _, environment := range environments {
+		configs = append(configs, target.Bank.EnvironmentConfigs[environment])
+	}
+
+	if err := s.writer.UpdateBankWithEnvironments(ctx, &bank, configs); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && strings.Contains(pgErr.ConstraintName, "bank_group") {
+			return nil, fmt.Errorf("cannot revert: the bank group of revision %d no longer exists", revision)
+		}
+		return nil, fmt.Errorf("failed to revert bank: %w", err)
+	}
+
+	return &models.BankWithEnvironments{
+		Bank:               bank,
+		EnvironmentConfigs: target.Bank.EnvironmentConfigs,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// MockBankRevisionRepository implements the BankRevisionRepository interface for testing
+type MockBankRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockBankRevisionRepository) GetBankRevision(ctx context.Context, bankID string, revision int64) (*models.BankRevision, error) {
+	args := m.Called(ctx, bankID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BankRevision), args.Error(1)
+}
+
+func revisionFixture() *models.BankRevision {
+	return &models.BankRevision{
+		BankID:   "BES0049",
+		Revision: 3,
+		Bank: models.BankWithEnvironments{
+			Bank: models.Bank{BankID: "BES0049", Name: "Santander", Version: 3},
+			EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+				"uat":        {BankID: "BES0049", Environment: models.EnvironmentUAT, Enabled: true},
+				"production": {BankID: "BES0049", Environment: models.EnvironmentProduction, Enabled: true},
+			},
+		},
+	}
+}
+
+func TestBankReverterService_RevertBank(t *testing.T) {
+	mockWriter := new(MockBankWriter)
+	mockReader := new(MockBankRepository)
+	mockRevisions := new(MockBankRevisionRepository)
+	service := NewBankReverterService(mockWriter, mockReader, mockRevisions)
+
+	mockRevisions.On("GetBankRevision", mock.Anything, "BES0049", int64(3)).Return(revisionFixture(), nil)
+	mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049", Name: "Santander SA", Version: 7}, nil)
+	mockWriter.On("UpdateBankWithEnvironments", mock.Anything,
+		mock.MatchedBy(func(bank *models.Bank) bool {
+			// The revert writes over the current version, not the one of the revision
+			return bank.Name == "Santander" && bank.Version == 7
+		}),
+		mock.MatchedBy(func(configs []*models.BankEnvironmentConfig) bool {
+			return len(configs) == 2 &&
+				configs[0].Environment == models.EnvironmentProduction &&
+				configs[1].Environment == models.EnvironmentUAT
+		}),
+	).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Bank).Version = 8
+	}).Return(nil)
+
+	bank, err := service.RevertBank(context.Background(), " BES0049 ", 3, VersionPrecondition{7})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Santander", bank.Name)
+	assert.Equal(t, int64(8), bank.Version)
+	assert.Len(t, bank.EnvironmentConfigs, 2)
+	mockWriter.AssertExpectations(t)
+}
+
+func TestBankReverterService_RevertBank_Errors(t *testing.T) {
+	groupMissing := &pgconn.PgError{Code: "23503", ConstraintName: "banks_bank_group_id_fkey"}
+
+	tests := []struct {
+		name         string
+		revision     int64
+		precondition VersionPrecondition
+		revisionErr  error
+		writerErr    error
+		expectedErr  string
+		conflict     bool
+	}{
+		{name: "non-positive revision", revision: 0, expectedErr: "invalid request"},
+		{name: "unknown revision", revision: 9, revisionErr: errors.New("revision 9 of bank 'BES0049' not found"), expectedErr: "revision 9 of bank 'BES0049' not found"},
+		{name: "stale If-Match", revision: 3, precondition: VersionPrecondition{6}, conflict: true},
+		{name: "deleted bank group", revision: 3, writerErr: groupMissing, expectedErr: "cannot revert"},
+		{name: "concurrent write", revision: 3, writerErr: repository.ErrVersionConflict, conflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWriter := new(MockBankWriter)
+			mockReader := new(MockBankRepository)
+			mockRevisions := new(MockBankRevisionRepository)
+			service := NewBankReverterService(mockWriter, mockReader, mockRevisions)
+
+			if tt.revisionErr != nil {
+				mockRevisions.On("GetBankRevision", mock.Anything, "BES0049", tt.revision).Return(nil, tt.revisionErr)
+			} else {
+				mockRevisions.On("GetBankRevision", mock.Anything, "BES0049", tt.revision).Return(revisionFixture(), nil)
+			}
+			mockReader.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049", Version: 7}, nil)
+			mockWriter.On("UpdateBankWithEnvironments", mock.Anything, mock.Anything, mock.Anything).Return(tt.writerErr)
+
+			_, err := service.RevertBank(context.Background(), "BES0049", tt.revision, tt.precondition)
+
+			require.Error(t, err)
+			if tt.conflict {
+				assert.ErrorIs(t, err, repository.ErrVersionConflict)
+				return
+			}
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
 	"github.com/wukong0111/go-banks/internal/models"
@@ -16,9 +19,35 @@ import (
 // BankService defines the interface for bank-related operations.
 type BankService interface {
 	GetBanks(ctx context.Context, filters *repository.BankFilters) ([]models.Bank, *models.Pagination, error)
+	GetBanksByCursor(ctx context.Context, filters *repository.BankFilters) ([]models.Bank, *models.CursorPagination, error)
+	ExportBanks(ctx context.Context, filters *repository.BankFilters, fn func(bank *models.BankWithEnvironments) error) error
 	GetBankDetails(ctx context.Context, bankID, environment string) (models.BankDetails, error)
+	GetBankGroup(ctx context.Context, groupID string) (*models.BankGroup, error)
+	LookupBanks(ctx context.Context, lookup *BankLookupRequest) ([]models.BankWithEnvironments, error)
+	BatchGetBanks(ctx context.Context, request *BatchGetBanksRequest) (*models.BankBatch, error)
+	RenderBanks(ctx context.Context, banks []models.Bank, environment string, representation *BankRepresentation) ([]map[string]any, error)
+	RenderBankDetails(ctx context.Context, details models.BankDetails, representation *BankRepresentation) (map[string]any, error)
 }
 
+// BankLookupRequest identifies banks either by BIC or by national bank code within a country
+type BankLookupRequest struct {
+	BIC     string
+	Code    string
+	Country string
+}
+
+// MaxBankIDsPerBatch caps the number of bank IDs of a single batch get request
+const MaxBankIDsPerBatch = 500
+
+// BatchGetBanksRequest lists the banks to fetch, optionally restricting their configs to one environment
+type BatchGetBanksRequest struct {
+	BankIDs     []string `json:"bank_ids" binding:"required"`
+	Environment string   `json:"environment"`
+}
+
+// bicPattern matches BIC8 and BIC11 codes (institution, country, location and optional branch)
+var bicPattern = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
 type bankService struct {
 	bankRepo repository.BankRepository
 }
@@ -33,10 +62,76 @@ func (s *bankService) GetBanks(ctx context.Context, filters *repository.BankFilt
 	// Apply business rules and validation
 	s.normalizeFilters(filters)
 
+	if err := s.validateFilters(filters); err != nil {
+		return nil, nil, err
+	}
+
+	if err := repository.ValidateBankSort(filters.Sort); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.resolveActiveMaintenance(ctx, filters); err != nil {
+		return nil, nil, err
+	}
+
 	// Delegate to repository
 	return s.bankRepo.GetBanks(ctx, filters)
 }
 
+func (s *bankService) GetBanksByCursor(ctx context.Context, filters *repository.BankFilters) ([]models.Bank, *models.CursorPagination, error) {
+	// Apply business rules and validation
+	s.normalizeFilters(filters)
+
+	if err := s.validateFilters(filters); err != nil {
+		return nil, nil, err
+	}
+
+	// Keyset pagination relies on the fixed (name, bank_id) ordering
+	if len(filters.Sort) > 0 {
+		return nil, nil, errors.New("invalid sort: custom sorting is not supported with cursor pagination")
+	}
+	// The same ordering would silently replace the relevance ranking of q
+	if filters.Query != "" {
+		return nil, nil, errors.New("invalid q: full-text search is not supported with cursor pagination")
+	}
+
+	// Reject malformed cursors before hitting the database
+	if filters.Cursor != "" {
+		if _, err := repository.DecodeBankCursor(filters.Cursor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := s.resolveActiveMaintenance(ctx, filters); err != nil {
+		return nil, nil, err
+	}
+
+	// Delegate to repository
+	return s.bankRepo.GetBanksByCursor(ctx, filters)
+}
+
+// ExportBanks streams every bank matching the filters to fn, with its environment configs.
+// Filters are validated like GetBanks; pagination parameters are ignored.
+func (s *bankService) ExportBanks(ctx context.Context, filters *repository.BankFilters, fn func(bank *models.BankWithEnvironments) error) error {
+	// Apply business rules and validation
+	s.normalizeFilters(filters)
+
+	if err := s.validateFilters(filters); err != nil {
+		return err
+	}
+
+	if err := repository.ValidateBankSort(filters.Sort); err != nil {
+		return err
+	}
+
+	if err := s.resolveActiveMaintenance(ctx, filters); err != nil {
+		return err
+	}
+
+	// Delegate to repository
+	return s.bankRepo.StreamBanks(ctx, filters, fn)
+}
+
 // normalizeFilters applies business rules to filter parameters
 func (s *bankService) normalizeFilters(filters *repository.BankFilters) {
 	const (
@@ -47,6 +142,15 @@ func (s *bankService) normalizeFilters(filters *repository.BankFilters) {
 		MinLimit           = 1
 	)
 
+	// Normalize search query - surrounding whitespace is not significant
+	filters.Query = strings.TrimSpace(filters.Query)
+
+	// Normalize multi-value filters - country codes are stored uppercase
+	for i := range filters.Countries {
+		filters.Countries[i] = strings.ToUpper(strings.TrimSpace(filters.Countries[i]))
+	}
+	filters.BankGroupID = strings.TrimSpace(filters.BankGroupID)
+
 	// Normalize environment - default to "all" if empty
 	if filters.Environment == "" {
 		filters.Environment = DefaultEnvironment
@@ -65,6 +169,63 @@ func (s *bankService) normalizeFilters(filters *repository.BankFilters) {
 	}
 }
 
+// validateFilters rejects filter values that cannot match any bank
+func (s *bankService) validateFilters(filters *repository.BankFilters) error {
+	if filters.BankGroupID != "" {
+		if _, err := uuid.Parse(filters.BankGroupID); err != nil {
+			return fmt.Errorf("invalid bank_group_id: %s must be a valid UUID", filters.BankGroupID)
+		}
+	}
+	if status := filters.EnvironmentState.EffectiveStatus; status != "" && !slices.Contains(effectiveStatusValues, status) {
+		return fmt.Errorf("invalid effective_status: %s (allowed: %s)", status, strings.Join(effectiveStatusValues, ", "))
+	}
+	return nil
+}
+
+// effectiveStatusValues lists the values of the effective_status filter
+var effectiveStatusValues = []string{models.EffectiveStatusAvailable, models.EffectiveStatusDegraded, models.EffectiveStatusBlocked}
+
+// resolveActiveMaintenance evaluates the maintenance windows in progress when the listing filters by
+// effective status, since recurring windows cannot be evaluated by the database
+func (s *bankService) resolveActiveMaintenance(ctx context.Context, filters *repository.BankFilters) error {
+	if filters.EnvironmentState.EffectiveStatus == "" {
+		return nil
+	}
+
+	environment := filters.Environment
+	if environment == "all" {
+		environment = ""
+	}
+
+	windows, err := s.bankRepo.GetMaintenanceWindows(ctx, "", environment)
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	filters.EnvironmentState.ActiveMaintenance = activeMaintenanceAt(windows, time.Now())
+	return nil
+}
+
+// GetBankGroup returns the bank group with the given ID, used to tell an unknown group apart
+// from a group without banks
+func (s *bankService) GetBankGroup(ctx context.Context, groupID string) (*models.BankGroup, error) {
+	id, err := uuid.Parse(strings.TrimSpace(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid group_id: %w", err)
+	}
+
+	groups, err := s.bankRepo.GetBankGroupsByIDs(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank group: %w", err)
+	}
+
+	group, exists := groups[id]
+	if !exists {
+		return nil, errors.New("bank group not found")
+	}
+
+	return group, nil
+}
+
 func (s *bankService) GetBankDetails(ctx context.Context, bankID, environment string) (models.BankDetails, error) {
 	// If specific environment is requested, validate it first
 	if environment != "" {
@@ -88,17 +249,24 @@ func (s *bankService) GetBankDetails(ctx context.Context, bankID, environment st
 		return nil, fmt.Errorf("failed to get environment configs: %w", err)
 	}
 
-	// If specific environment is requested
+	// If specific environment is requested, it must have a config
 	if environment != "" {
-
-		config, exists := envConfigs[environment]
-		if !exists {
+		if _, exists := envConfigs[environment]; !exists {
 			return nil, fmt.Errorf("environment configuration not found for %s", environment)
 		}
+	}
+
+	statuses, err := s.effectiveStatuses(ctx, bankID, environment, envConfigs)
+	if err != nil {
+		return nil, err
+	}
 
+	if environment != "" {
+		config := envConfigs[environment]
 		return &models.BankWithEnvironment{
 			Bank:              *bank,
 			EnvironmentConfig: config,
+			EffectiveStatus:   statuses[environment],
 		}, nil
 	}
 
@@ -106,11 +274,270 @@ func (s *bankService) GetBankDetails(ctx context.Context, bankID, environment st
 	return &models.BankWithEnvironments{
 		Bank:               *bank,
 		EnvironmentConfigs: envConfigs,
+		EffectiveStatuses:  statuses,
 	}, nil
 }
 
+// effectiveStatuses computes the effective status of every environment config of a bank at now
+func (s *bankService) effectiveStatuses(ctx context.Context, bankID, environment string, envConfigs map[string]*models.BankEnvironmentConfig) (map[string]*models.EffectiveStatus, error) {
+	windows, err := s.bankRepo.GetMaintenanceWindows(ctx, bankID, environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	windowsByEnvironment := make(map[string][]models.MaintenanceWindow)
+	for i := range windows {
+		env := string(windows[i].Environment)
+		windowsByEnvironment[env] = append(windowsByEnvironment[env], windows[i])
+	}
+
+	now := time.Now()
+	statuses := make(map[string]*models.EffectiveStatus, len(envConfigs))
+	for env, config := range envConfigs {
+		statuses[env] = effectiveStatusAt(config, windowsByEnvironment[env], now)
+	}
+	return statuses, nil
+}
+
+func (s *bankService) LookupBanks(ctx context.Context, lookup *BankLookupRequest) ([]models.BankWithEnvironments, error) {
+	bic := strings.ToUpper(strings.TrimSpace(lookup.BIC))
+	code := strings.TrimSpace(lookup.Code)
+	country := strings.ToUpper(strings.TrimSpace(lookup.Country))
+
+	var banks []models.Bank
+	var err error
+
+	switch {
+	case bic != "" && code != "":
+		return nil, errors.New("invalid lookup: bic and code cannot be combined")
+	case bic != "":
+		if !bicPattern.MatchString(bic) {
+			return nil, fmt.Errorf("invalid BIC format: %s", bic)
+		}
+		banks, err = s.bankRepo.GetBanksByBIC(ctx, bic)
+	case code != "":
+		if country == "" {
+			return nil, errors.New("invalid lookup: country is required for code lookup")
+		}
+		banks, err = s.bankRepo.GetBanksByCode(ctx, code, country)
+	default:
+		return nil, errors.New("invalid lookup: either bic or code is required")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup banks: %w", err)
+	}
+
+	if len(banks) == 0 {
+		return nil, errors.New("bank not found")
+	}
+
+	return s.attachEnvironmentConfigs(ctx, banks)
+}
+
+// BatchGetBanks fetches many banks with their environment configs using one query per table.
+// Duplicated IDs are fetched once and missing IDs are reported instead of failing the batch.
+func (s *bankService) BatchGetBanks(ctx context.Context, request *BatchGetBanksRequest) (*models.BankBatch, error) {
+	bankIDs := make([]string, 0, len(request.BankIDs))
+	for _, bankID := range request.BankIDs {
+		bankID = strings.TrimSpace(bankID)
+		if bankID != "" && !slices.Contains(bankIDs, bankID) {
+			bankIDs = append(bankIDs, bankID)
+		}
+	}
+
+	if len(bankIDs) == 0 {
+		return nil, errors.New("invalid request: at least one bank ID is required")
+	}
+	if len(bankIDs) > MaxBankIDsPerBatch {
+		return nil, fmt.Errorf("invalid request: at most %d bank IDs are allowed per request", MaxBankIDsPerBatch)
+	}
+	if request.Environment != "" && !s.isValidEnvironment(request.Environment) {
+		return nil, fmt.Errorf("invalid environment: %s", request.Environment)
+	}
+
+	banks, err := s.bankRepo.GetBanksByIDs(ctx, bankIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get banks: %w", err)
+	}
+
+	configsByBank, err := s.bankRepo.GetEnvironmentConfigsByBankIDs(ctx, bankIDs, request.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment configs: %w", err)
+	}
+
+	batch := &models.BankBatch{
+		Banks:    make(map[string]*models.BankWithEnvironments, len(banks)),
+		NotFound: []string{},
+	}
+
+	for i := range banks {
+		envConfigs := configsByBank[banks[i].BankID]
+		if envConfigs == nil {
+			envConfigs = make(map[string]*models.BankEnvironmentConfig)
+		}
+		batch.Banks[banks[i].BankID] = &models.BankWithEnvironments{
+			Bank:               banks[i],
+			EnvironmentConfigs: envConfigs,
+		}
+	}
+
+	for _, bankID := range bankIDs {
+		if _, found := batch.Banks[bankID]; !found {
+			batch.NotFound = append(batch.NotFound, bankID)
+		}
+	}
+
+	return batch, nil
+}
+
+// attachEnvironmentConfigs loads the environment configs of all banks at once and pairs them up
+func (s *bankService) attachEnvironmentConfigs(ctx context.Context, banks []models.Bank) ([]models.BankWithEnvironments, error) {
+	bankIDs := make([]string, 0, len(banks))
+	for i := range banks {
+		bankIDs = append(bankIDs, banks[i].BankID)
+	}
+
+	configsByBank, err := s.bankRepo.GetEnvironmentConfigsByBankIDs(ctx, bankIDs, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment configs: %w", err)
+	}
+
+	result := make([]models.BankWithEnvironments, 0, len(banks))
+	for i := range banks {
+		envConfigs := configsByBank[banks[i].BankID]
+		if envConfigs == nil {
+			envConfigs = make(map[string]*models.BankEnvironmentConfig)
+		}
+		result = append(result, models.BankWithEnvironments{
+			Bank:               banks[i],
+			EnvironmentConfigs: envConfigs,
+		})
+	}
+
+	return result, nil
+}
+
+// RenderBanks applies a sparse fieldset to banks and embeds the requested relations.
+// Relations are loaded with one query per relation for the whole page, whatever its size.
+// A specific environment restricts the embedded environment configs to that environment.
+func (s *bankService) RenderBanks(ctx context.Context, banks []models.Bank, environment string, representation *BankRepresentation) ([]map[string]any, error) {
+	if err := representation.Validate(); err != nil {
+		return nil, err
+	}
+
+	var configsByBank map[string]map[string]*models.BankEnvironmentConfig
+	if representation.Includes(IncludeEnvironmentConfigs) {
+		bankIDs := make([]string, 0, len(banks))
+		for i := range banks {
+			bankIDs = append(bankIDs, banks[i].BankID)
+		}
+
+		if environment == "all" {
+			environment = ""
+		}
+
+		var err error
+		configsByBank, err = s.bankRepo.GetEnvironmentConfigsByBankIDs(ctx, bankIDs, environment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environment configs: %w", err)
+		}
+	}
+
+	groups, err := s.loadBankGroups(ctx, representation, banks...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]any, 0, len(banks))
+	for i := range banks {
+		rendered, err := representation.project(&banks[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if configsByBank != nil {
+			envConfigs := configsByBank[banks[i].BankID]
+			if envConfigs == nil {
+				envConfigs = make(map[string]*models.BankEnvironmentConfig)
+			}
+			rendered[IncludeEnvironmentConfigs] = envConfigs
+		}
+		if groups != nil {
+			rendered[IncludeBankGroup] = bankGroupOf(&banks[i], groups)
+		}
+
+		result = append(result, rendered)
+	}
+
+	return result, nil
+}
+
+// RenderBankDetails applies a sparse fieldset to a bank details response and embeds the requested relations.
+// Environment configs and their effective statuses are always part of the details, so including the
+// configs has no additional effect.
+func (s *bankService) RenderBankDetails(ctx context.Context, details models.BankDetails, representation *BankRepresentation) (map[string]any, error) {
+	if err := representation.Validate(); err != nil {
+		return nil, err
+	}
+
+	rendered, err := representation.project(details, "environment_config", "environment_configs", "effective_status", "effective_statuses")
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.loadBankGroups(ctx, representation, *details.GetBank())
+	if err != nil {
+		return nil, err
+	}
+	if groups != nil {
+		rendered[IncludeBankGroup] = bankGroupOf(details.GetBank(), groups)
+	}
+
+	return rendered, nil
+}
+
+// loadBankGroups fetches the groups of banks at once when the bank_group relation is requested.
+// It returns nil when the relation is not requested.
+func (s *bankService) loadBankGroups(ctx context.Context, representation *BankRepresentation, banks ...models.Bank) (map[uuid.UUID]*models.BankGroup, error) {
+	if !representation.Includes(IncludeBankGroup) {
+		return nil, nil
+	}
+
+	groupIDs := make([]uuid.UUID, 0, len(banks))
+	for i := range banks {
+		if banks[i].BankGroupID != nil && !slices.Contains(groupIDs, *banks[i].BankGroupID) {
+			groupIDs = append(groupIDs, *banks[i].BankGroupID)
+		}
+	}
+
+	groups, err := s.bankRepo.GetBankGroupsByIDs(ctx, groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank groups: %w", err)
+	}
+	if groups == nil {
+		groups = make(map[uuid.UUID]*models.BankGroup)
+	}
+
+	return groups, nil
+}
+
+// bankGroupOf returns the loaded group of bank, or nil when the bank has no group
+func bankGroupOf(bank *models.Bank, groups map[uuid.UUID]*models.BankGroup) *models.BankGroup {
+	if bank.BankGroupID == nil {
+		return nil
+	}
+	return groups[*bank.BankGroupID]
+}
+
 // isValidEnvironment validates if the provided environment is valid
 func (s *bankService) isValidEnvironment(env string) bool {
-	validEnvironments := []string{"sandbox", "production", "uat", "test"}
+	return isValidEnvironment(env)
+}
+
+// validEnvironments mirrors the environment_type enum of bank_environment_configs
+var validEnvironments = []string{"sandbox", "production", "uat", "test"}
+
+func isValidEnvironment(env string) bool {
 	return slices.Contains(validEnvironments, env)
 }
@@ -2,9 +2,14 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -33,6 +38,21 @@ func (m *MockBankRepository) GetBanks(ctx context.Context, filters *repository.B
 	return banks, pagination, args.Error(2)
 }
 
+func (m *MockBankRepository) GetBanksByCursor(ctx context.Context, filters *repository.BankFilters) ([]models.Bank, *models.CursorPagination, error) {
+	args := m.Called(ctx, filters)
+	var banks []models.Bank
+	var pagination *models.CursorPagination
+
+	if args.Get(0) != nil {
+		banks = args.Get(0).([]models.Bank)
+	}
+	if args.Get(1) != nil {
+		pagination = args.Get(1).(*models.CursorPagination)
+	}
+
+	return banks, pagination, args.Error(2)
+}
+
 func (m *MockBankRepository) GetBankByID(ctx context.Context, bankID string) (*models.Bank, error) {
 	args := m.Called(ctx, bankID)
 	if args.Get(0) == nil {
@@ -41,6 +61,26 @@ func (m *MockBankRepository) GetBankByID(ctx context.Context, bankID string) (*m
 	return args.Get(0).(*models.Bank), args.Error(1)
 }
 
+func (m *MockBankRepository) StreamBanks(ctx context.Context, filters *repository.BankFilters, fn func(bank *models.BankWithEnvironments) error) error {
+	args := m.Called(ctx, filters, fn)
+	if banks, ok := args.Get(0).([]models.BankWithEnvironments); ok {
+		for i := range banks {
+			if err := fn(&banks[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockBankRepository) GetBanksByIDs(ctx context.Context, bankIDs []string) ([]models.Bank, error) {
+	args := m.Called(ctx, bankIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Bank), args.Error(1)
+}
+
 func (m *MockBankRepository) GetBankEnvironmentConfigs(ctx context.Context, bankID, environment string) (map[string]*models.BankEnvironmentConfig, error) {
 	args := m.Called(ctx, bankID, environment)
 	if args.Get(0) == nil {
@@ -49,6 +89,54 @@ func (m *MockBankRepository) GetBankEnvironmentConfigs(ctx context.Context, bank
 	return args.Get(0).(map[string]*models.BankEnvironmentConfig), args.Error(1)
 }
 
+func (m *MockBankRepository) GetEnvironmentConfigsByBankIDs(ctx context.Context, bankIDs []string, environment string) (map[string]map[string]*models.BankEnvironmentConfig, error) {
+	args := m.Called(ctx, bankIDs, environment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]map[string]*models.BankEnvironmentConfig), args.Error(1)
+}
+
+func (m *MockBankRepository) GetBanksByBIC(ctx context.Context, bic string) ([]models.Bank, error) {
+	args := m.Called(ctx, bic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Bank), args.Error(1)
+}
+
+func (m *MockBankRepository) GetBanksByCode(ctx context.Context, code, country string) ([]models.Bank, error) {
+	args := m.Called(ctx, code, country)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Bank), args.Error(1)
+}
+
+func (m *MockBankRepository) GetBanksByCodes(ctx context.Context, codes []string) ([]models.Bank, error) {
+	args := m.Called(ctx, codes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Bank), args.Error(1)
+}
+
+func (m *MockBankRepository) GetBankGroupsByIDs(ctx context.Context, groupIDs []uuid.UUID) (map[uuid.UUID]*models.BankGroup, error) {
+	args := m.Called(ctx, groupIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]*models.BankGroup), args.Error(1)
+}
+
+func (m *MockBankRepository) GetMaintenanceWindows(ctx context.Context, bankID, environment string) ([]models.MaintenanceWindow, error) {
+	args := m.Called(ctx, bankID, environment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MaintenanceWindow), args.Error(1)
+}
+
 func (m *MockBankRepository) GetAvailableFilters(ctx context.Context) (*models.BankFilters, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -189,7 +277,7 @@ func TestBankService_GetBanks_FilterValidation(t *testing.T) {
 	mockRepo.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
 		return filters.Name == "Test Bank" &&
 			filters.Environment == "production" &&
-			filters.Country == "ES"
+			slices.Equal(filters.Countries, []string{"ES"})
 	})).Return(expectedBanks, expectedPagination, nil)
 
 	// Create service with mock
@@ -199,7 +287,7 @@ func TestBankService_GetBanks_FilterValidation(t *testing.T) {
 	filters := &repository.BankFilters{
 		Name:        "Test Bank",
 		Environment: "production",
-		Country:     "ES",
+		Countries:   []string{"ES"},
 		Page:        1,
 		Limit:       20,
 	}
@@ -284,6 +372,167 @@ func TestBankService_GetBanks_MaxLimitEnforcement(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestBankService_GetBanks_TrimsSearchQuery(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return filters.Query == "caixa bank"
+	})).Return([]models.Bank{}, &models.Pagination{Page: 1, Limit: 20}, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	filters := &repository.BankFilters{
+		Query: "  caixa bank ",
+	}
+
+	// Call the method
+	_, _, err := service.GetBanks(context.Background(), filters)
+
+	// Assertions
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_GetBanks_NormalizesCountries(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return slices.Equal(filters.Countries, []string{"ES", "PT"})
+	})).Return([]models.Bank{}, &models.Pagination{Page: 1, Limit: 20}, nil)
+
+	service := NewBankService(mockRepo)
+
+	_, _, err := service.GetBanks(context.Background(), &repository.BankFilters{Countries: []string{"es", " pt "}})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_GetBanks_InvalidBankGroupID(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewBankService(mockRepo)
+
+	banks, pagination, err := service.GetBanks(context.Background(), &repository.BankFilters{BankGroupID: "not-a-uuid"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid bank_group_id")
+	assert.Nil(t, banks)
+	assert.Nil(t, pagination)
+	mockRepo.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+}
+
+func TestBankService_GetBanksByCursor(t *testing.T) {
+	cursor := repository.EncodeBankCursor(repository.BankCursor{Name: "Bank A", BankID: "bank-a"})
+	nextCursor := repository.EncodeBankCursor(repository.BankCursor{Name: "Bank B", BankID: "bank-b"})
+
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByCursor", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return filters.Cursor == cursor && filters.Limit == 100
+	})).Return([]models.Bank{{BankID: "bank-b", Name: "Bank B"}}, &models.CursorPagination{
+		Limit:      100,
+		NextCursor: &nextCursor,
+		HasMore:    true,
+	}, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	filters := &repository.BankFilters{
+		Cursor: cursor,
+		Limit:  1000, // This should be capped to 100 by business rules
+	}
+
+	// Call the method
+	banks, pagination, err := service.GetBanksByCursor(context.Background(), filters)
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Len(t, banks, 1)
+	require.NotNil(t, pagination.NextCursor)
+	assert.Equal(t, nextCursor, *pagination.NextCursor)
+	assert.True(t, pagination.HasMore)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_GetBanksByCursor_InvalidCursor(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	filters := &repository.BankFilters{
+		Cursor: "%%%not-base64",
+	}
+
+	// Call the method
+	banks, pagination, err := service.GetBanksByCursor(context.Background(), filters)
+
+	// Assertions
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cursor")
+	assert.Nil(t, banks)
+	assert.Nil(t, pagination)
+
+	mockRepo.AssertNotCalled(t, "GetBanksByCursor", mock.Anything, mock.Anything)
+}
+
+func TestBankService_GetBanks_InvalidSort(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewBankService(mockRepo)
+
+	filters := &repository.BankFilters{
+		Sort: []repository.SortField{{Field: "name"}, {Field: "password", Descending: true}},
+	}
+
+	banks, pagination, err := service.GetBanks(context.Background(), filters)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sort field: password")
+	assert.Nil(t, banks)
+	assert.Nil(t, pagination)
+
+	mockRepo.AssertNotCalled(t, "GetBanks", mock.Anything, mock.Anything)
+}
+
+func TestBankService_GetBanksByCursor_RejectsSort(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewBankService(mockRepo)
+
+	filters := &repository.BankFilters{
+		Sort: []repository.SortField{{Field: "country"}},
+	}
+
+	banks, pagination, err := service.GetBanksByCursor(context.Background(), filters)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sort")
+	assert.Nil(t, banks)
+	assert.Nil(t, pagination)
+
+	mockRepo.AssertNotCalled(t, "GetBanksByCursor", mock.Anything, mock.Anything)
+}
+
+func TestBankService_GetBanksByCursor_RejectsQuery(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewBankService(mockRepo)
+
+	filters := &repository.BankFilters{
+		Query: "santander",
+	}
+
+	banks, pagination, err := service.GetBanksByCursor(context.Background(), filters)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid q")
+	assert.Nil(t, banks)
+	assert.Nil(t, pagination)
+
+	mockRepo.AssertNotCalled(t, "GetBanksByCursor", mock.Anything, mock.Anything)
+}
+
 func TestBankService_GetBankDetails_AllEnvironments(t *testing.T) {
 	// Test data
 	expectedBank := &models.Bank{
@@ -308,6 +557,7 @@ func TestBankService_GetBankDetails_AllEnvironments(t *testing.T) {
 	mockRepo := new(MockBankRepository)
 	mockRepo.On("GetBankByID", mock.Anything, "test-bank").Return(expectedBank, nil)
 	mockRepo.On("GetBankEnvironmentConfigs", mock.Anything, "test-bank", "").Return(expectedConfigs, nil)
+	mockRepo.On("GetMaintenanceWindows", mock.Anything, "test-bank", "").Return([]models.MaintenanceWindow{}, nil)
 
 	// Create service with mock
 	service := NewBankService(mockRepo)
@@ -347,6 +597,7 @@ func TestBankService_GetBankDetails_SpecificEnvironment(t *testing.T) {
 	mockRepo := new(MockBankRepository)
 	mockRepo.On("GetBankByID", mock.Anything, "test-bank").Return(expectedBank, nil)
 	mockRepo.On("GetBankEnvironmentConfigs", mock.Anything, "test-bank", "sandbox").Return(expectedConfigs, nil)
+	mockRepo.On("GetMaintenanceWindows", mock.Anything, "test-bank", "sandbox").Return([]models.MaintenanceWindow{}, nil)
 
 	// Create service with mock
 	service := NewBankService(mockRepo)
@@ -457,3 +708,387 @@ func TestBankService_GetBankDetails_RepositoryError(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestBankService_LookupBanks_ByBIC(t *testing.T) {
+	bank := models.Bank{BankID: "BES2100", Name: "CaixaBank", Country: "ES"}
+	productionConfig := &models.BankEnvironmentConfig{BankID: "BES2100", Environment: models.EnvironmentProduction, Enabled: true}
+
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByBIC", mock.Anything, "CAIXESBBXXX").Return([]models.Bank{bank}, nil)
+	mockRepo.On("GetEnvironmentConfigsByBankIDs", mock.Anything, []string{"BES2100"}, "").Return(map[string]map[string]*models.BankEnvironmentConfig{
+		"BES2100": {"production": productionConfig},
+	}, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	// Lowercase and surrounding whitespace are normalized
+	result, err := service.LookupBanks(context.Background(), &BankLookupRequest{BIC: " caixesbbxxx "})
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "BES2100", result[0].BankID)
+	assert.Equal(t, productionConfig, result[0].EnvironmentConfigs["production"])
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_LookupBanks_ByCode(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByCode", mock.Anything, "2100", "ES").Return([]models.Bank{{BankID: "BES2100"}}, nil)
+	mockRepo.On("GetEnvironmentConfigsByBankIDs", mock.Anything, []string{"BES2100"}, "").Return(map[string]map[string]*models.BankEnvironmentConfig{}, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	result, err := service.LookupBanks(context.Background(), &BankLookupRequest{Code: "2100", Country: "es"})
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.NotNil(t, result[0].EnvironmentConfigs)
+	assert.Empty(t, result[0].EnvironmentConfigs)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_LookupBanks_NotFound(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByBIC", mock.Anything, "UNKNESMM").Return([]models.Bank{}, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	result, err := service.LookupBanks(context.Background(), &BankLookupRequest{BIC: "UNKNESMM"})
+
+	// Assertions
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "bank not found")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_LookupBanks_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name          string
+		lookup        *BankLookupRequest
+		expectedError string
+	}{
+		{
+			name:          "no criteria",
+			lookup:        &BankLookupRequest{},
+			expectedError: "either bic or code is required",
+		},
+		{
+			name:          "bic and code combined",
+			lookup:        &BankLookupRequest{BIC: "CAIXESBB", Code: "2100", Country: "ES"},
+			expectedError: "bic and code cannot be combined",
+		},
+		{
+			name:          "malformed bic",
+			lookup:        &BankLookupRequest{BIC: "CAIX-ES"},
+			expectedError: "invalid BIC format",
+		},
+		{
+			name:          "code without country",
+			lookup:        &BankLookupRequest{Code: "2100"},
+			expectedError: "country is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBankRepository)
+			service := NewBankService(mockRepo)
+
+			result, err := service.LookupBanks(context.Background(), tt.lookup)
+
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			mockRepo.AssertNotCalled(t, "GetBanksByBIC", mock.Anything, mock.Anything)
+			mockRepo.AssertNotCalled(t, "GetBanksByCode", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankService_ExportBanks(t *testing.T) {
+	banks := []models.BankWithEnvironments{
+		{Bank: models.Bank{BankID: "BES2100"}},
+		{Bank: models.Bank{BankID: "BES0049"}},
+	}
+
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("StreamBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		return filters.Environment == "all" && len(filters.Countries) == 1 && filters.Countries[0] == "ES"
+	}), mock.Anything).Return(banks, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	var exported []string
+	err := service.ExportBanks(context.Background(), &repository.BankFilters{Countries: []string{"es"}}, func(bank *models.BankWithEnvironments) error {
+		exported = append(exported, bank.BankID)
+		return nil
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BES2100", "BES0049"}, exported)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_ExportBanks_InvalidFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		filters       *repository.BankFilters
+		expectedError string
+	}{
+		{
+			name:          "invalid bank group",
+			filters:       &repository.BankFilters{BankGroupID: "not-a-uuid"},
+			expectedError: "invalid bank_group_id",
+		},
+		{
+			name:          "invalid sort",
+			filters:       &repository.BankFilters{Sort: []repository.SortField{{Field: "password"}}},
+			expectedError: "invalid sort field: password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBankRepository)
+			service := NewBankService(mockRepo)
+
+			err := service.ExportBanks(context.Background(), tt.filters, func(_ *models.BankWithEnvironments) error {
+				return nil
+			})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			mockRepo.AssertNotCalled(t, "StreamBanks", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankService_BatchGetBanks(t *testing.T) {
+	productionConfig := &models.BankEnvironmentConfig{BankID: "BES2100", Environment: models.EnvironmentProduction, Enabled: true}
+
+	// Create mock repository
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByIDs", mock.Anything, []string{"BES2100", "BES0049", "UNKNOWN"}).Return([]models.Bank{
+		{BankID: "BES0049", Name: "Santander"},
+		{BankID: "BES2100", Name: "CaixaBank"},
+	}, nil)
+	mockRepo.On("GetEnvironmentConfigsByBankIDs", mock.Anything, []string{"BES2100", "BES0049", "UNKNOWN"}, "production").Return(map[string]map[string]*models.BankEnvironmentConfig{
+		"BES2100": {"production": productionConfig},
+	}, nil)
+
+	// Create service with mock
+	service := NewBankService(mockRepo)
+
+	// Duplicated and blank IDs are ignored
+	result, err := service.BatchGetBanks(context.Background(), &BatchGetBanksRequest{
+		BankIDs:     []string{"BES2100", " BES0049 ", "BES2100", "", "UNKNOWN"},
+		Environment: "production",
+	})
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, result.Banks, 2)
+	assert.Equal(t, "CaixaBank", result.Banks["BES2100"].Name)
+	assert.Equal(t, productionConfig, result.Banks["BES2100"].EnvironmentConfigs["production"])
+	assert.NotNil(t, result.Banks["BES0049"].EnvironmentConfigs)
+	assert.Empty(t, result.Banks["BES0049"].EnvironmentConfigs)
+	assert.Equal(t, []string{"UNKNOWN"}, result.NotFound)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_BatchGetBanks_InvalidRequests(t *testing.T) {
+	tooMany := make([]string, MaxBankIDsPerBatch+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("BANK%d", i)
+	}
+
+	tests := []struct {
+		name          string
+		request       *BatchGetBanksRequest
+		expectedError string
+	}{
+		{
+			name:          "no bank IDs",
+			request:       &BatchGetBanksRequest{BankIDs: []string{" ", ""}},
+			expectedError: "invalid request: at least one bank ID is required",
+		},
+		{
+			name:          "too many bank IDs",
+			request:       &BatchGetBanksRequest{BankIDs: tooMany},
+			expectedError: "invalid request: at most 500 bank IDs",
+		},
+		{
+			name:          "unknown environment",
+			request:       &BatchGetBanksRequest{BankIDs: []string{"BES2100"}, Environment: "staging"},
+			expectedError: "invalid environment: staging",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBankRepository)
+			service := NewBankService(mockRepo)
+
+			result, err := service.BatchGetBanks(context.Background(), tt.request)
+
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			mockRepo.AssertNotCalled(t, "GetBanksByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBankService_RenderBanks_FieldsAndIncludes(t *testing.T) {
+	groupID := uuid.New()
+	banks := []models.Bank{
+		{BankID: "BES2100", Name: "CaixaBank", Country: "ES", BankGroupID: &groupID},
+		{BankID: "BES0049", Name: "Santander", Country: "ES"},
+	}
+	group := &models.BankGroup{GroupID: groupID, Name: "CaixaBank Group"}
+	sandboxConfig := &models.BankEnvironmentConfig{BankID: "BES2100", Environment: models.EnvironmentSandbox, Enabled: true}
+
+	// Create mock repository: each relation is loaded once for the whole page
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetEnvironmentConfigsByBankIDs", mock.Anything, []string{"BES2100", "BES0049"}, "sandbox").Return(map[string]map[string]*models.BankEnvironmentConfig{
+		"BES2100": {"sandbox": sandboxConfig},
+	}, nil).Once()
+	mockRepo.On("GetBankGroupsByIDs", mock.Anything, []uuid.UUID{groupID}).Return(map[uuid.UUID]*models.BankGroup{
+		groupID: group,
+	}, nil).Once()
+
+	service := NewBankService(mockRepo)
+
+	representation := &BankRepresentation{
+		Fields:  []string{"name", "logo_url"},
+		Include: []string{IncludeEnvironmentConfigs, IncludeBankGroup},
+	}
+
+	result, err := service.RenderBanks(context.Background(), banks, "sandbox", representation)
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	// Only the requested fields, the bank ID and the relations are rendered
+	assert.ElementsMatch(t, []string{"bank_id", "name", "logo_url", "environment_configs", "bank_group"}, slices.Collect(maps.Keys(result[0])))
+	assert.Equal(t, group, result[0]["bank_group"])
+	assert.Equal(t, map[string]*models.BankEnvironmentConfig{"sandbox": sandboxConfig}, result[0]["environment_configs"])
+
+	// Banks without group or configs still get the relation keys
+	assert.Nil(t, result[1]["bank_group"])
+	assert.Empty(t, result[1]["environment_configs"])
+
+	encoded, err := json.Marshal(result[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bank_id":"BES0049","name":"Santander","logo_url":null,"environment_configs":{},"bank_group":null}`, string(encoded))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_RenderBanks_FieldsOnly(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewBankService(mockRepo)
+
+	banks := []models.Bank{{BankID: "BES2100", Name: "CaixaBank", Country: "ES"}}
+
+	result, err := service.RenderBanks(context.Background(), banks, "all", &BankRepresentation{Fields: []string{"country"}})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.ElementsMatch(t, []string{"bank_id", "country"}, slices.Collect(maps.Keys(result[0])))
+
+	// No relation requested, so no additional queries
+	mockRepo.AssertNotCalled(t, "GetEnvironmentConfigsByBankIDs", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetBankGroupsByIDs", mock.Anything, mock.Anything)
+}
+
+func TestBankService_RenderBankDetails_KeepsEnvironmentConfigs(t *testing.T) {
+	groupID := uuid.New()
+	group := &models.BankGroup{GroupID: groupID, Name: "CaixaBank Group"}
+	details := &models.BankWithEnvironments{
+		Bank: models.Bank{BankID: "BES2100", Name: "CaixaBank", BankGroupID: &groupID},
+		EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"production": {BankID: "BES2100", Environment: models.EnvironmentProduction},
+		},
+	}
+
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBankGroupsByIDs", mock.Anything, []uuid.UUID{groupID}).Return(map[uuid.UUID]*models.BankGroup{
+		groupID: group,
+	}, nil)
+
+	service := NewBankService(mockRepo)
+
+	result, err := service.RenderBankDetails(context.Background(), details, &BankRepresentation{
+		Fields:  []string{"name"},
+		Include: []string{IncludeBankGroup},
+	})
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"bank_id", "name", "environment_configs", "bank_group"}, slices.Collect(maps.Keys(result)))
+	assert.Equal(t, group, result["bank_group"])
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_GetBankGroup(t *testing.T) {
+	groupID := uuid.New()
+	group := &models.BankGroup{GroupID: groupID, Name: "CaixaBank Group"}
+
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBankGroupsByIDs", mock.Anything, []uuid.UUID{groupID}).Return(map[uuid.UUID]*models.BankGroup{
+		groupID: group,
+	}, nil)
+
+	service := NewBankService(mockRepo)
+
+	result, err := service.GetBankGroup(context.Background(), " "+groupID.String()+" ")
+
+	require.NoError(t, err)
+	assert.Equal(t, group, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBankService_GetBankGroup_NotFound(t *testing.T) {
+	groupID := uuid.New()
+
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBankGroupsByIDs", mock.Anything, []uuid.UUID{groupID}).Return(map[uuid.UUID]*models.BankGroup{}, nil)
+
+	service := NewBankService(mockRepo)
+
+	result, err := service.GetBankGroup(context.Background(), groupID.String())
+
+	require.Error(t, err)
+	assert.Equal(t, "bank group not found", err.Error())
+	assert.Nil(t, result)
+}
+
+func TestBankService_GetBankGroup_InvalidID(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewBankService(mockRepo)
+
+	result, err := service.GetBankGroup(context.Background(), "not-a-uuid")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid group_id")
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "GetBankGroupsByIDs", mock.Anything, mock.Anything)
+}
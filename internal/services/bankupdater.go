@@ -39,7 +39,7 @@ type UpdateBankResponse struct {
 }
 
 type BankUpdater interface {
-	UpdateBank(ctx context.Context, bankID string, request *UpdateBankRequest) (*UpdateBankResponse, error)
+	UpdateBank(ctx context.Context, bankID string, request *UpdateBankRequest, precondition VersionPrecondition) (*UpdateBankResponse, error)
 }
 
 type BankUpdaterService struct {
@@ -54,13 +54,19 @@ func NewBankUpdaterService(writer repository.BankWriter, reader repository.BankR
 	}
 }
 
-func (s *BankUpdaterService) UpdateBank(ctx context.Context, bankID string, request *UpdateBankRequest) (*UpdateBankResponse, error) {
+// UpdateBank applies the request to the stored bank. The write only succeeds if the bank is still at
+// the version that was read, which must also satisfy the precondition.
+func (s *BankUpdaterService) UpdateBank(ctx context.Context, bankID string, request *UpdateBankRequest, precondition VersionPrecondition) (*UpdateBankResponse, error) {
 	// Verify bank exists first
 	existingBank, err := s.reader.GetBankByID(ctx, bankID)
 	if err != nil {
 		return nil, fmt.Errorf("bank not found: %w", err)
 	}
 
+	if err := checkVersion(precondition, "bank", existingBank.Version); err != nil {
+		return nil, err
+	}
+
 	// Build updated bank from request
 	updatedBank, err := s.requestToBank(existingBank, request)
 	if err != nil {
@@ -119,6 +125,7 @@ func (s *BankUpdaterService) requestToBank(existing *models.Bank, request *Updat
 		Keywords:               existing.Keywords,
 		Attribute:              existing.Attribute,
 		AuthTypeChoiceRequired: existing.AuthTypeChoiceRequired,
+		Version:                existing.Version,
 	}
 
 	// Apply updates only for provided fields
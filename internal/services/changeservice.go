@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+const (
+	DefaultChangesLimit = 500
+	MaxChangesLimit     = 1000
+)
+
+// ChangeService defines the interface for delta sync of the catalog
+type ChangeService interface {
+	GetChanges(ctx context.Context, since string, limit int) (*models.CatalogChanges, error)
+}
+
+type changeService struct {
+	changeRepo repository.ChangeRepository
+}
+
+// NewChangeService creates a new ChangeService
+func NewChangeService(changeRepo repository.ChangeRepository) ChangeService {
+	return &changeService{
+		changeRepo: changeRepo,
+	}
+}
+
+// GetChanges returns the catalog changes after the since token. An empty token starts
+// from the beginning, which yields the whole catalog as a sequence of changes.
+func (s *changeService) GetChanges(ctx context.Context, since string, limit int) (*models.CatalogChanges, error) {
+	var position repository.ChangePosition
+	if since != "" {
+		var err error
+		if position, err = repository.DecodeChangeToken(since); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit < 1 {
+		limit = DefaultChangesLimit
+	} else if limit > MaxChangesLimit {
+		limit = MaxChangesLimit
+	}
+
+	return s.changeRepo.GetChangesSince(ctx, position, limit)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// MockChangeRepository implements the ChangeRepository interface for testing
+type MockChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockChangeRepository) GetChangesSince(ctx context.Context, since repository.ChangePosition, limit int) (*models.CatalogChanges, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CatalogChanges), args.Error(1)
+}
+
+func TestChangeService_GetChanges_FromBeginning(t *testing.T) {
+	mockRepo := new(MockChangeRepository)
+	expected := &models.CatalogChanges{NextToken: repository.EncodeChangeToken(repository.ChangePosition{XID: 1200, Seq: 42})}
+	mockRepo.On("GetChangesSince", mock.Anything, repository.ChangePosition{}, DefaultChangesLimit).Return(expected, nil)
+
+	service := NewChangeService(mockRepo)
+
+	changes, err := service.GetChanges(context.Background(), "", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestChangeService_GetChanges_FromToken(t *testing.T) {
+	mockRepo := new(MockChangeRepository)
+	mockRepo.On("GetChangesSince", mock.Anything, repository.ChangePosition{XID: 1200, Seq: 42}, MaxChangesLimit).Return(&models.CatalogChanges{}, nil)
+
+	service := NewChangeService(mockRepo)
+
+	_, err := service.GetChanges(context.Background(), repository.EncodeChangeToken(repository.ChangePosition{XID: 1200, Seq: 42}), 5000)
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestChangeService_GetChanges_InvalidToken(t *testing.T) {
+	mockRepo := new(MockChangeRepository)
+	service := NewChangeService(mockRepo)
+
+	changes, err := service.GetChanges(context.Background(), "%%%", 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid since token")
+	assert.Nil(t, changes)
+	mockRepo.AssertNotCalled(t, "GetChangesSince", mock.Anything, mock.Anything, mock.Anything)
+}
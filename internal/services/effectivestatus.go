@@ -0,0 +1,174 @@
+package services
+
+import (
+	"time"
+
+	"github.com/wukong0111/go-banks/internal/cron"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// Reasons given when the flag or window behind an effective status has no text of its own
+const (
+	disabledReason    = "Bank is disabled in this environment"
+	blockedReason     = "Bank is blocked"
+	riskyReason       = "Bank is flagged as risky"
+	maintenanceReason = "Planned maintenance"
+)
+
+// windowOccurrence describes a maintenance window at a point in time: whether an occurrence of it is
+// in progress and until when, and the last time one of its occurrences started or ended
+type windowOccurrence struct {
+	active    bool
+	end       time.Time
+	changedAt *time.Time
+}
+
+// occurrenceAt evaluates a window at now. Recurring windows whose schedule or timezone can no
+// longer be read are treated as never in progress.
+func occurrenceAt(window *models.MaintenanceWindow, now time.Time) windowOccurrence {
+	var start, end time.Time
+
+	switch window.Kind {
+	case models.MaintenanceWindowOneOff:
+		if window.StartsAt == nil || window.EndsAt == nil {
+			return windowOccurrence{}
+		}
+		start, end = *window.StartsAt, *window.EndsAt
+
+	case models.MaintenanceWindowRecurring:
+		if window.Schedule == nil || window.DurationMinutes == nil {
+			return windowOccurrence{}
+		}
+		schedule, err := cron.Parse(*window.Schedule)
+		if err != nil {
+			return windowOccurrence{}
+		}
+		location, err := time.LoadLocation(window.Timezone)
+		if err != nil {
+			return windowOccurrence{}
+		}
+		// The latest start is the one whose occurrence ends last, so it alone decides
+		start, err = schedule.Prev(now.In(location))
+		if err != nil {
+			return windowOccurrence{}
+		}
+		end = start.Add(time.Duration(*window.DurationMinutes) * time.Minute)
+
+	default:
+		return windowOccurrence{}
+	}
+
+	switch {
+	case now.Before(start):
+		return windowOccurrence{}
+	case now.Before(end):
+		return windowOccurrence{active: true, end: end, changedAt: &start}
+	default:
+		return windowOccurrence{end: end, changedAt: &end}
+	}
+}
+
+// effectiveStatusAt combines the flags of an environment config with the maintenance windows of that
+// environment at now. The flags of the config come first: a disabled or blocked config is blocked
+// whatever its windows say, and a risky one is at least degraded.
+func effectiveStatusAt(config *models.BankEnvironmentConfig, windows []models.MaintenanceWindow, now time.Time) *models.EffectiveStatus {
+	status := &models.EffectiveStatus{Status: models.EffectiveStatusAvailable}
+
+	// The window in progress with the worst impact, ending last among equals
+	var current *models.MaintenanceWindow
+	var currentEnd time.Time
+	for i := range windows {
+		window := &windows[i]
+		occurrence := occurrenceAt(window, now)
+		if occurrence.changedAt != nil && (status.ChangedAt == nil || occurrence.changedAt.After(*status.ChangedAt)) {
+			changedAt := occurrence.changedAt.UTC()
+			status.ChangedAt = &changedAt
+		}
+		if !occurrence.active {
+			continue
+		}
+		if current == nil || impactRank(window.Impact) > impactRank(current.Impact) ||
+			(window.Impact == current.Impact && occurrence.end.After(currentEnd)) {
+			current, currentEnd = window, occurrence.end
+		}
+	}
+
+	setStatus := func(value string, reason *string, fallback string) {
+		status.Status = value
+		status.Reason = reason
+		if reason == nil || *reason == "" {
+			status.Reason = stringPtr(fallback)
+		}
+	}
+	setWindow := func() {
+		setStatus(current.Impact, current.Reason, maintenanceReason)
+		windowID := current.WindowID
+		until := currentEnd.UTC()
+		status.MaintenanceWindowID = &windowID
+		status.Until = &until
+	}
+
+	switch {
+	case !config.Enabled:
+		setStatus(models.EffectiveStatusBlocked, nil, disabledReason)
+	case config.Blocked:
+		setStatus(models.EffectiveStatusBlocked, config.BlockedText, blockedReason)
+	case current != nil && current.Impact == models.EffectiveStatusBlocked:
+		setWindow()
+	case config.Risky:
+		setStatus(models.EffectiveStatusDegraded, config.RiskyMessage, riskyReason)
+	case current != nil:
+		setWindow()
+	}
+
+	return status
+}
+
+// impactRank orders impacts from the mildest to the most severe
+func impactRank(impact string) int {
+	switch impact {
+	case models.EffectiveStatusBlocked:
+		return 2
+	case models.EffectiveStatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// activeMaintenanceAt lists the bank environments with a maintenance window in progress at now and the
+// worst impact among their windows
+func activeMaintenanceAt(windows []models.MaintenanceWindow, now time.Time) []repository.ActiveMaintenance {
+	type key struct {
+		bankID      string
+		environment string
+	}
+	impacts := make(map[key]string)
+	var order []key
+
+	for i := range windows {
+		window := &windows[i]
+		if !occurrenceAt(window, now).active {
+			continue
+		}
+		k := key{bankID: window.BankID, environment: string(window.Environment)}
+		current, seen := impacts[k]
+		if !seen {
+			order = append(order, k)
+		}
+		if !seen || impactRank(window.Impact) > impactRank(current) {
+			impacts[k] = window.Impact
+		}
+	}
+
+	active := make([]repository.ActiveMaintenance, 0, len(order))
+	for _, k := range order {
+		active = append(active, repository.ActiveMaintenance{
+			BankID:      k.bankID,
+			Environment: k.environment,
+			Impact:      impacts[k],
+		})
+	}
+	return active
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+func oneOffWindow(impact string, startsAt, endsAt time.Time) models.MaintenanceWindow {
+	return models.MaintenanceWindow{
+		WindowID:    uuid.New(),
+		BankID:      "BES0049",
+		Environment: models.EnvironmentProduction,
+		Kind:        models.MaintenanceWindowOneOff,
+		StartsAt:    &startsAt,
+		EndsAt:      &endsAt,
+		Timezone:    "UTC",
+		Impact:      impact,
+	}
+}
+
+func recurringWindow(impact, schedule string, minutes int, timezone string) models.MaintenanceWindow {
+	return models.MaintenanceWindow{
+		WindowID:        uuid.New(),
+		BankID:          "BES0049",
+		Environment:     models.EnvironmentProduction,
+		Kind:            models.MaintenanceWindowRecurring,
+		Schedule:        &schedule,
+		DurationMinutes: &minutes,
+		Timezone:        timezone,
+		Impact:          impact,
+	}
+}
+
+func TestEffectiveStatusAt_Flags(t *testing.T) {
+	now := time.Date(2026, 11, 7, 12, 0, 0, 0, time.UTC)
+	blockedText := "Migrating to PSD2 v2"
+
+	tests := []struct {
+		name           string
+		config         models.BankEnvironmentConfig
+		expectedStatus string
+		expectedReason string
+	}{
+		{"available", models.BankEnvironmentConfig{Enabled: true}, models.EffectiveStatusAvailable, ""},
+		{"disabled", models.BankEnvironmentConfig{Enabled: false, Risky: true}, models.EffectiveStatusBlocked, disabledReason},
+		{"blocked with text", models.BankEnvironmentConfig{Enabled: true, Blocked: true, BlockedText: &blockedText}, models.EffectiveStatusBlocked, blockedText},
+		{"blocked without text", models.BankEnvironmentConfig{Enabled: true, Blocked: true}, models.EffectiveStatusBlocked, blockedReason},
+		{"risky", models.BankEnvironmentConfig{Enabled: true, Risky: true}, models.EffectiveStatusDegraded, riskyReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := effectiveStatusAt(&tt.config, nil, now)
+
+			assert.Equal(t, tt.expectedStatus, status.Status)
+			if tt.expectedReason == "" {
+				assert.Nil(t, status.Reason)
+			} else {
+				require.NotNil(t, status.Reason)
+				assert.Equal(t, tt.expectedReason, *status.Reason)
+			}
+			assert.Nil(t, status.MaintenanceWindowID)
+			assert.Nil(t, status.ChangedAt)
+		})
+	}
+}
+
+func TestEffectiveStatusAt_OneOffWindow(t *testing.T) {
+	now := time.Date(2026, 11, 7, 12, 0, 0, 0, time.UTC)
+	reason := "Core banking upgrade"
+	window := oneOffWindow(models.EffectiveStatusBlocked, now.Add(-time.Hour), now.Add(2*time.Hour))
+	window.Reason = &reason
+
+	status := effectiveStatusAt(&models.BankEnvironmentConfig{Enabled: true, Risky: true}, []models.MaintenanceWindow{window}, now)
+
+	assert.Equal(t, models.EffectiveStatusBlocked, status.Status)
+	require.NotNil(t, status.Reason)
+	assert.Equal(t, reason, *status.Reason)
+	require.NotNil(t, status.MaintenanceWindowID)
+	assert.Equal(t, window.WindowID, *status.MaintenanceWindowID)
+	require.NotNil(t, status.Until)
+	assert.True(t, status.Until.Equal(now.Add(2*time.Hour)))
+	require.NotNil(t, status.ChangedAt)
+	assert.True(t, status.ChangedAt.Equal(now.Add(-time.Hour)))
+}
+
+func TestEffectiveStatusAt_Precedence(t *testing.T) {
+	now := time.Date(2026, 11, 7, 12, 0, 0, 0, time.UTC)
+	degrading := oneOffWindow(models.EffectiveStatusDegraded, now.Add(-time.Hour), now.Add(time.Hour))
+	riskyMessage := "Intermittent timeouts"
+
+	// A risky config outranks a degrading window
+	status := effectiveStatusAt(&models.BankEnvironmentConfig{Enabled: true, Risky: true, RiskyMessage: &riskyMessage},
+		[]models.MaintenanceWindow{degrading}, now)
+	assert.Equal(t, models.EffectiveStatusDegraded, status.Status)
+	assert.Equal(t, riskyMessage, *status.Reason)
+	assert.Nil(t, status.MaintenanceWindowID)
+
+	// Without a risky flag the window decides, with the default reason
+	status = effectiveStatusAt(&models.BankEnvironmentConfig{Enabled: true}, []models.MaintenanceWindow{degrading}, now)
+	assert.Equal(t, models.EffectiveStatusDegraded, status.Status)
+	assert.Equal(t, maintenanceReason, *status.Reason)
+	assert.Equal(t, degrading.WindowID, *status.MaintenanceWindowID)
+
+	// A blocking window outranks a degrading one
+	blocking := oneOffWindow(models.EffectiveStatusBlocked, now.Add(-time.Minute), now.Add(time.Minute))
+	status = effectiveStatusAt(&models.BankEnvironmentConfig{Enabled: true}, []models.MaintenanceWindow{degrading, blocking}, now)
+	assert.Equal(t, models.EffectiveStatusBlocked, status.Status)
+	assert.Equal(t, blocking.WindowID, *status.MaintenanceWindowID)
+}
+
+func TestEffectiveStatusAt_InactiveWindows(t *testing.T) {
+	now := time.Date(2026, 11, 7, 12, 0, 0, 0, time.UTC)
+	past := oneOffWindow(models.EffectiveStatusBlocked, now.Add(-3*time.Hour), now.Add(-2*time.Hour))
+	future := oneOffWindow(models.EffectiveStatusBlocked, now.Add(time.Hour), now.Add(2*time.Hour))
+
+	status := effectiveStatusAt(&models.BankEnvironmentConfig{Enabled: true}, []models.MaintenanceWindow{past, future}, now)
+
+	assert.Equal(t, models.EffectiveStatusAvailable, status.Status)
+	assert.Nil(t, status.MaintenanceWindowID)
+	// The end of the past window is the last change of the status
+	require.NotNil(t, status.ChangedAt)
+	assert.True(t, status.ChangedAt.Equal(now.Add(-2*time.Hour)))
+}
+
+func TestEffectiveStatusAt_RecurringWindow(t *testing.T) {
+	// Nightly from 01:00 to 03:00 Madrid time, which is UTC+1 in November
+	window := recurringWindow(models.EffectiveStatusDegraded, "0 1 * * *", 120, "Europe/Madrid")
+	config := &models.BankEnvironmentConfig{Enabled: true}
+
+	during := time.Date(2026, 11, 7, 1, 30, 0, 0, time.UTC)
+	status := effectiveStatusAt(config, []models.MaintenanceWindow{window}, during)
+	assert.Equal(t, models.EffectiveStatusDegraded, status.Status)
+	require.NotNil(t, status.Until)
+	assert.True(t, status.Until.Equal(time.Date(2026, 11, 7, 2, 0, 0, 0, time.UTC)))
+	assert.True(t, status.ChangedAt.Equal(time.Date(2026, 11, 7, 0, 0, 0, 0, time.UTC)))
+
+	after := time.Date(2026, 11, 7, 2, 30, 0, 0, time.UTC)
+	status = effectiveStatusAt(config, []models.MaintenanceWindow{window}, after)
+	assert.Equal(t, models.EffectiveStatusAvailable, status.Status)
+	assert.True(t, status.ChangedAt.Equal(time.Date(2026, 11, 7, 2, 0, 0, 0, time.UTC)))
+}
+
+func TestActiveMaintenanceAt(t *testing.T) {
+	now := time.Date(2026, 11, 7, 12, 0, 0, 0, time.UTC)
+	degrading := oneOffWindow(models.EffectiveStatusDegraded, now.Add(-time.Hour), now.Add(time.Hour))
+	blocking := oneOffWindow(models.EffectiveStatusBlocked, now.Add(-time.Hour), now.Add(time.Hour))
+	other := oneOffWindow(models.EffectiveStatusDegraded, now.Add(-time.Hour), now.Add(time.Hour))
+	other.BankID = "BFR0001"
+	other.Environment = models.EnvironmentSandbox
+	inactive := oneOffWindow(models.EffectiveStatusBlocked, now.Add(time.Hour), now.Add(2*time.Hour))
+	inactive.BankID = "BIT0001"
+
+	active := activeMaintenanceAt([]models.MaintenanceWindow{degrading, other, blocking, inactive}, now)
+
+	assert.Equal(t, []repository.ActiveMaintenance{
+		{BankID: "BES0049", Environment: "production", Impact: models.EffectiveStatusBlocked},
+		{BankID: "BFR0001", Environment: "sandbox", Impact: models.EffectiveStatusDegraded},
+	}, active)
+}
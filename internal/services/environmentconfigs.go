@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// EnvironmentConfigService reads and writes the environment configs of a bank one at a time. Unlike
+// UpdateBank, a write only touches the config of the targeted environment.
+type EnvironmentConfigService interface {
+	GetEnvironmentConfig(ctx context.Context, bankID, environment string) (*models.BankEnvironmentConfigWithStatus, error)
+	PutEnvironmentConfig(ctx context.Context, bankID, environment string, request *EnvironmentConfig, precondition VersionPrecondition) (*models.BankEnvironmentConfigWithStatus, bool, error)
+	PatchEnvironmentConfig(ctx context.Context, bankID, environment, mediaType string, patch []byte, precondition VersionPrecondition) (*models.BankEnvironmentConfigWithStatus, error)
+	DeleteEnvironmentConfig(ctx context.Context, bankID, environment string, precondition VersionPrecondition) error
+}
+
+type environmentConfigService struct {
+	writer repository.BankWriter
+	reader repository.BankRepository
+}
+
+func NewEnvironmentConfigService(writer repository.BankWriter, reader repository.BankRepository) EnvironmentConfigService {
+	return &environmentConfigService{
+		writer: writer,
+		reader: reader,
+	}
+}
+
+// GetEnvironmentConfig returns the config of one environment of a bank with its effective status
+func (s *environmentConfigService) GetEnvironmentConfig(ctx context.Context, bankID, environment string) (*models.BankEnvironmentConfigWithStatus, error) {
+	bankID, existing, err := s.readConfig(ctx, bankID, environment)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("environment config '%s' of bank '%s' not found", environment, bankID)
+	}
+
+	return s.withStatus(ctx, existing)
+}
+
+// PutEnvironmentConfig creates or replaces the config of one environment of a bank. Fields missing
+// from the request take the defaults of a new config. It reports whether the config was created.
+// The write only succeeds if the config is still at the version that was read, which must also
+// satisfy the precondition; a config that does not exist yet has no version to match.
+func (s *environmentConfigService) PutEnvironmentConfig(ctx context.Context, bankID, environment string, request *EnvironmentConfig, precondition VersionPrecondition) (*models.BankEnvironmentConfigWithStatus, bool, error) {
+	bankID, existing, err := s.readConfig(ctx, bankID, environment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	config := environmentConfigFromRequest(bankID, models.EnvironmentType(environment), request)
+	if existing != nil {
+		config.Version = existing.Version
+		config.CreatedAt = existing.CreatedAt
+	}
+
+	if err := checkVersion(precondition, "environment config", config.Version); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.writer.SaveEnvironmentConfig(ctx, config); err != nil {
+		return nil, false, fmt.Errorf("failed to save environment config: %w", err)
+	}
+
+	result, err := s.withStatus(ctx, config)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, existing == nil, nil
+}
+
+// PatchEnvironmentConfig applies a JSON Merge Patch or JSON Patch document to the config of one
+// environment of a bank. The effective status is computed and is not part of the patched document.
+func (s *environmentConfigService) PatchEnvironmentConfig(ctx context.Context, bankID, environment, mediaType string, patch []byte, precondition VersionPrecondition) (*models.BankEnvironmentConfigWithStatus, error) {
+	bankID, existing, err := s.readConfig(ctx, bankID, environment)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("environment config '%s' of bank '%s' not found", environment, bankID)
+	}
+
+	if err := checkVersion(precondition, "environment config", existing.Version); err != nil {
+		return nil, err
+	}
+
+	patched, err := applyPatch(existing, mediaType, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fieldErrorsToError(validatePatchedEnvironmentConfig(existing, patched)); err != nil {
+		return nil, err
+	}
+
+	if err := s.writer.SaveEnvironmentConfig(ctx, patched); err != nil {
+		return nil, fmt.Errorf("failed to save environment config: %w", err)
+	}
+
+	return s.withStatus(ctx, patched)
+}
+
+// DeleteEnvironmentConfig removes the config of one environment of a bank
+func (s *environmentConfigService) DeleteEnvironmentConfig(ctx context.Context, bankID, environment string, precondition VersionPrecondition) error {
+	bankID, existing, err := s.readConfig(ctx, bankID, environment)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("environment config '%s' of bank '%s' not found", environment, bankID)
+	}
+
+	if err := checkVersion(precondition, "environment config", existing.Version); err != nil {
+		return err
+	}
+
+	if err := s.writer.DeleteEnvironmentConfig(ctx, bankID, existing.Environment, existing.Version); err != nil {
+		return fmt.Errorf("failed to delete environment config: %w", err)
+	}
+	return nil
+}
+
+// readConfig checks the bank and environment and returns the normalized bank ID and the stored
+// config, which is nil when the bank has no config for the environment
+func (s *environmentConfigService) readConfig(ctx context.Context, bankID, environment string) (string, *models.BankEnvironmentConfig, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return "", nil, err
+	}
+	if !isValidEnvironment(environment) {
+		return "", nil, fmt.Errorf("invalid request: environment must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+
+	if _, err := s.reader.GetBankByID(ctx, bankID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, errors.New("bank not found")
+		}
+		return "", nil, fmt.Errorf("failed to get bank: %w", err)
+	}
+
+	configs, err := s.reader.GetBankEnvironmentConfigs(ctx, bankID, environment)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get environment configs: %w", err)
+	}
+
+	return bankID, configs[environment], nil
+}
+
+// withStatus computes the effective status of a config at the current time
+func (s *environmentConfigService) withStatus(ctx context.Context, config *models.BankEnvironmentConfig) (*models.BankEnvironmentConfigWithStatus, error) {
+	windows, err := s.reader.GetMaintenanceWindows(ctx, config.BankID, string(config.Environment))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	return &models.BankEnvironmentConfigWithStatus{
+		BankEnvironmentConfig: *config,
+		EffectiveStatus:       effectiveStatusAt(config, windows, time.Now()),
+	}, nil
+}
+
+// environmentConfigFromRequest builds a whole config from a request. Flags missing from the request
+// fall back to the column defaults, so a config is enabled unless it says otherwise.
+func environmentConfigFromRequest(bankID string, environment models.EnvironmentType, request *EnvironmentConfig) *models.BankEnvironmentConfig {
+	config := &models.BankEnvironmentConfig{
+		BankID:                       bankID,
+		Environment:                  environment,
+		Enabled:                      true,
+		BlockedText:                  request.BlockedText,
+		RiskyMessage:                 request.RiskyMessage,
+		SupportsInstantPayments:      request.SupportsInstantPayments,
+		InstantPaymentsActivated:     request.InstantPaymentsActivated,
+		InstantPaymentsLimit:         request.InstantPaymentsLimit,
+		OkStatusCodesSimplePayment:   request.OkStatusCodesSimplePayment,
+		OkStatusCodesInstantPayment:  request.OkStatusCodesInstantPayment,
+		OkStatusCodesPeriodicPayment: request.OkStatusCodesPeriodicPayment,
+		EnabledPeriodicPayment:       request.EnabledPeriodicPayment,
+		FrequencyPeriodicPayment:     request.FrequencyPeriodicPayment,
+		ConfigPeriodicPayment:        request.ConfigPeriodicPayment,
+	}
+	if request.Enabled != nil {
+		config.Enabled = *request.Enabled
+	}
+	if request.Blocked != nil {
+		config.Blocked = *request.Blocked
+	}
+	if request.Risky != nil {
+		config.Risky = *request.Risky
+	}
+	if request.AppAuthSetupRequired != nil {
+		config.AppAuthSetupRequired = *request.AppAuthSetupRequired
+	}
+	return config
+}
+
+// validatePatchedEnvironmentConfig rejects changes to the fields managed by the service
+func validatePatchedEnvironmentConfig(existing, patched *models.BankEnvironmentConfig) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if patched.BankID != existing.BankID {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "bank_id", Message: "is read-only"})
+	}
+	if patched.Environment != existing.Environment {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "environment", Message: "is read-only"})
+	}
+	if patched.Version != existing.Version {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "version", Message: "is read-only"})
+	}
+	if !patched.CreatedAt.Equal(existing.CreatedAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "created_at", Message: "is read-only"})
+	}
+	if !patched.UpdatedAt.Equal(existing.UpdatedAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "updated_at", Message: "is read-only"})
+	}
+
+	return fieldErrors
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+func storedSandboxConfig() *models.BankEnvironmentConfig {
+	return &models.BankEnvironmentConfig{
+		BankID:      "BES0049",
+		Environment: models.EnvironmentSandbox,
+		Enabled:     true,
+		Version:     4,
+		CreatedAt:   time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func expectStoredConfig(bankRepo *MockBankRepository, config *models.BankEnvironmentConfig) {
+	configs := map[string]*models.BankEnvironmentConfig{}
+	if config != nil {
+		configs[string(config.Environment)] = config
+	}
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+	bankRepo.On("GetBankEnvironmentConfigs", mock.Anything, "BES0049", "sandbox").Return(configs, nil)
+	bankRepo.On("GetMaintenanceWindows", mock.Anything, "BES0049", "sandbox").Return([]models.MaintenanceWindow{}, nil)
+}
+
+func TestEnvironmentConfigService_GetEnvironmentConfig(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	expectStoredConfig(bankRepo, storedSandboxConfig())
+
+	config, err := service.GetEnvironmentConfig(context.Background(), "BES0049", "sandbox")
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), config.Version)
+	require.NotNil(t, config.EffectiveStatus)
+	assert.Equal(t, models.EffectiveStatusAvailable, config.EffectiveStatus.Status)
+}
+
+func TestEnvironmentConfigService_GetEnvironmentConfig_Errors(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	_, err := service.GetEnvironmentConfig(context.Background(), "BES0049", "staging")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request: environment must be one of")
+
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+	bankRepo.On("GetBankEnvironmentConfigs", mock.Anything, "BES0049", "uat").Return(map[string]*models.BankEnvironmentConfig{}, nil)
+	_, err = service.GetEnvironmentConfig(context.Background(), "BES0049", "uat")
+	require.EqualError(t, err, "environment config 'uat' of bank 'BES0049' not found")
+
+	bankRepo.On("GetBankByID", mock.Anything, "BXX0001").Return(nil, pgx.ErrNoRows)
+	_, err = service.GetEnvironmentConfig(context.Background(), "BXX0001", "uat")
+	require.EqualError(t, err, "bank not found")
+}
+
+func TestEnvironmentConfigService_PutEnvironmentConfig_Replace(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	stored := storedSandboxConfig()
+	expectStoredConfig(bankRepo, stored)
+	writer.On("SaveEnvironmentConfig", mock.Anything, mock.MatchedBy(func(config *models.BankEnvironmentConfig) bool {
+		// The write is based on the version that was read and only targets sandbox
+		return config.BankID == "BES0049" &&
+			config.Environment == models.EnvironmentSandbox &&
+			config.Version == 4 &&
+			config.Enabled && config.Risky
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.BankEnvironmentConfig).Version = 5
+	}).Return(nil)
+
+	risky := true
+	message := "Intermittent timeouts"
+	config, created, err := service.PutEnvironmentConfig(context.Background(), "BES0049", "sandbox",
+		&EnvironmentConfig{Risky: &risky, RiskyMessage: &message}, VersionPrecondition{4})
+
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, int64(5), config.Version)
+	assert.True(t, config.CreatedAt.Equal(stored.CreatedAt))
+	assert.Equal(t, models.EffectiveStatusDegraded, config.EffectiveStatus.Status)
+	writer.AssertExpectations(t)
+}
+
+func TestEnvironmentConfigService_PutEnvironmentConfig_Create(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	expectStoredConfig(bankRepo, nil)
+	writer.On("SaveEnvironmentConfig", mock.Anything, mock.MatchedBy(func(config *models.BankEnvironmentConfig) bool {
+		return config.Version == 0 && config.Enabled
+	})).Return(nil)
+
+	_, created, err := service.PutEnvironmentConfig(context.Background(), "BES0049", "sandbox", &EnvironmentConfig{}, nil)
+
+	require.NoError(t, err)
+	assert.True(t, created)
+	writer.AssertExpectations(t)
+}
+
+func TestEnvironmentConfigService_PutEnvironmentConfig_StalePrecondition(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	expectStoredConfig(bankRepo, storedSandboxConfig())
+
+	_, _, err := service.PutEnvironmentConfig(context.Background(), "BES0049", "sandbox", &EnvironmentConfig{}, VersionPrecondition{3})
+
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	writer.AssertNotCalled(t, "SaveEnvironmentConfig", mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentConfigService_PatchEnvironmentConfig(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	expectStoredConfig(bankRepo, storedSandboxConfig())
+	writer.On("SaveEnvironmentConfig", mock.Anything, mock.MatchedBy(func(config *models.BankEnvironmentConfig) bool {
+		return config.Blocked && *config.BlockedText == "Migration" && config.Enabled && config.Version == 4
+	})).Return(nil)
+
+	config, err := service.PatchEnvironmentConfig(context.Background(), "BES0049", "sandbox", jsonpatch.MergePatchMediaType,
+		[]byte(`{"blocked":true,"blocked_text":"Migration"}`), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.EffectiveStatusBlocked, config.EffectiveStatus.Status)
+	assert.Equal(t, "Migration", *config.EffectiveStatus.Reason)
+	writer.AssertExpectations(t)
+}
+
+func TestEnvironmentConfigService_PatchEnvironmentConfig_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       string
+		expectedErr string
+	}{
+		{"read-only field", `{"environment":"production","version":9}`, "invalid patch result: environment is read-only, version is read-only"},
+		{"computed status", `{"effective_status":{"status":"available"}}`, "invalid patch result"},
+		{"wrong type", `{"enabled":"yes"}`, "invalid patch result"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := new(MockBankWriter)
+			bankRepo := new(MockBankRepository)
+			service := NewEnvironmentConfigService(writer, bankRepo)
+			expectStoredConfig(bankRepo, storedSandboxConfig())
+
+			_, err := service.PatchEnvironmentConfig(context.Background(), "BES0049", "sandbox", jsonpatch.MergePatchMediaType, []byte(tt.patch), nil)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			writer.AssertNotCalled(t, "SaveEnvironmentConfig", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEnvironmentConfigService_DeleteEnvironmentConfig(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentConfigService(writer, bankRepo)
+
+	expectStoredConfig(bankRepo, storedSandboxConfig())
+	writer.On("DeleteEnvironmentConfig", mock.Anything, "BES0049", models.EnvironmentSandbox, int64(4)).
+		Return(errors.New("connection reset"))
+
+	err := service.DeleteEnvironmentConfig(context.Background(), "BES0049", "sandbox", VersionPrecondition{4})
+
+	require.EqualError(t, err, "failed to delete environment config: connection reset")
+}
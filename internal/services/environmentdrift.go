@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// Supported drift report formats
+const (
+	DriftReportFormatJSON = "json"
+	DriftReportFormatCSV  = "csv"
+)
+
+// driftComparedFields are the environment config fields compared by the drift report: the enabled and
+// blocked states, the instant payment flags, the status code lists and the periodic payment settings
+var driftComparedFields = []string{
+	"enabled",
+	"blocked",
+	"supports_instant_payments",
+	"instant_payments_activated",
+	"instant_payments_limit",
+	"ok_status_codes_simple_payment",
+	"ok_status_codes_instant_payment",
+	"ok_status_codes_periodic_payment",
+	"enabled_periodic_payment",
+	"frequency_periodic_payment",
+	"config_periodic_payment",
+}
+
+// EnvironmentDriftColumns is the CSV header of the drift report, which has a row per drifted field
+var EnvironmentDriftColumns = []string{"bank_id", "name", "country", "status", "field", "base", "target"}
+
+// EnvironmentDriftService compares the environment configs of every bank between two environments
+type EnvironmentDriftService interface {
+	GetEnvironmentDrift(ctx context.Context, filters *repository.BankFilters, base, target string) (*models.EnvironmentDriftReport, error)
+}
+
+type environmentDriftService struct {
+	banks BankService
+}
+
+func NewEnvironmentDriftService(reader repository.BankRepository) EnvironmentDriftService {
+	return &environmentDriftService{
+		banks: NewBankService(reader),
+	}
+}
+
+// GetEnvironmentDrift lists the banks matching the filters whose config of the target environment
+// differs from their config of the base environment, in the listing order. A bank with a config in
+// only one of the environments drifts on every compared field.
+func (s *environmentDriftService) GetEnvironmentDrift(ctx context.Context, filters *repository.BankFilters, base, target string) (*models.EnvironmentDriftReport, error) {
+	if !isValidEnvironment(base) {
+		return nil, fmt.Errorf("invalid request: base must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+	if !isValidEnvironment(target) {
+		return nil, fmt.Errorf("invalid request: target must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+	if base == target {
+		return nil, errors.New("invalid request: target must differ from base")
+	}
+
+	// Every config of a bank is needed, so the filters cannot name an environment
+	if filters.Environment != "" {
+		return nil, errors.New("invalid request: env is not supported, the report compares base and target")
+	}
+	filters.Environment = "all"
+
+	report := &models.EnvironmentDriftReport{
+		Base:        models.EnvironmentType(base),
+		Target:      models.EnvironmentType(target),
+		Fields:      driftComparedFields,
+		GeneratedAt: time.Now().UTC(),
+		Banks:       []models.EnvironmentDrift{},
+	}
+
+	err := s.banks.ExportBanks(ctx, filters, func(bank *models.BankWithEnvironments) error {
+		baseConfig, hasBase := bank.EnvironmentConfigs[base]
+		targetConfig, hasTarget := bank.EnvironmentConfigs[target]
+		if !hasBase && !hasTarget {
+			return nil
+		}
+		report.Compared++
+
+		drift, err := compareEnvironmentConfigs(baseConfig, targetConfig)
+		if err != nil {
+			return fmt.Errorf("failed to compare environment configs of bank %s: %w", bank.BankID, err)
+		}
+		if drift == nil {
+			return nil
+		}
+
+		drift.BankID = bank.BankID
+		drift.Name = bank.Name
+		drift.Country = bank.Country
+		report.Banks = append(report.Banks, *drift)
+		report.Drifted++
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to compare environments: %w", err)
+	}
+
+	return report, nil
+}
+
+// compareEnvironmentConfigs returns the drift between two configs of a bank, either of which may be
+// nil, or nil when every compared field matches
+func compareEnvironmentConfigs(base, target *models.BankEnvironmentConfig) (*models.EnvironmentDrift, error) {
+	baseFields, err := driftFieldValues(base)
+	if err != nil {
+		return nil, err
+	}
+	targetFields, err := driftFieldValues(target)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &models.EnvironmentDrift{Status: models.DriftStatusDifferent}
+	switch {
+	case base == nil:
+		drift.Status = models.DriftStatusMissingInBase
+	case target == nil:
+		drift.Status = models.DriftStatusMissingInTarget
+	}
+
+	for _, field := range driftComparedFields {
+		baseValue, targetValue := baseFields[field], targetFields[field]
+		if base != nil && target != nil && reflect.DeepEqual(baseValue, targetValue) {
+			continue
+		}
+		drift.Fields = append(drift.Fields, models.EnvironmentDriftField{Field: field, Base: baseValue, Target: targetValue})
+	}
+
+	if len(drift.Fields) == 0 {
+		return nil, nil
+	}
+	return drift, nil
+}
+
+// driftFieldValues decodes a config into a map from JSON field names to values, empty for a nil config
+func driftFieldValues(config *models.BankEnvironmentConfig) (map[string]any, error) {
+	if config == nil {
+		return map[string]any{}, nil
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// WriteEnvironmentDriftCSV writes a drift report as CSV, with a row per drifted field of each bank.
+// Values follow the export format: nulls become empty cells and string lists are joined.
+func WriteEnvironmentDriftCSV(w io.Writer, report *models.EnvironmentDriftReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(EnvironmentDriftColumns); err != nil {
+		return err
+	}
+
+	for _, drift := range report.Banks {
+		for _, field := range drift.Fields {
+			base, err := driftCSVCell(field.Base)
+			if err != nil {
+				return fmt.Errorf("failed to encode bank %s: %w", drift.BankID, err)
+			}
+			target, err := driftCSVCell(field.Target)
+			if err != nil {
+				return fmt.Errorf("failed to encode bank %s: %w", drift.BankID, err)
+			}
+			record := []string{drift.BankID, drift.Name, drift.Country, drift.Status, field.Field, base, target}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func driftCSVCell(value any) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return csvCell(encoded)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+func driftTestBanks() []models.BankWithEnvironments {
+	limit := int32(15000)
+	higherLimit := int32(30000)
+	supported := true
+
+	return []models.BankWithEnvironments{
+		// Production lags behind the instant payment setup tested in UAT
+		{Bank: models.Bank{BankID: "BES0049", Name: "Santander", Country: "ES"}, EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"uat": {Environment: models.EnvironmentUAT, Enabled: true, SupportsInstantPayments: &supported, InstantPaymentsLimit: &higherLimit,
+				OkStatusCodesInstantPayment: []string{"ACSC", "ACCP"}},
+			"production": {Environment: models.EnvironmentProduction, Enabled: true, SupportsInstantPayments: &supported, InstantPaymentsLimit: &limit,
+				OkStatusCodesInstantPayment: []string{"ACSC"}, RiskyMessage: stringPtr("not compared")},
+		}},
+		// Same compared values; risky is not compared
+		{Bank: models.Bank{BankID: "BES0081", Name: "Sabadell", Country: "ES"}, EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"uat":        {Environment: models.EnvironmentUAT, Enabled: true},
+			"production": {Environment: models.EnvironmentProduction, Enabled: true, Risky: true},
+		}},
+		// Tested but never released
+		{Bank: models.Bank{BankID: "BPT0033", Name: "Millennium BCP", Country: "PT"}, EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"uat": {Environment: models.EnvironmentUAT, Enabled: true},
+		}},
+		// Configured in neither environment
+		{Bank: models.Bank{BankID: "BFR0001", Name: "BNP Paribas", Country: "FR"}, EnvironmentConfigs: map[string]*models.BankEnvironmentConfig{
+			"sandbox": {Environment: models.EnvironmentSandbox, Enabled: true},
+		}},
+	}
+}
+
+func TestEnvironmentDriftService_GetEnvironmentDrift(t *testing.T) {
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentDriftService(bankRepo)
+
+	bankRepo.On("StreamBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		// Every config is needed to compare two environments
+		return filters.Environment == "all"
+	}), mock.Anything).Return(driftTestBanks(), nil)
+
+	report, err := service.GetEnvironmentDrift(context.Background(), &repository.BankFilters{}, "uat", "production")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.EnvironmentUAT, report.Base)
+	assert.Equal(t, models.EnvironmentProduction, report.Target)
+	assert.Equal(t, 3, report.Compared)
+	assert.Equal(t, 2, report.Drifted)
+	require.Len(t, report.Banks, 2)
+
+	santander := report.Banks[0]
+	assert.Equal(t, "BES0049", santander.BankID)
+	assert.Equal(t, models.DriftStatusDifferent, santander.Status)
+	assert.Equal(t, []models.EnvironmentDriftField{
+		{Field: "instant_payments_limit", Base: float64(30000), Target: float64(15000)},
+		{Field: "ok_status_codes_instant_payment", Base: []any{"ACSC", "ACCP"}, Target: []any{"ACSC"}},
+	}, santander.Fields)
+
+	millennium := report.Banks[1]
+	assert.Equal(t, models.DriftStatusMissingInTarget, millennium.Status)
+	assert.Len(t, millennium.Fields, len(driftComparedFields))
+	assert.Equal(t, models.EnvironmentDriftField{Field: "enabled", Base: true, Target: nil}, millennium.Fields[0])
+}
+
+func TestEnvironmentDriftService_GetEnvironmentDrift_InvalidEnvironments(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		base        string
+		target      string
+		expectedErr string
+	}{
+		{"missing base", "", "", "production", "invalid request: base must be one of"},
+		{"unknown target", "", "uat", "prod", "invalid request: target must be one of"},
+		{"same environment", "", "uat", "uat", "invalid request: target must differ from base"},
+		{"environment filter", "uat", "uat", "production", "invalid request: env is not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bankRepo := new(MockBankRepository)
+			service := NewEnvironmentDriftService(bankRepo)
+
+			_, err := service.GetEnvironmentDrift(context.Background(), &repository.BankFilters{Environment: tt.environment}, tt.base, tt.target)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			bankRepo.AssertNotCalled(t, "StreamBanks", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWriteEnvironmentDriftCSV(t *testing.T) {
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentDriftService(bankRepo)
+	bankRepo.On("StreamBanks", mock.Anything, mock.Anything, mock.Anything).Return(driftTestBanks()[:1], nil)
+
+	report, err := service.GetEnvironmentDrift(context.Background(), &repository.BankFilters{}, "uat", "production")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteEnvironmentDriftCSV(&buf, report))
+
+	assert.Equal(t, "bank_id,name,country,status,field,base,target\n"+
+		"BES0049,Santander,ES,different,instant_payments_limit,30000,15000\n"+
+		"BES0049,Santander,ES,different,ok_status_codes_instant_payment,ACSC;ACCP,ACSC\n", buf.String())
+}
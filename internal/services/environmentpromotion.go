@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// MaxPromotedBanks caps the number of banks of a single bulk promotion
+const MaxPromotedBanks = 10000
+
+// promotableEnvironmentConfigFields are the environment config fields a promotion can copy, in the
+// order they are reported. The identity, version and timestamps of a config are never copied.
+var promotableEnvironmentConfigFields = []string{
+	"enabled",
+	"blocked",
+	"blocked_text",
+	"risky",
+	"risky_message",
+	"supports_instant_payments",
+	"instant_payments_activated",
+	"instant_payments_limit",
+	"ok_status_codes_simple_payment",
+	"ok_status_codes_instant_payment",
+	"ok_status_codes_periodic_payment",
+	"enabled_periodic_payment",
+	"frequency_periodic_payment",
+	"config_periodic_payment",
+	"app_auth_setup_required",
+}
+
+// PromoteEnvironmentRequest selects what a promotion copies. Fields defaults to every promotable field.
+type PromoteEnvironmentRequest struct {
+	From   string
+	To     string
+	Fields []string
+	DryRun bool
+}
+
+// EnvironmentPromotionService copies the values of the environment config validated in one environment
+// to another, for a single bank or for every bank matching the listing filters
+type EnvironmentPromotionService interface {
+	PromoteEnvironmentConfig(ctx context.Context, bankID string, request *PromoteEnvironmentRequest, precondition VersionPrecondition) (*models.EnvironmentPromotionReport, error)
+	PromoteEnvironmentConfigs(ctx context.Context, filters *repository.BankFilters, request *PromoteEnvironmentRequest) (*models.EnvironmentPromotionReport, error)
+}
+
+type environmentPromotionService struct {
+	writer repository.BankWriter
+	reader repository.BankRepository
+	banks  BankService
+}
+
+func NewEnvironmentPromotionService(writer repository.BankWriter, reader repository.BankRepository) EnvironmentPromotionService {
+	return &environmentPromotionService{
+		writer: writer,
+		reader: reader,
+		banks:  NewBankService(reader),
+	}
+}
+
+// PromoteEnvironmentConfig promotes the config of a single bank. Failures are returned as errors rather
+// than in the report. The precondition applies to the target config, whose version is zero when it does
+// not exist yet.
+func (s *environmentPromotionService) PromoteEnvironmentConfig(ctx context.Context, bankID string, request *PromoteEnvironmentRequest, precondition VersionPrecondition) (*models.EnvironmentPromotionReport, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := validatePromoteEnvironmentRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion := &repository.EnvironmentPromotion{
+		BankID: bankID,
+		From:   models.EnvironmentType(request.From),
+		To:     models.EnvironmentType(request.To),
+		Fields: fields,
+	}
+
+	// The precondition is checked against the version that was read, which the write then pins
+	if precondition != nil {
+		configs, err := s.reader.GetBankEnvironmentConfigs(ctx, bankID, request.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environment configs: %w", err)
+		}
+		var version int64
+		if config, ok := configs[request.To]; ok {
+			version = config.Version
+		}
+		if err := checkVersion(precondition, "environment config", version); err != nil {
+			return nil, err
+		}
+		promotion.Version = &version
+	}
+
+	report, errs, err := s.promote(ctx, []*repository.EnvironmentPromotion{promotion}, request, fields)
+	if err != nil {
+		return nil, err
+	}
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	return report, nil
+}
+
+// PromoteEnvironmentConfigs promotes the configs of every bank matching the filters that has a config
+// of the source environment, whose state the environment filters apply to. Either every bank is
+// promoted or none is; the report tells which banks failed.
+func (s *environmentPromotionService) PromoteEnvironmentConfigs(ctx context.Context, filters *repository.BankFilters, request *PromoteEnvironmentRequest) (*models.EnvironmentPromotionReport, error) {
+	fields, err := validatePromoteEnvironmentRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// The filters select the banks by their source config; any other environment is a conflict
+	if filters.Environment != "" && filters.Environment != request.From {
+		return nil, fmt.Errorf("invalid request: env must be empty or match the source environment %s", request.From)
+	}
+	filters.Environment = request.From
+	errTooManyBanks := fmt.Errorf("invalid request: the filters match more than %d banks", MaxPromotedBanks)
+
+	var promotions []*repository.EnvironmentPromotion
+	err = s.banks.ExportBanks(ctx, filters, func(bank *models.BankWithEnvironments) error {
+		if len(promotions) == MaxPromotedBanks {
+			return errTooManyBanks
+		}
+		promotions = append(promotions, &repository.EnvironmentPromotion{
+			BankID: bank.BankID,
+			From:   models.EnvironmentType(request.From),
+			To:     models.EnvironmentType(request.To),
+			Fields: fields,
+		})
+		return nil
+	})
+	if err != nil {
+		// Filter errors and the bank limit are the client's to fix
+		if strings.Contains(err.Error(), "invalid") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to list banks: %w", err)
+	}
+
+	report, _, err := s.promote(ctx, promotions, request, fields)
+	return report, err
+}
+
+// promote applies the promotions and summarizes their outcome. It also returns the error of each
+// promotion, in order.
+func (s *environmentPromotionService) promote(ctx context.Context, promotions []*repository.EnvironmentPromotion, request *PromoteEnvironmentRequest, fields []string) (*models.EnvironmentPromotionReport, []error, error) {
+	report := &models.EnvironmentPromotionReport{
+		From:   models.EnvironmentType(request.From),
+		To:     models.EnvironmentType(request.To),
+		Fields: fields,
+		DryRun: request.DryRun,
+		Total:  len(promotions),
+		Banks:  make([]models.EnvironmentPromotionResult, 0, len(promotions)),
+	}
+	if len(promotions) == 0 {
+		return report, nil, nil
+	}
+
+	outcomes, err := s.writer.PromoteEnvironmentConfigs(ctx, promotions, request.DryRun)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to promote environment configs: %w", err)
+	}
+
+	errs := make([]error, len(outcomes))
+	for i, outcome := range outcomes {
+		result := models.EnvironmentPromotionResult{
+			BankID:  promotions[i].BankID,
+			Created: outcome.Created,
+			Changes: outcome.Changes,
+			Version: outcome.Version,
+			Success: outcome.Err == nil,
+		}
+		if outcome.Err != nil {
+			result.Error = stringPtr(outcome.Err.Error())
+			errs[i] = outcome.Err
+			report.Failed++
+		} else if outcome.Created || len(outcome.Changes) > 0 {
+			report.Changed++
+		}
+		report.Banks = append(report.Banks, result)
+	}
+
+	report.Applied = !request.DryRun && report.Failed == 0 && report.Changed > 0
+	return report, errs, nil
+}
+
+// validatePromoteEnvironmentRequest checks the environments of a promotion and returns the fields it
+// copies, deduplicated and in their canonical order
+func validatePromoteEnvironmentRequest(request *PromoteEnvironmentRequest) ([]string, error) {
+	if !isValidEnvironment(request.From) {
+		return nil, fmt.Errorf("invalid request: source environment must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+	if !isValidEnvironment(request.To) {
+		return nil, fmt.Errorf("invalid request: to must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+	if request.From == request.To {
+		return nil, errors.New("invalid request: to must differ from the source environment")
+	}
+
+	if len(request.Fields) == 0 {
+		return promotableEnvironmentConfigFields, nil
+	}
+	for _, field := range request.Fields {
+		if !slices.Contains(promotableEnvironmentConfigFields, field) {
+			return nil, fmt.Errorf("invalid request: field %s cannot be promoted (allowed: %s)",
+				field, strings.Join(promotableEnvironmentConfigFields, ", "))
+		}
+	}
+
+	fields := make([]string, 0, len(request.Fields))
+	for _, field := range promotableEnvironmentConfigFields {
+		if slices.Contains(request.Fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
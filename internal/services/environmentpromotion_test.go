@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfig(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentPromotionService(writer, bankRepo)
+
+	changes := []models.FieldChange{{Field: "instant_payments_limit", Before: nil, After: float64(15000)}}
+	writer.On("PromoteEnvironmentConfigs", mock.Anything, mock.MatchedBy(func(promotions []*repository.EnvironmentPromotion) bool {
+		// Requested fields are deduplicated and put in their canonical order
+		return len(promotions) == 1 &&
+			promotions[0].BankID == "BES0049" &&
+			promotions[0].From == models.EnvironmentUAT &&
+			promotions[0].To == models.EnvironmentProduction &&
+			assert.ObjectsAreEqual([]string{"risky", "instant_payments_limit"}, promotions[0].Fields) &&
+			promotions[0].Version == nil
+	}), true).Return([]repository.EnvironmentPromotionOutcome{{Changes: changes, Version: 7}}, nil)
+
+	report, err := service.PromoteEnvironmentConfig(context.Background(), " BES0049 ", &PromoteEnvironmentRequest{
+		From:   "uat",
+		To:     "production",
+		Fields: []string{"instant_payments_limit", "risky", "risky"},
+		DryRun: true,
+	}, nil)
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.False(t, report.Applied)
+	assert.Equal(t, 1, report.Total)
+	assert.Equal(t, 1, report.Changed)
+	require.Len(t, report.Banks, 1)
+	assert.True(t, report.Banks[0].Success)
+	assert.Equal(t, changes, report.Banks[0].Changes)
+	writer.AssertExpectations(t)
+}
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfig_Precondition(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentPromotionService(writer, bankRepo)
+
+	bankRepo.On("GetBankEnvironmentConfigs", mock.Anything, "BES0049", "production").Return(map[string]*models.BankEnvironmentConfig{
+		"production": {BankID: "BES0049", Environment: models.EnvironmentProduction, Version: 6},
+	}, nil)
+	request := &PromoteEnvironmentRequest{From: "uat", To: "production"}
+
+	_, err := service.PromoteEnvironmentConfig(context.Background(), "BES0049", request, VersionPrecondition{5})
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	writer.AssertNotCalled(t, "PromoteEnvironmentConfigs", mock.Anything, mock.Anything, mock.Anything)
+
+	// A matching precondition pins the version that was read
+	writer.On("PromoteEnvironmentConfigs", mock.Anything, mock.MatchedBy(func(promotions []*repository.EnvironmentPromotion) bool {
+		return promotions[0].Version != nil && *promotions[0].Version == 6 &&
+			len(promotions[0].Fields) == len(promotableEnvironmentConfigFields)
+	}), false).Return([]repository.EnvironmentPromotionOutcome{{Version: 6, Changes: []models.FieldChange{}}}, nil)
+
+	report, err := service.PromoteEnvironmentConfig(context.Background(), "BES0049", request, VersionPrecondition{6})
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Equal(t, 0, report.Changed)
+}
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *PromoteEnvironmentRequest
+		expectedErr string
+	}{
+		{"unknown source", &PromoteEnvironmentRequest{From: "staging", To: "production"}, "invalid request: source environment must be one of"},
+		{"unknown target", &PromoteEnvironmentRequest{From: "uat", To: "prod"}, "invalid request: to must be one of"},
+		{"same environment", &PromoteEnvironmentRequest{From: "uat", To: "uat"}, "invalid request: to must differ from the source environment"},
+		{"read-only field", &PromoteEnvironmentRequest{From: "uat", To: "production", Fields: []string{"version"}}, "invalid request: field version cannot be promoted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := new(MockBankWriter)
+			service := NewEnvironmentPromotionService(writer, new(MockBankRepository))
+
+			_, err := service.PromoteEnvironmentConfig(context.Background(), "BES0049", tt.request, nil)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			writer.AssertNotCalled(t, "PromoteEnvironmentConfigs", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	// The failure of the only promotion is the failure of the request
+	writer := new(MockBankWriter)
+	service := NewEnvironmentPromotionService(writer, new(MockBankRepository))
+	writer.On("PromoteEnvironmentConfigs", mock.Anything, mock.Anything, false).Return([]repository.EnvironmentPromotionOutcome{
+		{Err: errors.New("environment config 'uat' of bank 'BES0049' not found")},
+	}, nil)
+
+	_, err := service.PromoteEnvironmentConfig(context.Background(), "BES0049", &PromoteEnvironmentRequest{From: "uat", To: "production"}, nil)
+	require.EqualError(t, err, "environment config 'uat' of bank 'BES0049' not found")
+}
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfigs(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentPromotionService(writer, bankRepo)
+
+	blocked := false
+	bankRepo.On("StreamBanks", mock.Anything, mock.MatchedBy(func(filters *repository.BankFilters) bool {
+		// Only banks with a config of the source environment are promoted
+		return filters.Environment == "uat" && filters.Countries[0] == "ES" && *filters.EnvironmentState.Blocked == blocked
+	}), mock.Anything).Return([]models.BankWithEnvironments{
+		{Bank: models.Bank{BankID: "BES0049"}},
+		{Bank: models.Bank{BankID: "BES0081"}},
+		{Bank: models.Bank{BankID: "BES0182"}},
+	}, nil)
+	writer.On("PromoteEnvironmentConfigs", mock.Anything, mock.MatchedBy(func(promotions []*repository.EnvironmentPromotion) bool {
+		return len(promotions) == 3 && promotions[2].BankID == "BES0182"
+	}), false).Return([]repository.EnvironmentPromotionOutcome{
+		{Created: true, Changes: []models.FieldChange{{Field: "enabled", After: true}}, Version: 1},
+		{Changes: []models.FieldChange{}, Version: 3},
+		{Changes: []models.FieldChange{{Field: "risky", Before: true, After: false}}, Version: 9},
+	}, nil)
+
+	report, err := service.PromoteEnvironmentConfigs(context.Background(),
+		&repository.BankFilters{Countries: []string{"es"}, EnvironmentState: repository.EnvironmentStateFilters{Blocked: &blocked}},
+		&PromoteEnvironmentRequest{From: "uat", To: "production", Fields: []string{"enabled", "risky"}})
+
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.Changed)
+	assert.Equal(t, 0, report.Failed)
+	assert.Equal(t, []string{"enabled", "risky"}, report.Fields)
+	assert.True(t, report.Banks[0].Created)
+}
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfigs_ConflictingEnvironment(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentPromotionService(writer, bankRepo)
+
+	_, err := service.PromoteEnvironmentConfigs(context.Background(), &repository.BankFilters{Environment: "production"},
+		&PromoteEnvironmentRequest{From: "uat", To: "production"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request: env must be empty or match the source environment uat")
+	bankRepo.AssertNotCalled(t, "StreamBanks", mock.Anything, mock.Anything, mock.Anything)
+	writer.AssertNotCalled(t, "PromoteEnvironmentConfigs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfigs_Failures(t *testing.T) {
+	writer := new(MockBankWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewEnvironmentPromotionService(writer, bankRepo)
+
+	bankRepo.On("StreamBanks", mock.Anything, mock.Anything, mock.Anything).Return([]models.BankWithEnvironments{
+		{Bank: models.Bank{BankID: "BES0049"}},
+		{Bank: models.Bank{BankID: "BES0081"}},
+	}, nil)
+	writer.On("PromoteEnvironmentConfigs", mock.Anything, mock.Anything, false).Return([]repository.EnvironmentPromotionOutcome{
+		{Changes: []models.FieldChange{{Field: "risky", Before: true, After: false}}, Version: 9},
+		{Err: errors.New("bank with ID 'BES0081' not found")},
+	}, nil)
+
+	report, err := service.PromoteEnvironmentConfigs(context.Background(), &repository.BankFilters{},
+		&PromoteEnvironmentRequest{From: "uat", To: "production"})
+
+	require.NoError(t, err)
+	// A failed bank rolls back the promotion of every other bank
+	assert.False(t, report.Applied)
+	assert.Equal(t, 1, report.Failed)
+	assert.False(t, report.Banks[1].Success)
+	assert.Equal(t, "bank with ID 'BES0081' not found", *report.Banks[1].Error)
+}
+
+func TestEnvironmentPromotionService_PromoteEnvironmentConfigs_InvalidFilters(t *testing.T) {
+	writer := new(MockBankWriter)
+	service := NewEnvironmentPromotionService(writer, new(MockBankRepository))
+
+	_, err := service.PromoteEnvironmentConfigs(context.Background(), &repository.BankFilters{BankGroupID: "not-a-uuid"},
+		&PromoteEnvironmentRequest{From: "uat", To: "production"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid bank_group_id")
+	writer.AssertNotCalled(t, "PromoteEnvironmentConfigs", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wukong0111/go-banks/internal/iban"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// MaxIBANsPerRequest caps the batch size of a single resolution request
+const MaxIBANsPerRequest = 1000
+
+// ResolveIBANsRequest represents a batch of IBANs to resolve to banks
+type ResolveIBANsRequest struct {
+	IBANs []string `json:"ibans" binding:"required"`
+}
+
+// IBANResolver defines the interface for resolving IBANs to banks
+type IBANResolver interface {
+	ResolveIBANs(ctx context.Context, request *ResolveIBANsRequest) ([]models.IBANResolution, error)
+}
+
+// IBANResolverService validates IBANs and matches their national bank identifier against bank_codes
+type IBANResolverService struct {
+	reader repository.BankRepository
+}
+
+// NewIBANResolverService creates a new IBANResolverService
+func NewIBANResolverService(reader repository.BankRepository) *IBANResolverService {
+	return &IBANResolverService{
+		reader: reader,
+	}
+}
+
+// ResolveIBANs resolves every IBAN independently: invalid IBANs get a per-item error instead of
+// failing the batch. All bank lookups are done with a single repository query.
+func (s *IBANResolverService) ResolveIBANs(ctx context.Context, request *ResolveIBANsRequest) ([]models.IBANResolution, error) {
+	if len(request.IBANs) == 0 {
+		return nil, errors.New("invalid request: at least one IBAN is required")
+	}
+	if len(request.IBANs) > MaxIBANsPerRequest {
+		return nil, fmt.Errorf("invalid request: at most %d IBANs are allowed per request", MaxIBANsPerRequest)
+	}
+
+	resolutions := make([]models.IBANResolution, len(request.IBANs))
+	codes := make([]string, 0, len(request.IBANs))
+	seenCodes := make(map[string]bool)
+
+	for i, input := range request.IBANs {
+		resolutions[i] = models.IBANResolution{
+			IBAN:  iban.Normalize(input),
+			Banks: []models.Bank{},
+		}
+
+		parsed, err := iban.Parse(input)
+		if err != nil {
+			resolutions[i].Error = stringPtr(err.Error())
+			continue
+		}
+
+		resolutions[i].Valid = true
+		resolutions[i].Country = parsed.Country
+		resolutions[i].BankCode = parsed.BankCode
+
+		if !seenCodes[parsed.BankCode] {
+			seenCodes[parsed.BankCode] = true
+			codes = append(codes, parsed.BankCode)
+		}
+	}
+
+	if len(codes) == 0 {
+		return resolutions, nil
+	}
+
+	banks, err := s.reader.GetBanksByCodes(ctx, codes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get banks by codes: %w", err)
+	}
+
+	// Index banks by country and code, since national codes are only unique within a country
+	banksByCode := make(map[string][]models.Bank)
+	for i := range banks {
+		for _, code := range banks[i].BankCodes {
+			key := banks[i].Country + ":" + code
+			banksByCode[key] = append(banksByCode[key], banks[i])
+		}
+	}
+
+	for i := range resolutions {
+		if !resolutions[i].Valid {
+			continue
+		}
+
+		matches := banksByCode[resolutions[i].Country+":"+resolutions[i].BankCode]
+		if len(matches) == 0 {
+			resolutions[i].Error = stringPtr("bank not found for bank code " + resolutions[i].BankCode)
+			continue
+		}
+		resolutions[i].Banks = matches
+	}
+
+	return resolutions, nil
+}
+
+// stringPtr returns a pointer to a copy of s
+func stringPtr(s string) *string {
+	return &s
+}
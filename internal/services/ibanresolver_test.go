@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+func TestIBANResolverService_ResolveIBANs(t *testing.T) {
+	caixa := models.Bank{BankID: "BES2100", Name: "CaixaBank", Country: "ES", BankCodes: []string{"2100", "ES-2100"}}
+	// Same national code in another country must not match Spanish IBANs
+	foreign := models.Bank{BankID: "BPT2100", Name: "Foreign Bank", Country: "PT", BankCodes: []string{"2100"}}
+
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByCodes", mock.Anything, []string{"2100", "37040044"}).Return([]models.Bank{caixa, foreign}, nil)
+
+	service := NewIBANResolverService(mockRepo)
+
+	result, err := service.ResolveIBANs(context.Background(), &ResolveIBANsRequest{
+		IBANs: []string{
+			"es91 2100 0418 4502 0005 1332",
+			"ES9221000418450200051332",
+			"DE89370400440532013000",
+			"ES7921000813610123456789",
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+
+	assert.True(t, result[0].Valid)
+	assert.Equal(t, "ES9121000418450200051332", result[0].IBAN)
+	assert.Equal(t, "ES", result[0].Country)
+	assert.Equal(t, "2100", result[0].BankCode)
+	assert.Equal(t, []models.Bank{caixa}, result[0].Banks)
+	assert.Nil(t, result[0].Error)
+
+	assert.False(t, result[1].Valid)
+	assert.Empty(t, result[1].Banks)
+	require.NotNil(t, result[1].Error)
+	assert.Contains(t, *result[1].Error, "invalid checksum")
+
+	assert.True(t, result[2].Valid)
+	assert.Empty(t, result[2].Banks)
+	require.NotNil(t, result[2].Error)
+	assert.Contains(t, *result[2].Error, "bank not found")
+
+	assert.True(t, result[3].Valid)
+	assert.Equal(t, []models.Bank{caixa}, result[3].Banks)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIBANResolverService_ResolveIBANs_AllInvalid(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewIBANResolverService(mockRepo)
+
+	result, err := service.ResolveIBANs(context.Background(), &ResolveIBANsRequest{
+		IBANs: []string{"not an iban"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.False(t, result[0].Valid)
+	mockRepo.AssertNotCalled(t, "GetBanksByCodes", mock.Anything, mock.Anything)
+}
+
+func TestIBANResolverService_ResolveIBANs_BatchLimits(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	service := NewIBANResolverService(mockRepo)
+
+	_, err := service.ResolveIBANs(context.Background(), &ResolveIBANsRequest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request")
+
+	_, err = service.ResolveIBANs(context.Background(), &ResolveIBANsRequest{
+		IBANs: make([]string, MaxIBANsPerRequest+1),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request")
+}
+
+func TestIBANResolverService_ResolveIBANs_RepositoryError(t *testing.T) {
+	mockRepo := new(MockBankRepository)
+	mockRepo.On("GetBanksByCodes", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	service := NewIBANResolverService(mockRepo)
+
+	result, err := service.ResolveIBANs(context.Background(), &ResolveIBANsRequest{
+		IBANs: []string{"ES9121000418450200051332"},
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "database error")
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/cron"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// MaxMaintenanceWindowMinutes caps the duration of a recurring maintenance window to a week
+const MaxMaintenanceWindowMinutes = 7 * 24 * 60
+
+// MaintenanceWindowService manages the maintenance windows of banks
+type MaintenanceWindowService interface {
+	CreateMaintenanceWindow(ctx context.Context, bankID string, request *CreateMaintenanceWindowRequest) (*models.MaintenanceWindow, error)
+	GetMaintenanceWindows(ctx context.Context, bankID, environment string) ([]models.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, bankID, windowID string) error
+}
+
+// CreateMaintenanceWindowRequest describes a maintenance window. A one_off window needs StartsAt and
+// EndsAt; a recurring one needs Schedule, a five-field cron expression evaluated in Timezone (UTC by
+// default), and DurationMinutes.
+type CreateMaintenanceWindowRequest struct {
+	Environment     string     `json:"environment" binding:"required"`
+	Kind            string     `json:"kind" binding:"required"`
+	StartsAt        *time.Time `json:"starts_at,omitempty"`
+	EndsAt          *time.Time `json:"ends_at,omitempty"`
+	Schedule        *string    `json:"schedule,omitempty"`
+	DurationMinutes *int       `json:"duration_minutes,omitempty"`
+	Timezone        string     `json:"timezone,omitempty"`
+	Impact          string     `json:"impact" binding:"required"`
+	Reason          *string    `json:"reason,omitempty"`
+}
+
+type maintenanceWindowService struct {
+	writer   repository.MaintenanceWindowWriter
+	bankRepo repository.BankRepository
+}
+
+func NewMaintenanceWindowService(writer repository.MaintenanceWindowWriter, bankRepo repository.BankRepository) MaintenanceWindowService {
+	return &maintenanceWindowService{
+		writer:   writer,
+		bankRepo: bankRepo,
+	}
+}
+
+// CreateMaintenanceWindow validates and stores a maintenance window of an environment config
+func (s *maintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, bankID string, request *CreateMaintenanceWindowRequest) (*models.MaintenanceWindow, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMaintenanceWindowRequest(request, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkBank(ctx, bankID); err != nil {
+		return nil, err
+	}
+
+	window := &models.MaintenanceWindow{
+		WindowID:    uuid.New(),
+		BankID:      bankID,
+		Environment: models.EnvironmentType(request.Environment),
+		Kind:        request.Kind,
+		Timezone:    "UTC",
+		Impact:      request.Impact,
+		Reason:      request.Reason,
+		CreatedBy:   audit.ActorFromContext(ctx).Subject,
+	}
+	if request.Kind == models.MaintenanceWindowOneOff {
+		startsAt, endsAt := request.StartsAt.UTC(), request.EndsAt.UTC()
+		window.StartsAt, window.EndsAt = &startsAt, &endsAt
+	} else {
+		schedule := strings.Join(strings.Fields(*request.Schedule), " ")
+		window.Schedule = &schedule
+		window.DurationMinutes = request.DurationMinutes
+		if request.Timezone != "" {
+			window.Timezone = request.Timezone
+		}
+	}
+
+	if err := s.writer.CreateMaintenanceWindow(ctx, window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// GetMaintenanceWindows lists the maintenance windows of a bank, optionally of a single environment
+func (s *maintenanceWindowService) GetMaintenanceWindows(ctx context.Context, bankID, environment string) ([]models.MaintenanceWindow, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+	if environment != "" && !isValidEnvironment(environment) {
+		return nil, fmt.Errorf("invalid request: environment must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+
+	windows, err := s.bankRepo.GetMaintenanceWindows(ctx, bankID, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(windows) == 0 {
+		if err := s.checkBank(ctx, bankID); err != nil {
+			return nil, err
+		}
+	}
+
+	return windows, nil
+}
+
+// DeleteMaintenanceWindow removes a maintenance window of a bank
+func (s *maintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, bankID, windowID string) error {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(windowID))
+	if err != nil {
+		return errors.New("invalid request: window ID must be a valid UUID")
+	}
+
+	return s.writer.DeleteMaintenanceWindow(ctx, bankID, id)
+}
+
+func (s *maintenanceWindowService) checkBank(ctx context.Context, bankID string) error {
+	if _, err := s.bankRepo.GetBankByID(ctx, bankID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("bank not found")
+		}
+		return fmt.Errorf("failed to get bank: %w", err)
+	}
+	return nil
+}
+
+func validateMaintenanceWindowRequest(request *CreateMaintenanceWindowRequest, now time.Time) error {
+	if !isValidEnvironment(request.Environment) {
+		return fmt.Errorf("invalid request: environment must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+	if request.Impact != models.EffectiveStatusBlocked && request.Impact != models.EffectiveStatusDegraded {
+		return fmt.Errorf("invalid request: impact must be one of %s, %s", models.EffectiveStatusBlocked, models.EffectiveStatusDegraded)
+	}
+
+	switch request.Kind {
+	case models.MaintenanceWindowOneOff:
+		if request.Schedule != nil || request.DurationMinutes != nil || request.Timezone != "" {
+			return errors.New("invalid request: schedule, duration_minutes and timezone only apply to recurring windows")
+		}
+		if request.StartsAt == nil || request.EndsAt == nil {
+			return errors.New("invalid request: starts_at and ends_at are required for one_off windows")
+		}
+		if !request.EndsAt.After(*request.StartsAt) {
+			return errors.New("invalid request: ends_at must be after starts_at")
+		}
+		if !request.EndsAt.After(now) {
+			return errors.New("invalid request: ends_at must be in the future")
+		}
+
+	case models.MaintenanceWindowRecurring:
+		if request.StartsAt != nil || request.EndsAt != nil {
+			return errors.New("invalid request: starts_at and ends_at only apply to one_off windows")
+		}
+		if request.Schedule == nil || request.DurationMinutes == nil {
+			return errors.New("invalid request: schedule and duration_minutes are required for recurring windows")
+		}
+		schedule, err := cron.Parse(*request.Schedule)
+		if err != nil {
+			return fmt.Errorf("invalid request: invalid schedule: %w", err)
+		}
+		if _, err := schedule.Next(now); err != nil {
+			return fmt.Errorf("invalid request: invalid schedule: %w", err)
+		}
+		if *request.DurationMinutes < 1 || *request.DurationMinutes > MaxMaintenanceWindowMinutes {
+			return fmt.Errorf("invalid request: duration_minutes must be between 1 and %d", MaxMaintenanceWindowMinutes)
+		}
+		if request.Timezone != "" {
+			if _, err := time.LoadLocation(request.Timezone); err != nil {
+				return fmt.Errorf("invalid request: unknown timezone %s", request.Timezone)
+			}
+		}
+
+	default:
+		return fmt.Errorf("invalid request: kind must be one of %s, %s", models.MaintenanceWindowOneOff, models.MaintenanceWindowRecurring)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockMaintenanceWindowWriter implements the MaintenanceWindowWriter interface for testing
+type MockMaintenanceWindowWriter struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceWindowWriter) CreateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	args := m.Called(ctx, window)
+	return args.Error(0)
+}
+
+func (m *MockMaintenanceWindowWriter) DeleteMaintenanceWindow(ctx context.Context, bankID string, windowID uuid.UUID) error {
+	args := m.Called(ctx, bankID, windowID)
+	return args.Error(0)
+}
+
+func nightlyWindowRequest() *CreateMaintenanceWindowRequest {
+	schedule := "0  1 * * 1-5"
+	minutes := 90
+	return &CreateMaintenanceWindowRequest{
+		Environment:     "production",
+		Kind:            models.MaintenanceWindowRecurring,
+		Schedule:        &schedule,
+		DurationMinutes: &minutes,
+		Timezone:        "Europe/Madrid",
+		Impact:          models.EffectiveStatusDegraded,
+	}
+}
+
+func TestMaintenanceWindowService_CreateMaintenanceWindow(t *testing.T) {
+	writer := new(MockMaintenanceWindowWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewMaintenanceWindowService(writer, bankRepo)
+
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+	writer.On("CreateMaintenanceWindow", mock.Anything, mock.AnythingOfType("*models.MaintenanceWindow")).Return(nil)
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{Subject: "ops@example.com"})
+	window, err := service.CreateMaintenanceWindow(ctx, " BES0049 ", nightlyWindowRequest())
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, window.WindowID)
+	assert.Equal(t, "BES0049", window.BankID)
+	assert.Equal(t, models.EnvironmentProduction, window.Environment)
+	require.NotNil(t, window.Schedule)
+	assert.Equal(t, "0 1 * * 1-5", *window.Schedule)
+	assert.Equal(t, 90, *window.DurationMinutes)
+	assert.Equal(t, "Europe/Madrid", window.Timezone)
+	assert.Nil(t, window.StartsAt)
+	assert.Equal(t, "ops@example.com", window.CreatedBy)
+	writer.AssertExpectations(t)
+}
+
+func TestMaintenanceWindowService_CreateMaintenanceWindow_OneOff(t *testing.T) {
+	writer := new(MockMaintenanceWindowWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewMaintenanceWindowService(writer, bankRepo)
+
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+	writer.On("CreateMaintenanceWindow", mock.Anything, mock.AnythingOfType("*models.MaintenanceWindow")).Return(nil)
+
+	startsAt := time.Now().Add(time.Hour).In(time.FixedZone("CET", 3600))
+	endsAt := startsAt.Add(4 * time.Hour)
+	window, err := service.CreateMaintenanceWindow(context.Background(), "BES0049", &CreateMaintenanceWindowRequest{
+		Environment: "sandbox",
+		Kind:        models.MaintenanceWindowOneOff,
+		StartsAt:    &startsAt,
+		EndsAt:      &endsAt,
+		Impact:      models.EffectiveStatusBlocked,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, window.StartsAt.Location())
+	assert.True(t, window.EndsAt.Equal(endsAt))
+	assert.Equal(t, "UTC", window.Timezone)
+	assert.Nil(t, window.Schedule)
+}
+
+func TestMaintenanceWindowService_CreateMaintenanceWindow_Errors(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	zero := 0
+
+	tests := []struct {
+		name        string
+		modify      func(request *CreateMaintenanceWindowRequest)
+		expectedErr string
+	}{
+		{"unknown environment", func(r *CreateMaintenanceWindowRequest) { r.Environment = "staging" }, "invalid request: environment must be one of"},
+		{"unknown impact", func(r *CreateMaintenanceWindowRequest) { r.Impact = "down" }, "invalid request: impact must be one of"},
+		{"unknown kind", func(r *CreateMaintenanceWindowRequest) { r.Kind = "weekly" }, "invalid request: kind must be one of"},
+		{"invalid schedule", func(r *CreateMaintenanceWindowRequest) { s := "0 25 * * *"; r.Schedule = &s }, "invalid request: invalid schedule"},
+		{"missing duration", func(r *CreateMaintenanceWindowRequest) { r.DurationMinutes = nil }, "invalid request: schedule and duration_minutes are required"},
+		{"zero duration", func(r *CreateMaintenanceWindowRequest) { r.DurationMinutes = &zero }, "invalid request: duration_minutes must be between 1 and"},
+		{"unknown timezone", func(r *CreateMaintenanceWindowRequest) { r.Timezone = "Mars/Olympus" }, "invalid request: unknown timezone"},
+		{"recurring with bounds", func(r *CreateMaintenanceWindowRequest) { r.StartsAt = &future }, "invalid request: starts_at and ends_at only apply to one_off windows"},
+		{"one-off with schedule", func(r *CreateMaintenanceWindowRequest) { r.Kind = models.MaintenanceWindowOneOff }, "invalid request: schedule, duration_minutes and timezone only apply"},
+		{"one-off without bounds", func(r *CreateMaintenanceWindowRequest) {
+			*r = CreateMaintenanceWindowRequest{Environment: "production", Kind: models.MaintenanceWindowOneOff, Impact: models.EffectiveStatusBlocked}
+		}, "invalid request: starts_at and ends_at are required"},
+		{"one-off ending before it starts", func(r *CreateMaintenanceWindowRequest) {
+			*r = CreateMaintenanceWindowRequest{Environment: "production", Kind: models.MaintenanceWindowOneOff, Impact: models.EffectiveStatusBlocked, StartsAt: &future, EndsAt: &past}
+		}, "invalid request: ends_at must be after starts_at"},
+		{"one-off in the past", func(r *CreateMaintenanceWindowRequest) {
+			start := past.Add(-time.Hour)
+			*r = CreateMaintenanceWindowRequest{Environment: "production", Kind: models.MaintenanceWindowOneOff, Impact: models.EffectiveStatusBlocked, StartsAt: &start, EndsAt: &past}
+		}, "invalid request: ends_at must be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := new(MockMaintenanceWindowWriter)
+			bankRepo := new(MockBankRepository)
+			service := NewMaintenanceWindowService(writer, bankRepo)
+			request := nightlyWindowRequest()
+			tt.modify(request)
+
+			_, err := service.CreateMaintenanceWindow(context.Background(), "BES0049", request)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			writer.AssertNotCalled(t, "CreateMaintenanceWindow", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestMaintenanceWindowService_CreateMaintenanceWindow_UnknownBank(t *testing.T) {
+	writer := new(MockMaintenanceWindowWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewMaintenanceWindowService(writer, bankRepo)
+
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(nil, pgx.ErrNoRows)
+
+	_, err := service.CreateMaintenanceWindow(context.Background(), "BES0049", nightlyWindowRequest())
+
+	require.EqualError(t, err, "bank not found")
+	writer.AssertNotCalled(t, "CreateMaintenanceWindow", mock.Anything, mock.Anything)
+}
+
+func TestMaintenanceWindowService_GetMaintenanceWindows(t *testing.T) {
+	writer := new(MockMaintenanceWindowWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewMaintenanceWindowService(writer, bankRepo)
+
+	bankRepo.On("GetMaintenanceWindows", mock.Anything, "BES0049", "uat").Return([]models.MaintenanceWindow{}, nil)
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(nil, pgx.ErrNoRows)
+
+	_, err := service.GetMaintenanceWindows(context.Background(), "BES0049", "uat")
+	require.EqualError(t, err, "bank not found")
+
+	_, err = service.GetMaintenanceWindows(context.Background(), "BES0049", "staging")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request: environment must be one of")
+}
+
+func TestMaintenanceWindowService_DeleteMaintenanceWindow(t *testing.T) {
+	writer := new(MockMaintenanceWindowWriter)
+	bankRepo := new(MockBankRepository)
+	service := NewMaintenanceWindowService(writer, bankRepo)
+
+	windowID := uuid.New()
+	writer.On("DeleteMaintenanceWindow", mock.Anything, "BES0049", windowID).Return(errors.New("maintenance window not found"))
+
+	err := service.DeleteMaintenanceWindow(context.Background(), "BES0049", windowID.String())
+	require.EqualError(t, err, "maintenance window not found")
+
+	err = service.DeleteMaintenanceWindow(context.Background(), "BES0049", "not-a-uuid")
+	require.EqualError(t, err, "invalid request: window ID must be a valid UUID")
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wukong0111/go-banks/internal/jsonpatch"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// applyPatch applies a JSON Merge Patch or JSON Patch document, selected by its media type, to the
+// JSON representation of current and decodes the result into a new value. Members that T does
+// not define are rejected.
+func applyPatch[T any](current *T, mediaType string, patch []byte) (*T, error) {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode current value: %w", err)
+	}
+
+	var patched []byte
+	switch mediaType {
+	case jsonpatch.MergePatchMediaType:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case jsonpatch.JSONPatchMediaType:
+		patched, err = jsonpatch.Apply(doc, patch)
+	default:
+		return nil, fmt.Errorf("unsupported patch media type %q", mediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+
+	var result T
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid patch result: %w", err)
+	}
+	return &result, nil
+}
+
+// fieldErrorsToError joins the errors found in a patched value into a single validation error
+func fieldErrorsToError(fieldErrors []models.FieldError) error {
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		messages = append(messages, fieldError.Field+" "+fieldError.Message)
+	}
+	return fmt.Errorf("invalid patch result: %s", strings.Join(messages, ", "))
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// VersionPrecondition lists the versions that a write accepts the stored resource to be at, as sent
+// in If-Match. A nil precondition, from a missing If-Match or "*", accepts any version.
+type VersionPrecondition []int64
+
+// Matches reports whether a resource at version satisfies the precondition
+func (p VersionPrecondition) Matches(version int64) bool {
+	return p == nil || slices.Contains(p, version)
+}
+
+// checkVersion rejects a write whose precondition does not match the version that was read
+func checkVersion(precondition VersionPrecondition, entity string, version int64) error {
+	if precondition.Matches(version) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is at version %d", repository.ErrVersionConflict, entity, version)
+}
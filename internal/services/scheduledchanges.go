@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/models"
+	"github.com/wukong0111/go-banks/internal/repository"
+)
+
+// ScheduledChangeService schedules changes to the environment configs of banks and applies them when due
+type ScheduledChangeService interface {
+	ScheduleChange(ctx context.Context, bankID string, request *ScheduleChangeRequest) (*models.ScheduledChange, error)
+	GetScheduledChanges(ctx context.Context, bankID, status string) ([]models.ScheduledChange, error)
+	CancelScheduledChange(ctx context.Context, bankID, changeID string) (*models.ScheduledChange, error)
+	ApplyDueChanges(ctx context.Context, limit int) ([]models.ScheduledChange, error)
+}
+
+// ScheduleChangeRequest describes a change to one environment config of a bank. Changes maps
+// config fields to the values they take from ApplyAt on; when RevertAt is set, the replaced
+// values are written back at that time.
+type ScheduleChangeRequest struct {
+	Environment string         `json:"environment" binding:"required"`
+	Changes     map[string]any `json:"changes" binding:"required"`
+	ApplyAt     *time.Time     `json:"apply_at" binding:"required"`
+	RevertAt    *time.Time     `json:"revert_at,omitempty"`
+	Reason      *string        `json:"reason,omitempty"`
+}
+
+// scheduledChangeReadOnlyFields are the environment config fields a scheduled change cannot set
+var scheduledChangeReadOnlyFields = []string{"bank_id", "environment", "version", "created_at", "updated_at"}
+
+// scheduledChangeRequiredFields are the environment config fields that cannot be set to null
+var scheduledChangeRequiredFields = []string{"enabled", "blocked", "risky", "app_auth_setup_required"}
+
+type scheduledChangeService struct {
+	changes  repository.ScheduledChangeRepository
+	bankRepo repository.BankRepository
+}
+
+func NewScheduledChangeService(changes repository.ScheduledChangeRepository, bankRepo repository.BankRepository) ScheduledChangeService {
+	return &scheduledChangeService{
+		changes:  changes,
+		bankRepo: bankRepo,
+	}
+}
+
+// ScheduleChange stores a pending change after checking it against the environment config it targets
+// and against the open changes of that config: two changes that set the same field cannot overlap in
+// time, since the revert of one would undo the other.
+func (s *scheduledChangeService) ScheduleChange(ctx context.Context, bankID string, request *ScheduleChangeRequest) (*models.ScheduledChange, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateScheduleChangeRequest(request, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bankRepo.GetBankByID(ctx, bankID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("bank not found")
+		}
+		return nil, fmt.Errorf("failed to get bank: %w", err)
+	}
+
+	configs, err := s.bankRepo.GetBankEnvironmentConfigs(ctx, bankID, request.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment configs: %w", err)
+	}
+	if _, ok := configs[request.Environment]; !ok {
+		return nil, fmt.Errorf("environment config '%s' of bank '%s' not found", request.Environment, bankID)
+	}
+
+	change := &models.ScheduledChange{
+		ChangeID:    uuid.New(),
+		BankID:      bankID,
+		Environment: models.EnvironmentType(request.Environment),
+		Changes:     request.Changes,
+		ApplyAt:     request.ApplyAt.UTC(),
+		Reason:      request.Reason,
+		Status:      models.ScheduledChangePending,
+		CreatedBy:   audit.ActorFromContext(ctx).Subject,
+	}
+	if request.RevertAt != nil {
+		revertAt := request.RevertAt.UTC()
+		change.RevertAt = &revertAt
+	}
+
+	// The repository runs the check and the insert in one transaction, so two concurrent requests
+	// cannot both pass it
+	err = s.changes.CreateScheduledChange(ctx, change, func(open []models.ScheduledChange) error {
+		for i := range open {
+			existing := &open[i]
+			if existing.Environment != change.Environment || !scheduleWindowsOverlap(existing, change) {
+				continue
+			}
+			if field, ok := sharedChangeField(existing, change); ok {
+				return fmt.Errorf("scheduled change conflicts with change '%s', which also sets %s", existing.ChangeID, field)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// GetScheduledChanges returns the scheduled changes of a bank, optionally only those in a status
+func (s *scheduledChangeService) GetScheduledChanges(ctx context.Context, bankID, status string) ([]models.ScheduledChange, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []string
+	if status != "" {
+		if !slices.Contains(scheduledChangeStatuses, status) {
+			return nil, fmt.Errorf("invalid request: status must be one of %s", strings.Join(scheduledChangeStatuses, ", "))
+		}
+		statuses = []string{status}
+	}
+
+	changes, err := s.changes.GetScheduledChanges(ctx, bankID, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		if _, err := s.bankRepo.GetBankByID(ctx, bankID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errors.New("bank not found")
+			}
+			return nil, fmt.Errorf("failed to get bank: %w", err)
+		}
+	}
+
+	return changes, nil
+}
+
+// CancelScheduledChange cancels a pending change, or reverts an active one right away
+func (s *scheduledChangeService) CancelScheduledChange(ctx context.Context, bankID, changeID string) (*models.ScheduledChange, error) {
+	bankID, err := normalizeBankID(bankID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(changeID))
+	if err != nil {
+		return nil, errors.New("invalid request: change ID must be a valid UUID")
+	}
+
+	return s.changes.CancelScheduledChange(ctx, bankID, id)
+}
+
+// ApplyDueChanges applies or reverts up to limit changes that are due, one transaction each, and
+// returns them. Changes that could not be written are returned in the failed status.
+func (s *scheduledChangeService) ApplyDueChanges(ctx context.Context, limit int) ([]models.ScheduledChange, error) {
+	applied := []models.ScheduledChange{}
+	for len(applied) < limit {
+		change, err := s.changes.ApplyNextDueScheduledChange(ctx, time.Now())
+		if err != nil {
+			return applied, err
+		}
+		if change == nil {
+			break
+		}
+		applied = append(applied, *change)
+	}
+
+	return applied, nil
+}
+
+// scheduledChangeStatuses lists the statuses a scheduled change can be in
+var scheduledChangeStatuses = []string{
+	models.ScheduledChangePending,
+	models.ScheduledChangeActive,
+	models.ScheduledChangeCompleted,
+	models.ScheduledChangeCancelled,
+	models.ScheduledChangeFailed,
+}
+
+func validateScheduleChangeRequest(request *ScheduleChangeRequest, now time.Time) error {
+	if !isValidEnvironment(request.Environment) {
+		return fmt.Errorf("invalid request: environment must be one of %s", strings.Join(validEnvironments, ", "))
+	}
+
+	if len(request.Changes) == 0 {
+		return errors.New("invalid request: changes must set at least one field")
+	}
+	for field, value := range request.Changes {
+		if slices.Contains(scheduledChangeReadOnlyFields, field) {
+			return fmt.Errorf("invalid request: %s is read-only", field)
+		}
+		if value == nil && slices.Contains(scheduledChangeRequiredFields, field) {
+			return fmt.Errorf("invalid request: %s cannot be null", field)
+		}
+	}
+
+	// Decoding into the config model rejects unknown fields and values of the wrong type
+	encoded, err := json.Marshal(request.Changes)
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	var config models.BankEnvironmentConfig
+	if err := decoder.Decode(&config); err != nil {
+		return fmt.Errorf("invalid request: invalid changes: %w", err)
+	}
+
+	if request.ApplyAt == nil {
+		return errors.New("invalid request: apply_at is required")
+	}
+	if request.ApplyAt.Before(now) {
+		return errors.New("invalid request: apply_at must be in the future")
+	}
+	if request.RevertAt != nil && !request.RevertAt.After(*request.ApplyAt) {
+		return errors.New("invalid request: revert_at must be after apply_at")
+	}
+
+	return nil
+}
+
+// scheduleWindowsOverlap reports whether the periods in which two changes are in effect intersect.
+// A change without a revert time is only considered at the instant it applies.
+func scheduleWindowsOverlap(a, b *models.ScheduledChange) bool {
+	aEnd, bEnd := a.ApplyAt, b.ApplyAt
+	if a.RevertAt != nil {
+		aEnd = *a.RevertAt
+	}
+	if b.RevertAt != nil {
+		bEnd = *b.RevertAt
+	}
+	return !a.ApplyAt.After(bEnd) && !b.ApplyAt.After(aEnd)
+}
+
+// sharedChangeField returns the first field, in name order, that both changes set
+func sharedChangeField(a, b *models.ScheduledChange) (string, bool) {
+	fields := make([]string, 0, len(b.Changes))
+	for field := range b.Changes {
+		if _, ok := a.Changes[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return "", false
+	}
+	slices.Sort(fields)
+	return fields[0], true
+}
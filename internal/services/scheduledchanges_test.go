@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wukong0111/go-banks/internal/audit"
+	"github.com/wukong0111/go-banks/internal/models"
+)
+
+// MockScheduledChangeRepository implements the ScheduledChangeRepository interface for testing
+type MockScheduledChangeRepository struct {
+	mock.Mock
+}
+
+// CreateScheduledChange runs check against the open changes given to Return, as the repository does
+// before inserting
+func (m *MockScheduledChangeRepository) CreateScheduledChange(ctx context.Context, change *models.ScheduledChange, check func(open []models.ScheduledChange) error) error {
+	args := m.Called(ctx, change)
+	if open, ok := args.Get(0).([]models.ScheduledChange); ok {
+		if err := check(open); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) GetScheduledChanges(ctx context.Context, bankID string, statuses []string) ([]models.ScheduledChange, error) {
+	args := m.Called(ctx, bankID, statuses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) CancelScheduledChange(ctx context.Context, bankID string, changeID uuid.UUID) (*models.ScheduledChange, error) {
+	args := m.Called(ctx, bankID, changeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduledChange), args.Error(1)
+}
+
+func (m *MockScheduledChangeRepository) ApplyNextDueScheduledChange(ctx context.Context, now time.Time) (*models.ScheduledChange, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduledChange), args.Error(1)
+}
+
+func maintenanceRequest() *ScheduleChangeRequest {
+	applyAt := time.Now().Add(24 * time.Hour)
+	revertAt := applyAt.Add(8 * time.Hour)
+	reason := "ASPSP maintenance"
+	return &ScheduleChangeRequest{
+		Environment: "production",
+		Changes:     map[string]any{"blocked": true, "blocked_text": "Maintenance in progress"},
+		ApplyAt:     &applyAt,
+		RevertAt:    &revertAt,
+		Reason:      &reason,
+	}
+}
+
+func expectScheduledBank(bankRepo *MockBankRepository) {
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+	bankRepo.On("GetBankEnvironmentConfigs", mock.Anything, "BES0049", "production").Return(map[string]*models.BankEnvironmentConfig{
+		"production": {BankID: "BES0049", Environment: models.EnvironmentProduction, Enabled: true},
+	}, nil)
+}
+
+func TestScheduledChangeService_ScheduleChange(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	bankRepo := new(MockBankRepository)
+	service := NewScheduledChangeService(changes, bankRepo)
+	request := maintenanceRequest()
+
+	expectScheduledBank(bankRepo)
+	// An open change of another field in the same window does not conflict
+	changes.On("CreateScheduledChange", mock.Anything, mock.AnythingOfType("*models.ScheduledChange")).Return([]models.ScheduledChange{
+		{
+			ChangeID:    uuid.New(),
+			Environment: models.EnvironmentProduction,
+			Changes:     map[string]any{"risky": true},
+			ApplyAt:     request.ApplyAt.Add(time.Hour),
+			Status:      models.ScheduledChangePending,
+		},
+	}, nil)
+
+	ctx := audit.WithActor(context.Background(), audit.Actor{Subject: "ops@example.com"})
+	change, err := service.ScheduleChange(ctx, " BES0049 ", request)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, change.ChangeID)
+	assert.Equal(t, "BES0049", change.BankID)
+	assert.Equal(t, models.EnvironmentProduction, change.Environment)
+	assert.Equal(t, models.ScheduledChangePending, change.Status)
+	assert.Equal(t, "ops@example.com", change.CreatedBy)
+	assert.Equal(t, request.Changes, change.Changes)
+	assert.True(t, change.ApplyAt.Equal(*request.ApplyAt))
+	require.NotNil(t, change.RevertAt)
+	assert.True(t, change.RevertAt.Equal(*request.RevertAt))
+	changes.AssertExpectations(t)
+}
+
+func TestScheduledChangeService_ScheduleChange_Errors(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		modify      func(request *ScheduleChangeRequest)
+		expectedErr string
+	}{
+		{"unknown environment", func(r *ScheduleChangeRequest) { r.Environment = "staging" }, "invalid request: environment must be one of"},
+		{"no changes", func(r *ScheduleChangeRequest) { r.Changes = map[string]any{} }, "invalid request: changes must set at least one field"},
+		{"read-only field", func(r *ScheduleChangeRequest) { r.Changes = map[string]any{"version": 3} }, "invalid request: version is read-only"},
+		{"null flag", func(r *ScheduleChangeRequest) { r.Changes = map[string]any{"blocked": nil} }, "invalid request: blocked cannot be null"},
+		{"unknown field", func(r *ScheduleChangeRequest) { r.Changes = map[string]any{"colour": "red"} }, "invalid request: invalid changes"},
+		{"wrong type", func(r *ScheduleChangeRequest) { r.Changes = map[string]any{"blocked": "yes"} }, "invalid request: invalid changes"},
+		{"missing apply_at", func(r *ScheduleChangeRequest) { r.ApplyAt = nil }, "invalid request: apply_at is required"},
+		{"apply_at in the past", func(r *ScheduleChangeRequest) { r.ApplyAt = &past; r.RevertAt = nil }, "invalid request: apply_at must be in the future"},
+		{"revert before apply", func(r *ScheduleChangeRequest) { r.RevertAt = r.ApplyAt }, "invalid request: revert_at must be after apply_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := new(MockScheduledChangeRepository)
+			bankRepo := new(MockBankRepository)
+			service := NewScheduledChangeService(changes, bankRepo)
+			request := maintenanceRequest()
+			tt.modify(request)
+
+			_, err := service.ScheduleChange(context.Background(), "BES0049", request)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			changes.AssertNotCalled(t, "CreateScheduledChange", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestScheduledChangeService_ScheduleChange_Overlap(t *testing.T) {
+	request := maintenanceRequest()
+	inWindow := request.ApplyAt.Add(2 * time.Hour)
+	afterWindow := request.RevertAt.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		existing models.ScheduledChange
+		conflict bool
+	}{
+		{
+			name:     "same field inside the window",
+			existing: models.ScheduledChange{Environment: models.EnvironmentProduction, Changes: map[string]any{"blocked": false}, ApplyAt: inWindow},
+			conflict: true,
+		},
+		{
+			name:     "same field after the window",
+			existing: models.ScheduledChange{Environment: models.EnvironmentProduction, Changes: map[string]any{"blocked": false}, ApplyAt: afterWindow},
+		},
+		{
+			name:     "same field in another environment",
+			existing: models.ScheduledChange{Environment: models.EnvironmentUAT, Changes: map[string]any{"blocked": false}, ApplyAt: inWindow},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := new(MockScheduledChangeRepository)
+			bankRepo := new(MockBankRepository)
+			service := NewScheduledChangeService(changes, bankRepo)
+
+			expectScheduledBank(bankRepo)
+			tt.existing.ChangeID = uuid.New()
+			changes.On("CreateScheduledChange", mock.Anything, mock.Anything).Return([]models.ScheduledChange{tt.existing}, nil)
+
+			_, err := service.ScheduleChange(context.Background(), "BES0049", maintenanceRequest())
+
+			if tt.conflict {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "conflicts with change '"+tt.existing.ChangeID.String()+"', which also sets blocked")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestScheduledChangeService_ScheduleChange_MissingEnvironmentConfig(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	bankRepo := new(MockBankRepository)
+	service := NewScheduledChangeService(changes, bankRepo)
+
+	bankRepo.On("GetBankByID", mock.Anything, "BES0049").Return(&models.Bank{BankID: "BES0049"}, nil)
+	bankRepo.On("GetBankEnvironmentConfigs", mock.Anything, "BES0049", "production").Return(map[string]*models.BankEnvironmentConfig{}, nil)
+
+	_, err := service.ScheduleChange(context.Background(), "BES0049", maintenanceRequest())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "environment config 'production' of bank 'BES0049' not found")
+}
+
+func TestScheduledChangeService_GetScheduledChanges(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	bankRepo := new(MockBankRepository)
+	service := NewScheduledChangeService(changes, bankRepo)
+
+	pending := []models.ScheduledChange{{ChangeID: uuid.New(), BankID: "BES0049", Status: models.ScheduledChangePending}}
+	changes.On("GetScheduledChanges", mock.Anything, "BES0049", []string{"pending"}).Return(pending, nil)
+
+	result, err := service.GetScheduledChanges(context.Background(), "BES0049", "pending")
+	require.NoError(t, err)
+	assert.Equal(t, pending, result)
+
+	_, err = service.GetScheduledChanges(context.Background(), "BES0049", "done")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request: status must be one of")
+}
+
+func TestScheduledChangeService_CancelScheduledChange_InvalidID(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	service := NewScheduledChangeService(changes, new(MockBankRepository))
+
+	_, err := service.CancelScheduledChange(context.Background(), "BES0049", "not-a-uuid")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request: change ID must be a valid UUID")
+	changes.AssertNotCalled(t, "CancelScheduledChange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScheduledChangeService_ApplyDueChanges(t *testing.T) {
+	changes := new(MockScheduledChangeRepository)
+	service := NewScheduledChangeService(changes, new(MockBankRepository))
+
+	due := &models.ScheduledChange{ChangeID: uuid.New(), Status: models.ScheduledChangeActive}
+	changes.On("ApplyNextDueScheduledChange", mock.Anything, mock.Anything).Return(due, nil).Twice()
+	changes.On("ApplyNextDueScheduledChange", mock.Anything, mock.Anything).Return(nil, nil)
+
+	applied, err := service.ApplyDueChanges(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+
+	// The batch stops at the limit even when more changes are due
+	changes = new(MockScheduledChangeRepository)
+	service = NewScheduledChangeService(changes, new(MockBankRepository))
+	changes.On("ApplyNextDueScheduledChange", mock.Anything, mock.Anything).Return(due, nil)
+
+	applied, err = service.ApplyDueChanges(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	changes.AssertNumberOfCalls(t, "ApplyNextDueScheduledChange", 3)
+}
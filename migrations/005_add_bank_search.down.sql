@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_banks_real_name_trgm;
+DROP INDEX IF EXISTS idx_banks_name_trgm;
+DROP INDEX IF EXISTS idx_banks_search_vector;
+
+ALTER TABLE banks DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS jsonb_strings_text(JSONB);
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
+
+DROP EXTENSION IF EXISTS unaccent;
+DROP EXTENSION IF EXISTS pg_trgm;
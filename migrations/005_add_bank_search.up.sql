@@ -0,0 +1,28 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE; wrapping it with an explicit dictionary makes it usable in indexes
+CREATE OR REPLACE FUNCTION immutable_unaccent(input TEXT)
+RETURNS TEXT AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, input)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Concatenates every string value found in a JSONB document (arrays and nested objects included)
+CREATE OR REPLACE FUNCTION jsonb_strings_text(doc JSONB)
+RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(value #>> '{}', ' '), '')
+    FROM jsonb_path_query(COALESCE(doc, '{}'::jsonb), 'strict $.** ? (@.type() == "string")') AS value
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+ALTER TABLE banks ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', immutable_unaccent(COALESCE(name, ''))), 'A') ||
+    setweight(to_tsvector('simple', immutable_unaccent(COALESCE(real_name, ''))), 'A') ||
+    setweight(to_tsvector('simple', jsonb_strings_text(bank_codes)), 'B') ||
+    setweight(to_tsvector('simple', immutable_unaccent(jsonb_strings_text(keywords))), 'C')
+) STORED;
+
+CREATE INDEX idx_banks_search_vector ON banks USING GIN(search_vector);
+
+-- Trigram indexes for fuzzy (typo tolerant) matching on names
+CREATE INDEX idx_banks_name_trgm ON banks USING GIN(lower(immutable_unaccent(name)) gin_trgm_ops);
+CREATE INDEX idx_banks_real_name_trgm ON banks USING GIN(lower(immutable_unaccent(COALESCE(real_name, ''))) gin_trgm_ops);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_banks_updated_at;
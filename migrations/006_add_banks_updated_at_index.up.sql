@@ -0,0 +1,2 @@
+-- Supports the updated_since list filter
+CREATE INDEX idx_banks_updated_at ON banks(updated_at);
@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS record_bank_environment_configs_tombstone ON bank_environment_configs;
+DROP TRIGGER IF EXISTS record_banks_tombstone ON banks;
+DROP TRIGGER IF EXISTS record_bank_groups_tombstone ON bank_groups;
+DROP FUNCTION IF EXISTS record_catalog_tombstone();
+DROP TABLE IF EXISTS catalog_tombstones;
+
+DROP TRIGGER IF EXISTS update_bank_environment_configs_change_seq ON bank_environment_configs;
+DROP TRIGGER IF EXISTS update_banks_change_seq ON banks;
+DROP TRIGGER IF EXISTS update_bank_groups_change_seq ON bank_groups;
+DROP FUNCTION IF EXISTS update_change_seq_column();
+
+ALTER TABLE bank_environment_configs DROP COLUMN IF EXISTS change_seq;
+ALTER TABLE banks DROP COLUMN IF EXISTS change_seq;
+ALTER TABLE bank_groups DROP COLUMN IF EXISTS change_seq;
+
+DROP SEQUENCE IF EXISTS catalog_change_seq;
@@ -0,0 +1,69 @@
+-- Monotonic change sequence shared by every catalog table; delta sync tokens are positions in it
+CREATE SEQUENCE catalog_change_seq;
+
+ALTER TABLE bank_groups ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('catalog_change_seq');
+ALTER TABLE banks ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('catalog_change_seq');
+ALTER TABLE bank_environment_configs ADD COLUMN change_seq BIGINT NOT NULL DEFAULT nextval('catalog_change_seq');
+
+CREATE INDEX idx_bank_groups_change_seq ON bank_groups(change_seq);
+CREATE INDEX idx_banks_change_seq ON banks(change_seq);
+CREATE INDEX idx_bank_env_configs_change_seq ON bank_environment_configs(change_seq);
+
+CREATE OR REPLACE FUNCTION update_change_seq_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.change_seq = nextval('catalog_change_seq');
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_bank_groups_change_seq
+    BEFORE UPDATE ON bank_groups
+    FOR EACH ROW EXECUTE FUNCTION update_change_seq_column();
+
+CREATE TRIGGER update_banks_change_seq
+    BEFORE UPDATE ON banks
+    FOR EACH ROW EXECUTE FUNCTION update_change_seq_column();
+
+CREATE TRIGGER update_bank_environment_configs_change_seq
+    BEFORE UPDATE ON bank_environment_configs
+    FOR EACH ROW EXECUTE FUNCTION update_change_seq_column();
+
+-- Tombstones record hard deletions so that delta sync clients can remove their local copies
+CREATE TABLE catalog_tombstones (
+    change_seq BIGINT PRIMARY KEY DEFAULT nextval('catalog_change_seq'),
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    bank_id VARCHAR(255),
+    environment environment_type,
+    deleted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION record_catalog_tombstone()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'banks' THEN
+        INSERT INTO catalog_tombstones (entity_type, entity_id, bank_id)
+        VALUES ('bank', OLD.bank_id, OLD.bank_id);
+    ELSIF TG_TABLE_NAME = 'bank_environment_configs' THEN
+        INSERT INTO catalog_tombstones (entity_type, entity_id, bank_id, environment)
+        VALUES ('environment_config', OLD.bank_id || ':' || OLD.environment, OLD.bank_id, OLD.environment);
+    ELSIF TG_TABLE_NAME = 'bank_groups' THEN
+        INSERT INTO catalog_tombstones (entity_type, entity_id)
+        VALUES ('bank_group', OLD.group_id::text);
+    END IF;
+    RETURN OLD;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_bank_groups_tombstone
+    AFTER DELETE ON bank_groups
+    FOR EACH ROW EXECUTE FUNCTION record_catalog_tombstone();
+
+CREATE TRIGGER record_banks_tombstone
+    AFTER DELETE ON banks
+    FOR EACH ROW EXECUTE FUNCTION record_catalog_tombstone();
+
+CREATE TRIGGER record_bank_environment_configs_tombstone
+    AFTER DELETE ON bank_environment_configs
+    FOR EACH ROW EXECUTE FUNCTION record_catalog_tombstone();
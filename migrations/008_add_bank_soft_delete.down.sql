@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_banks_deleted_at;
+ALTER TABLE banks DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+-- Soft-deleted banks keep their rows (and environment configs) but are hidden from every read
+ALTER TABLE banks ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_banks_deleted_at ON banks(deleted_at) WHERE deleted_at IS NOT NULL;
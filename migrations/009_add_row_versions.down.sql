@@ -0,0 +1,3 @@
+ALTER TABLE bank_environment_configs DROP COLUMN IF EXISTS version;
+ALTER TABLE banks DROP COLUMN IF EXISTS version;
+ALTER TABLE bank_groups DROP COLUMN IF EXISTS version;
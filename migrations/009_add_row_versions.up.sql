@@ -0,0 +1,5 @@
+-- Row versions for optimistic concurrency: every write increments them and updates only apply
+-- when the stored version is still the one the writer read
+ALTER TABLE bank_groups ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE banks ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE bank_environment_configs ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,15 @@
+-- Audit trail of catalog writes: who made each one, in which request, and the entity before and after it.
+-- Snapshots are NULL when the entity did not exist (or was soft-deleted) on that side of the write.
+CREATE TABLE audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64),
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, audit_id);
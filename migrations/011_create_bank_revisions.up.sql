@@ -0,0 +1,10 @@
+-- Numbered snapshots of a bank, with its environment configs, after each of its writes. The revision
+-- number is the version of the bank after the write, so banks start collecting revisions on their
+-- first write after this migration.
+CREATE TABLE bank_revisions (
+    bank_id VARCHAR(255) NOT NULL,
+    revision BIGINT NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (bank_id, revision)
+);
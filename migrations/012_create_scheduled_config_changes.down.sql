@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_scheduled_config_changes_due_revert;
+DROP INDEX IF EXISTS idx_scheduled_config_changes_due_apply;
+DROP INDEX IF EXISTS idx_scheduled_config_changes_bank;
+DROP TABLE IF EXISTS scheduled_config_changes;
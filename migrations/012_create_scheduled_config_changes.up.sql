@@ -0,0 +1,24 @@
+-- Changes to an environment config scheduled for a later time. A change with revert_at is a window:
+-- the values it replaced are kept in previous and written back when the window ends.
+CREATE TABLE scheduled_config_changes (
+    change_id UUID PRIMARY KEY,
+    bank_id VARCHAR(255) NOT NULL REFERENCES banks(bank_id) ON DELETE CASCADE,
+    environment environment_type NOT NULL,
+    changes JSONB NOT NULL,
+    previous JSONB,
+    apply_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revert_at TIMESTAMP WITH TIME ZONE,
+    reason TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    error TEXT,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    applied_at TIMESTAMP WITH TIME ZONE,
+    reverted_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    CHECK (revert_at IS NULL OR revert_at > apply_at)
+);
+
+CREATE INDEX idx_scheduled_config_changes_bank ON scheduled_config_changes(bank_id, apply_at);
+CREATE INDEX idx_scheduled_config_changes_due_apply ON scheduled_config_changes(apply_at) WHERE status = 'pending';
+CREATE INDEX idx_scheduled_config_changes_due_revert ON scheduled_config_changes(revert_at) WHERE status = 'active';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_maintenance_windows_bank;
+DROP TABLE IF EXISTS maintenance_windows;
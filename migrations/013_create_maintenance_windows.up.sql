@@ -0,0 +1,23 @@
+-- Planned maintenance of a bank in an environment. A one-off window spans starts_at to ends_at; a
+-- recurring window starts whenever its cron schedule matches, in its timezone, and lasts duration_minutes.
+CREATE TABLE maintenance_windows (
+    window_id UUID PRIMARY KEY,
+    bank_id VARCHAR(255) NOT NULL REFERENCES banks(bank_id) ON DELETE CASCADE,
+    environment environment_type NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    schedule VARCHAR(255),
+    duration_minutes INTEGER,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    impact VARCHAR(16) NOT NULL,
+    reason TEXT,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (kind IN ('one_off', 'recurring')),
+    CHECK (impact IN ('blocked', 'degraded')),
+    CHECK (kind <> 'one_off' OR (starts_at IS NOT NULL AND ends_at > starts_at)),
+    CHECK (kind <> 'recurring' OR (schedule IS NOT NULL AND duration_minutes > 0))
+);
+
+CREATE INDEX idx_maintenance_windows_bank ON maintenance_windows(bank_id, environment);
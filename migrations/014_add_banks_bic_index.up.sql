@@ -0,0 +1,4 @@
+-- Supports GET /api/banks/lookup?bic= (GetBanksByBIC), which matches on the first 8 characters of the BIC.
+-- IBAN resolution goes through the bank codes instead and does not use this index.
+-- The expression must stay identical to the one in GetBanksByBIC for the planner to use the index.
+CREATE INDEX idx_banks_bic8 ON banks(LEFT(UPPER(bic), 8)) WHERE deleted_at IS NULL;
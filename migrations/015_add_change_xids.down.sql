@@ -0,0 +1,21 @@
+CREATE OR REPLACE FUNCTION update_change_seq_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.change_seq = nextval('catalog_change_seq');
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP INDEX IF EXISTS idx_catalog_tombstones_change_position;
+DROP INDEX IF EXISTS idx_bank_env_configs_change_position;
+DROP INDEX IF EXISTS idx_banks_change_position;
+DROP INDEX IF EXISTS idx_bank_groups_change_position;
+
+CREATE INDEX idx_bank_groups_change_seq ON bank_groups(change_seq);
+CREATE INDEX idx_banks_change_seq ON banks(change_seq);
+CREATE INDEX idx_bank_env_configs_change_seq ON bank_environment_configs(change_seq);
+
+ALTER TABLE catalog_tombstones DROP COLUMN IF EXISTS change_xid;
+ALTER TABLE bank_environment_configs DROP COLUMN IF EXISTS change_xid;
+ALTER TABLE banks DROP COLUMN IF EXISTS change_xid;
+ALTER TABLE bank_groups DROP COLUMN IF EXISTS change_xid;
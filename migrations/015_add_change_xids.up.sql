@@ -0,0 +1,31 @@
+-- A change sequence value is taken when a row is written, not when its transaction commits, so a
+-- reader can see seq 6 while seq 5 is still in flight. Delta sync therefore orders changes by the
+-- transaction that wrote them, then by sequence, and only reads up to the oldest transaction in flight.
+-- Rows written before this migration keep xid 0, which preserves their order and existing tokens.
+ALTER TABLE bank_groups ADD COLUMN change_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE banks ADD COLUMN change_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE bank_environment_configs ADD COLUMN change_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE catalog_tombstones ADD COLUMN change_xid xid8 NOT NULL DEFAULT '0';
+
+ALTER TABLE bank_groups ALTER COLUMN change_xid SET DEFAULT pg_current_xact_id();
+ALTER TABLE banks ALTER COLUMN change_xid SET DEFAULT pg_current_xact_id();
+ALTER TABLE bank_environment_configs ALTER COLUMN change_xid SET DEFAULT pg_current_xact_id();
+ALTER TABLE catalog_tombstones ALTER COLUMN change_xid SET DEFAULT pg_current_xact_id();
+
+DROP INDEX IF EXISTS idx_bank_groups_change_seq;
+DROP INDEX IF EXISTS idx_banks_change_seq;
+DROP INDEX IF EXISTS idx_bank_env_configs_change_seq;
+
+CREATE INDEX idx_bank_groups_change_position ON bank_groups(change_xid, change_seq);
+CREATE INDEX idx_banks_change_position ON banks(change_xid, change_seq);
+CREATE INDEX idx_bank_env_configs_change_position ON bank_environment_configs(change_xid, change_seq);
+CREATE INDEX idx_catalog_tombstones_change_position ON catalog_tombstones(change_xid, change_seq);
+
+CREATE OR REPLACE FUNCTION update_change_seq_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.change_seq = nextval('catalog_change_seq');
+    NEW.change_xid = pg_current_xact_id();
+    RETURN NEW;
+END;
+$$ language 'plpgsql';